@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/config"
 	"github.com/aasumitro/tix/docs"
@@ -8,6 +10,12 @@ import (
 	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/spf13/viper"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 //	@version                     1.0
@@ -49,20 +57,65 @@ func main() {
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 	// INIT GOOGLE FORM SERVICE
 	config.Instance.InitGoogleFormConn()
+	// INIT WALLET PASS SIGNER (OPTIONAL)
+	config.Instance.InitWalletPassSigner()
+	// INIT EXPORT FILE STORE
+	config.Instance.InitFileStore()
 
 	// PRINT RUNNING LOG
 	log.Printf("Run %s(%s)",
 		config.Instance.AppName,
 		common.Version)
 
-	// BOOTSTRAP APP
-	internal.RunApp(
+	// BOOTSTRAP APP, THE WORKER ROLE RUN FROM cmd/worker
+	role := common.AppRole(config.Instance.AppRole)
+	if role == common.AppRoleWorker {
+		log.Fatalln("APP_ROLE worker is served by cmd/worker")
+	}
+	shutdown := internal.RunApp(
+		internal.WithRole(role),
 		internal.WithEngine(config.Engine),
 		internal.WithPostgreDatabase(config.Postgre),
 		internal.WithRedisCache(config.Redis),
 		internal.WithMailer(config.Mailer),
-		internal.WithGoogleFormService(config.GoogleForm))
+		internal.WithGoogleFormService(config.GoogleForm),
+		internal.WithWalletPassSigner(config.WalletPass),
+		internal.WithFileStore(config.FileStore))
+
+	// RUN SERVER, THE REQUEST CONTEXT IS CANCELED ON SHUTDOWN
+	// SO THE LONG-LIVED STREAMS (SSE) ARE CLOSED TOO
+	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:              config.Instance.AppURL,
+		Handler:           config.Engine,
+		ReadHeaderTimeout: common.ContextTimeout * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	server.RegisterOnShutdown(cancelBaseCtx)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalln(err)
+		}
+	}()
+
+	// WAIT FOR THE TERMINATION SIGNAL
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server . . . .")
+
+	// STOP ACCEPTING REQUEST THEN LET THE RUNNING JOBS FINISH
+	ctx, cancel := context.WithTimeout(context.Background(), common.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("SERVER_SHUTDOWN_ERROR: %s", err.Error())
+	}
+	if err := shutdown(ctx); err != nil {
+		log.Printf("APP_SHUTDOWN_ERROR: %s", err.Error())
+	}
 
-	// RUN SERVER
-	log.Fatalln(config.Engine.Run(config.Instance.AppURL))
+	// CLOSE CONNECTION (THE MAILER DIAL PER EMAIL, NOTHING TO CLOSE)
+	config.Instance.CloseRedisConn()
+	config.Instance.ClosePostgresConn()
+	log.Println("Server stopped")
 }
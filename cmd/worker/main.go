@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/config"
+	"github.com/aasumitro/tix/internal"
+	"github.com/spf13/viper"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// set config file
+	viper.SetConfigFile(".env")
+	// LOAD APP ENV
+	config.LoadEnv()
+	// INIT LANDLORD/MAIN DATABASE FOR DATA STORE
+	config.Instance.InitPostgresConn()
+	// INIT REDIS CONNECTION FOR DATA CACHE AND QUEUE
+	config.Instance.InitRedisConn()
+	// INIT MAILER CONNECTION FOR EMAIL NOTIFICATION
+	config.Instance.InitMailerConn()
+	// INIT SENTRY
+	if !config.Instance.AppDebug {
+		config.Instance.InitSentryConn()
+	}
+	// INIT GOOGLE FORM SERVICE
+	config.Instance.InitGoogleFormConn()
+	// INIT WALLET PASS SIGNER (OPTIONAL)
+	config.Instance.InitWalletPassSigner()
+	// INIT EXPORT FILE STORE
+	config.Instance.InitFileStore()
+
+	// PRINT RUNNING LOG
+	log.Printf("Run %s(%s) worker",
+		config.Instance.AppName,
+		common.Version)
+
+	// BOOTSTRAP WORKER (QUEUE CONSUMERS AND SCHEDULED JOBS)
+	shutdown := internal.RunApp(
+		internal.WithRole(common.AppRoleWorker),
+		internal.WithPostgreDatabase(config.Postgre),
+		internal.WithRedisCache(config.Redis),
+		internal.WithMailer(config.Mailer),
+		internal.WithGoogleFormService(config.GoogleForm),
+		internal.WithWalletPassSigner(config.WalletPass),
+		internal.WithFileStore(config.FileStore))
+
+	// WAIT FOR THE TERMINATION SIGNAL
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down worker . . . .")
+
+	// STOP TAKING NEW JOBS THEN LET THE RUNNING JOBS FINISH
+	ctx, cancel := context.WithTimeout(context.Background(), common.ShutdownTimeout)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.Printf("APP_SHUTDOWN_ERROR: %s", err.Error())
+	}
+
+	// CLOSE CONNECTION (THE MAILER DIAL PER EMAIL, NOTHING TO CLOSE)
+	config.Instance.CloseRedisConn()
+	config.Instance.ClosePostgresConn()
+	log.Println("Worker stopped")
+}
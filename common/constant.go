@@ -12,6 +12,44 @@ const (
 	GoogleFormCacheTimeDuration       = time.Hour * 24
 
 	LastWeekDay = 7
+
+	GoogleFormResponsesPageSize = 5000
+
+	QueueStreamMaxLen     = 10000
+	QueueReadCount        = 10
+	QueueReadBlockTime    = 5 * time.Second
+	QueueClaimMinIdleTime = 5 * time.Minute
+	// QueueClaimRenewPerIdle is how many times the running job is claimed
+	// again within QueueClaimMinIdleTime, so it never looks idle.
+	QueueClaimRenewPerIdle = 3
+	QueueErrorWaitDuration = 3 * time.Second
+	QueueMaxAttempts       = 5
+	QueueInitialBackoff    = 30 * time.Second
+	QueueMaxBackoff        = 30 * time.Minute
+
+	JobHistoryLimit = 50
+
+	DeadLetterPageSize    = 20
+	DeadLetterMaxPageSize = 100
+
+	EventStreamHeartbeatTime = 15 * time.Second
+	EventStreamBufferSize    = 10
+
+	SchedulerLeaderLockTTL = 30 * time.Second
+
+	ShutdownTimeout = 30 * time.Second
+
+	BrandingLogoFetchTimeout = 10 * time.Second
+	// BrandingLogoCacheTTL keep the downloaded logo, so the bulk tickets
+	// and the public ticket download do not fetch it for every ticket.
+	BrandingLogoCacheTTL = 10 * time.Minute
+
+	// TicketTokenGracePeriod keep the ticket token valid after the event date.
+	TicketTokenGracePeriod = 24 * time.Hour
+
+	// ExportLinkExpiry is how long the download link of the archived export
+	// sent by email is valid (the longest expiry of the S3 signed url).
+	ExportLinkExpiry = 7 * 24 * time.Hour
 )
 
 const (
@@ -29,10 +67,74 @@ const (
 
 	EmptyPath = ""
 
-	AutoSyncEventKey        = "event_auto_sync"
-	ReqSyncEventQueueKey    = "req_sync_event_queue"
-	ReqGenEventTixQueueKey  = "req_gen_event_tix_queue"
-	ReqExpEventDataQueueKey = "req_exp_event_data_queue"
+	ExportTempDir = "temps/exports"
+	// ExportArchiveDir is the directory of the local file store keeping the exports
+	ExportArchiveDir = "temps/archives"
+	// ExportArchivePrefix is the key prefix of the exports in the file store
+	ExportArchivePrefix = "exports"
+	// ExportAttachmentMaxSize is the largest export (in bytes) attached to the
+	// email, the larger one is sent as a download link, see ExportLinkExpiry.
+	ExportAttachmentMaxSize = 10 << 20
+	// ExportDownloadKeyID derive the key signing the export download links
+	ExportDownloadKeyID = "export-download"
+	// ExportFileMode is the permission of the generated files (owner only)
+	ExportFileMode = 0o600
+	// ExportDownloadMaxParticipants is the largest event exported by the
+	// download, the larger one is only exported by email.
+	ExportDownloadMaxParticipants = 5000
+
+	CalendarUIDDomain = "tix.bakode.xyz"
+
+	// the default branding, used by the event that does not customise it
+	DefaultBrandingHeaderTitle    = "TIX"
+	DefaultBrandingHeaderSubtitle = "Manage Events Participants and Tickets"
+	DefaultBrandingFooterText     = "tix.bakode.xyz"
+	DefaultBrandingWebsiteURL     = "https://tix.bakode.xyz/"
+	DefaultBrandingLogoURL        = "https://avatars.githubusercontent.com/u/105574217?s=400&u=81ba732eec2ca291da7654906168eb38a391ea22&v=4"
+	DefaultBrandingSenderName     = "BAKODE SUPPORT"
+	DefaultBrandingSenderEmail    = "support@bakode.xyz"
+	DefaultBrandingPrimaryColor   = "#000000"
+	DefaultBrandingSecondaryColor = "#640000"
+
+	// BrandingLogoMaxSize is the max size (bytes) of the logo embedded into the files
+	BrandingLogoMaxSize = 1 << 20
+
+	AutoSyncEventKey           = "event_auto_sync"
+	ReqSyncEventQueueKey       = "req_sync_event_queue"
+	ReqGenEventTixQueueKey     = "req_gen_event_tix_queue"
+	ReqExpEventDataQueueKey    = "req_exp_event_data_queue"
+	ReqGenEventBulkTixQueueKey = "req_gen_event_bulk_tix_queue"
+
+	QueueConsumerGroup = "tix_workers"
+	QueueDelayedKey    = "queue_delayed_jobs"
+	QueueDeadLetterKey = "queue_dead_letter_jobs"
+	QueuePayloadField  = "payload"
+	QueueAttemptField  = "attempt"
+	QueueNameField     = "queue"
+	QueueErrorField    = "error"
+	QueueFailedAtField = "failed_at"
+
+	EventNotificationChannelKey = "event_notification"
+	SchedulerLeaderLockKey      = "scheduler_leader_lock"
+)
+
+// AppRole decide what the running instance do, the web role serve the http api
+// while the worker role consume the queued jobs and run the scheduled jobs.
+type AppRole string
+
+const (
+	AppRoleWeb    AppRole = "web"
+	AppRoleWorker AppRole = "worker"
+	AppRoleAll    AppRole = "all"
+)
+
+// FileStoreDriver is where the exports are archived, the local directory
+// (only shared by the instances mounting it) or an S3 compatible bucket.
+type FileStoreDriver string
+
+const (
+	FileStoreLocal FileStoreDriver = "local"
+	FileStoreS3    FileStoreDriver = "s3"
 )
 
 type EventParticipantStatus string
@@ -42,19 +144,161 @@ const (
 	ParticipantRequestApproved EventParticipantStatus = "approved"
 	ParticipantRequestDeclined EventParticipantStatus = "declined"
 	ParticipantRequestWaiting  EventParticipantStatus = "waiting"
+	ParticipantCheckedIn       EventParticipantStatus = "checked_in"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// TicketDeliveryStatus is the ticket delivery progress of
+// a participant within the bulk ticket job.
+type TicketDeliveryStatus string
+
+const (
+	TicketDeliveryQueued TicketDeliveryStatus = "queued"
+	TicketDeliverySent   TicketDeliveryStatus = "sent"
+	TicketDeliveryFailed TicketDeliveryStatus = "failed"
+)
+
+// TicketRecipientFilter choose the participants of the bulk ticket job.
+type TicketRecipientFilter string
+
+const (
+	// TicketRecipientApproved send the ticket to every approved participant
+	TicketRecipientApproved TicketRecipientFilter = "approved"
+	// TicketRecipientUnsent only send the ticket to the approved participant
+	// that has never been sent one
+	TicketRecipientUnsent TicketRecipientFilter = "unsent"
 )
 
+// CheckInStatus is the result of an offline gate check-in.
+type CheckInStatus string
+
+const (
+	CheckInRecorded  CheckInStatus = "checked_in"
+	CheckInDuplicate CheckInStatus = "duplicate"
+	CheckInRejected  CheckInStatus = "rejected"
+)
+
+type EventNotificationType string
+
+const (
+	NotificationParticipantsSynced       EventNotificationType = "participants_synced"
+	NotificationParticipantStatusUpdated EventNotificationType = "participant_status_updated"
+	NotificationJobFinished              EventNotificationType = "job_finished"
+	NotificationParticipantCheckedIn     EventNotificationType = "participant_checked_in"
+	NotificationTicketDelivered          EventNotificationType = "ticket_delivered"
+)
+
+type ParticipantField string
+
+const (
+	ParticipantFieldName  ParticipantField = "name"
+	ParticipantFieldEmail ParticipantField = "email"
+	ParticipantFieldPhone ParticipantField = "phone"
+	ParticipantFieldJob   ParticipantField = "job"
+	ParticipantFieldDoB   ParticipantField = "dob"
+	ParticipantFieldPoP   ParticipantField = "pop"
+)
+
+// ParticipantFields list every participant field that can be bound to a google form question
+var ParticipantFields = []ParticipantField{
+	ParticipantFieldName,
+	ParticipantFieldEmail,
+	ParticipantFieldPhone,
+	ParticipantFieldJob,
+	ParticipantFieldDoB,
+	ParticipantFieldPoP,
+}
+
+// ParticipantFieldTitleHints used to suggest the participant field
+// from the normalized google form question title (lowercase, snake_case)
+var ParticipantFieldTitleHints = map[string]ParticipantField{
+	"nama":             ParticipantFieldName,
+	"nama_lengkap":     ParticipantFieldName,
+	"name":             ParticipantFieldName,
+	"full_name":        ParticipantFieldName,
+	"email":            ParticipantFieldEmail,
+	"email_address":    ParticipantFieldEmail,
+	"nomor_telepon":    ParticipantFieldPhone,
+	"phone":            ParticipantFieldPhone,
+	"phone_number":     ParticipantFieldPhone,
+	"pekerjaan":        ParticipantFieldJob,
+	"job":              ParticipantFieldJob,
+	"occupation":       ParticipantFieldJob,
+	"tanggal_lahir":    ParticipantFieldDoB,
+	"date_of_birth":    ParticipantFieldDoB,
+	"birth_date":       ParticipantFieldDoB,
+	"bukti_transfer":   ParticipantFieldPoP,
+	"proof_of_payment": ParticipantFieldPoP,
+	"payment_proof":    ParticipantFieldPoP,
+}
+
 type EventExportType string
 
 const (
-	ExportTypePDF EventExportType = "pdf"
-	ExportTypeXLS EventExportType = "xls"
+	ExportTypePDF    EventExportType = "pdf"
+	ExportTypeXLS    EventExportType = "xls"
+	ExportTypeCSV    EventExportType = "csv"
+	ExportTypeJSON   EventExportType = "json"
+	ExportTypeNDJSON EventExportType = "ndjson"
 )
 
+// EventExportTypes list every export type that has an exporter
+var EventExportTypes = []EventExportType{
+	ExportTypePDF,
+	ExportTypeXLS,
+	ExportTypeCSV,
+	ExportTypeJSON,
+	ExportTypeNDJSON,
+}
+
+type EventExportColumn string
+
+const (
+	ExportColumnNo             EventExportColumn = "no"
+	ExportColumnName           EventExportColumn = "name"
+	ExportColumnEmail          EventExportColumn = "email"
+	ExportColumnPhone          EventExportColumn = "phone"
+	ExportColumnJob            EventExportColumn = "job"
+	ExportColumnDoB            EventExportColumn = "dob"
+	ExportColumnApproved       EventExportColumn = "approved"
+	ExportColumnDeclined       EventExportColumn = "declined"
+	ExportColumnDeclinedReason EventExportColumn = "declined_reason"
+	ExportColumnCheckedIn      EventExportColumn = "checked_in"
+	ExportColumnStatus         EventExportColumn = "status"
+	// ExportColumnAnswers is every custom answer of the google form
+	ExportColumnAnswers EventExportColumn = "answers"
+)
+
+// EventExportColumns list every participant column the export can choose,
+// the json export write them all when the columns are not chosen.
+var EventExportColumns = []EventExportColumn{
+	ExportColumnNo,
+	ExportColumnName,
+	ExportColumnEmail,
+	ExportColumnPhone,
+	ExportColumnJob,
+	ExportColumnDoB,
+	ExportColumnApproved,
+	ExportColumnDeclined,
+	ExportColumnDeclinedReason,
+	ExportColumnCheckedIn,
+	ExportColumnStatus,
+	ExportColumnAnswers,
+}
+
 const (
-	MsgWaitGenTix = "Please wait a moment while we send the generated ticket to the intended recipient."
-	MsgWaitSync   = "Please wait a moment while we sync the event data."
-	MsgWaitExport = `
+	MsgWaitGenTix     = "Please wait a moment while we send the generated ticket to the intended recipient."
+	MsgWaitGenBulkTix = "Please wait a moment while we send the generated tickets to the participants."
+	MsgWaitSync       = "Please wait a moment while we sync the event data."
+	MsgWaitExport     = `
 		Please wait a moment while we export the event data and send it to your email. 
 		Please check your email periodically within 1-3 minutes after making these request.
     `
@@ -73,17 +317,34 @@ const (
 	ExcelTitleHeight     = 40
 	ExcelSubtitleHeight  = 30
 	ExcelTableStartIndex = 11
+	ExcelFooterRowOffset = 2
+	// ExcelEventDataStartIndex is the row of the first event data (name, location, ...)
+	ExcelEventDataStartIndex = 5
+	// ExcelMinColWidth is the default width of the excel column
+	ExcelMinColWidth = 9.140625
+	// ExcelColWidthSampleSize is the participant rows (after the table
+	// header) read to size the excel columns, the later rows do not widen them.
+	ExcelColWidthSampleSize = 100
+	// ExcelStreamThreshold is the participants count above which the
+	// spreadsheet is streamed, the streamed sheet has no branding logo.
+	ExcelStreamThreshold = 1000
+	// ExcelLogoHeight is the height (pixels) of the logo next to the title
+	ExcelLogoHeight = 48
 )
 
 const (
-	PdfMarginLeft        = 20
-	PdfMarginRight       = 20
-	PdfMarginTop         = 10
-	PdfHeaderRowHeight   = 20
-	PdfHeaderColWidth    = 12
-	PdfTitleSize         = 18
-	PdfSubtitleMarginTop = 9
-	PdfSubtitleSize      = 14
+	PdfMarginLeft         = 20
+	PdfMarginRight        = 20
+	PdfMarginTop          = 10
+	PdfHeaderRowHeight    = 20
+	PdfHeaderColWidth     = 12
+	PdfHeaderLogoColWidth = 2
+	PdfHeaderLogoPercent  = 90
+	// PdfHeaderLogoTitleColWidth is the title width when the logo is drawn
+	PdfHeaderLogoTitleColWidth = 8
+	PdfTitleSize               = 18
+	PdfSubtitleMarginTop       = 9
+	PdfSubtitleSize            = 14
 
 	PdfLineSpaceHeight = 1.0
 	PdfLineWidth       = 0.5
@@ -97,7 +358,87 @@ const (
 	PdfTableTitleRowWidth  = 12
 	PdfTableTitleSize      = 12
 	PdfTableTitleMarginTop = 8
+	PdfTableMaxColumns     = 12
 
 	PdfFooterTitleSize      = 8
 	PdfFooterTitleMarginTop = 12
+
+	PdfTicketRowHeight        = 60
+	PdfTicketDetailColWidth   = 8
+	PdfTicketQrCodeColWidth   = 4
+	PdfTicketQrCodePercent    = 90
+	PdfTicketEventNameSize    = 16
+	PdfTicketDetailSize       = 11
+	PdfTicketDetailMarginTop  = 14
+	PdfTicketDetailLineHeight = 8
+	PdfTicketBarcodeRowHeight = 20
+	PdfTicketBarcodeColWidth  = 12
+	PdfTicketBarcodePercent   = 60
+	PdfTicketCodeRowHeight    = 8
+	PdfTicketCodeSize         = 10
+	PdfTicketFooterRowHeight  = 12
+
+	PdfTicketTemplateTitleHeight    = 10
+	PdfTicketTemplateSubtitleHeight = 8
+	PdfTicketTemplateHeadingHeight  = 10
+	PdfTicketTemplateSpaceHeight    = 4
+	PdfTicketTemplateLogoHeight     = 20
+	PdfTicketTemplateQrCodeHeight   = 50
 )
+
+// TicketTemplateMaxLines is the max lines of the ticket layout,
+// it keep a wrong template from drawing endless pages.
+const TicketTemplateMaxLines = 200
+
+// the directives of the ticket template, each is placed on its own line
+const (
+	TicketTemplateQRCode  = "@qrcode"
+	TicketTemplateBarcode = "@barcode"
+	TicketTemplateLogo    = "@logo"
+)
+
+// DefaultTicketTemplate is the built-in ticket layout written as a ticket
+// template, the admin start from it to write the event ticket template.
+// A line starting with "# ", "## " or "### " is a title, a subtitle or a
+// heading, "---" draw a line, "> " is a centered note, "**text**" is bold
+// and an empty line add a space. {{ .QRCode }}, {{ .Barcode }} and
+// {{ .Logo }} draw the QR code, the barcode and the logo on their line.
+const DefaultTicketTemplate = `# {{ .Branding.HeaderTitle }}
+## {{ .Branding.HeaderSubtitle }}
+---
+### {{ .Event.Name }}
+Attendee : {{ .Participant.Name }}
+Email : {{ .Participant.Email }}
+Date : {{ .Event.Date }}
+Location : {{ .Event.Location }}
+{{ .QRCode }}
+---
+{{ .Barcode }}
+> {{ .Branding.FooterText }}`
+
+// TicketTemplatePlaceholders list the values that can be used in the ticket template
+var TicketTemplatePlaceholders = []string{
+	"{{ .Event.Name }}",
+	"{{ .Event.Location }}",
+	"{{ .Event.Date }}",
+	"{{ .Participant.Name }}",
+	"{{ .Participant.Email }}",
+	"{{ .Participant.Phone }}",
+	"{{ .Participant.Job }}",
+	`{{ index .Participant.Answers "question" }}`,
+	"{{ .TicketCode }}",
+	"{{ .QRCode }}",
+	"{{ .Barcode }}",
+	"{{ .Logo }}",
+	"{{ .Branding.HeaderTitle }}",
+	"{{ .Branding.HeaderSubtitle }}",
+	"{{ .Branding.FooterText }}",
+	"{{ .Branding.WebsiteURL }}",
+}
+
+// TicketCodeLength is the number of random bytes of a ticket code,
+// encoded (base32) into 16 characters.
+const TicketCodeLength = 10
+
+// TicketMessageIDLength is the number of random bytes of the ticket email Message-ID.
+const TicketMessageIDLength = 16
@@ -7,4 +7,66 @@ var (
 	ErrUserAlreadyInvited      = errors.New("user with the given email has already been invited. Please give instruction to check their email to continue using this application")
 	ErrRateLimitingPushQueue   = errors.New("you can make this request once every minute")
 	ErrDeclineReasonNotProvide = errors.New("please provide decline status")
+	ErrUnknownParticipantField = errors.New("field mapping contains an unknown participant field")
+	ErrDuplicateFieldMapping   = errors.New("each participant field can only be bound to one question")
+	ErrUnknownFormQuestion     = errors.New("field mapping contains a question that does not exist on the google form")
+	ErrUnrecoverableJob        = errors.New("job can not be processed and will not be retried")
+	ErrDeadLetterJobNotFound   = errors.New("dead-letter job with given id is not found")
+	ErrJobNotFound             = errors.New("job with given id is not found")
+	ErrParticipantNotApproved  = errors.New("ticket is only available for approved participant")
+	ErrTicketSigningDisabled   = errors.New("ticket signing secret is not configured")
+	ErrWalletPassDisabled      = errors.New("wallet pass certificate is not configured")
+	ErrInvalidBrandingColor    = errors.New("branding color must be a hex color, e.g. #1A2B3C")
+	ErrInvalidTicketTemplate   = errors.New("ticket template is not valid")
+	ErrTicketDeliveryFailed    = errors.New("some tickets could not be sent")
+	ErrUnsupportedExportType   = errors.New("export type is not supported, use one of pdf, xls, csv, json or ndjson")
+	ErrUnsupportedExportFormat = errors.New("export download is only available as csv, xlsx or pdf")
+	ErrExportTooLarge          = errors.New("event has too many participants to download, request the export by email instead")
+	ErrExportNotFound          = errors.New("export is not found, it may have been removed from the archive")
+	ErrExportLinkInvalid       = errors.New("export download link is not valid")
+	ErrExportLinkExpired       = errors.New("export download link is expired, request the export again")
+	ErrUnsupportedExportColumn = errors.New("export column is not supported, use the comma separated " +
+		"no, name, email, phone, job, dob, approved, declined, declined_reason, checked_in, status or answers")
+)
+
+// CheckInError is a rejected ticket check-in, the code let
+// the gate scanner tell the rejection reason apart.
+type CheckInError struct {
+	Code    string
+	Message string
+}
+
+func (err *CheckInError) Error() string {
+	return err.Message
+}
+
+var (
+	ErrTicketNotFound = &CheckInError{
+		Code:    "TICKET_NOT_FOUND",
+		Message: "ticket with given code is not found",
+	}
+	ErrTicketWrongEvent = &CheckInError{
+		Code:    "TICKET_WRONG_EVENT",
+		Message: "ticket with given code is issued for another event",
+	}
+	ErrTicketDeclined = &CheckInError{
+		Code:    "TICKET_DECLINED",
+		Message: "ticket with given code belongs to a declined participant",
+	}
+	ErrTicketNotApproved = &CheckInError{
+		Code:    "TICKET_NOT_APPROVED",
+		Message: "ticket with given code belongs to a participant waiting for approval",
+	}
+	ErrTicketAlreadyCheckedIn = &CheckInError{
+		Code:    "TICKET_ALREADY_CHECKED_IN",
+		Message: "ticket with given code has already been checked in",
+	}
+	ErrTicketInvalid = &CheckInError{
+		Code:    "TICKET_INVALID",
+		Message: "ticket token signature is not valid",
+	}
+	ErrTicketExpired = &CheckInError{
+		Code:    "TICKET_EXPIRED",
+		Message: "ticket token is expired",
+	}
 )
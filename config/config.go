@@ -3,6 +3,8 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/aasumitro/tix/pkg/wallet"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
@@ -19,6 +21,8 @@ var (
 	mailerSingleton      sync.Once
 	engineSingleton      sync.Once
 	googleFormsSingleton sync.Once
+	walletPassSingleton  sync.Once
+	fileStoreSingleton   sync.Once
 
 	Instance   *Config
 	Postgre    *sql.DB
@@ -26,6 +30,8 @@ var (
 	Mailer     *gomail.Dialer
 	Engine     *gin.Engine
 	GoogleForm *forms.Service
+	WalletPass *wallet.Signer
+	FileStore  storage.FileStore
 )
 
 type Config struct {
@@ -33,6 +39,8 @@ type Config struct {
 	AppDescription string `mapstructure:"APP_DESC"`
 	AppDebug       bool   `mapstructure:"APP_DEBUG"`
 	AppURL         string `mapstructure:"APP_URL"`
+	AppRole        string `mapstructure:"APP_ROLE"`
+	AppPublicURL   string `mapstructure:"APP_PUBLIC_URL"`
 
 	SupabaseProjectURL string `mapstructure:"SUPABASE_PROJECT_URL"`
 	SupabaseAPIKey     string `mapstructure:"SUPABASE_API_KEY"`
@@ -50,6 +58,27 @@ type Config struct {
 	MailPassword string `mapstructure:"MAIL_PASSWORD"`
 
 	GoogleCredentialPath string `mapstructure:"GOOGLE_CREDENTIAL_PATH"`
+
+	SyncResetStatusOnEdit bool `mapstructure:"SYNC_RESET_STATUS_ON_EDIT"`
+
+	TicketSigningSecret string `mapstructure:"TICKET_SIGNING_SECRET"`
+
+	WalletPassTypeID           string `mapstructure:"WALLET_PASS_TYPE_ID"`
+	WalletPassTeamID           string `mapstructure:"WALLET_PASS_TEAM_ID"`
+	WalletPassCertificatePath  string `mapstructure:"WALLET_PASS_CERTIFICATE_PATH"`
+	WalletPassPrivateKeyPath   string `mapstructure:"WALLET_PASS_PRIVATE_KEY_PATH"`
+	WalletPassIntermediatePath string `mapstructure:"WALLET_PASS_INTERMEDIATE_PATH"`
+
+	FileStoreDriver         string `mapstructure:"FILE_STORE_DRIVER"`
+	FileStoreLocalDir       string `mapstructure:"FILE_STORE_LOCAL_DIR"`
+	FileStoreS3Endpoint     string `mapstructure:"FILE_STORE_S3_ENDPOINT"`
+	FileStoreS3Region       string `mapstructure:"FILE_STORE_S3_REGION"`
+	FileStoreS3Bucket       string `mapstructure:"FILE_STORE_S3_BUCKET"`
+	FileStoreS3AccessKey    string `mapstructure:"FILE_STORE_S3_ACCESS_KEY"`
+	FileStoreS3SecretKey    string `mapstructure:"FILE_STORE_S3_SECRET_KEY"`
+	FileStoreS3PathStyle    bool   `mapstructure:"FILE_STORE_S3_PATH_STYLE"`
+	FileStoreS3Timeout      int    `mapstructure:"FILE_STORE_S3_TIMEOUT"`
+	ExportAttachmentMaxSize int64  `mapstructure:"EXPORT_ATTACHMENT_MAX_SIZE"`
 }
 
 func LoadEnv() {
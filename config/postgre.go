@@ -28,3 +28,14 @@ func (cfg *Config) InitPostgresConn() {
 		log.Println("Database connection pool created with postgres driver . . . .")
 	})
 }
+
+func (cfg *Config) ClosePostgresConn() {
+	if Postgre == nil {
+		return
+	}
+	if err := Postgre.Close(); err != nil {
+		log.Printf("DATABASE_ERROR: %s", err.Error())
+		return
+	}
+	log.Println("Database connection pool closed . . . .")
+}
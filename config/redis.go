@@ -21,3 +21,14 @@ func (cfg *Config) InitRedisConn() {
 		log.Println("Redis connection pool created . . . .")
 	})
 }
+
+func (cfg *Config) CloseRedisConn() {
+	if Redis == nil {
+		return
+	}
+	if err := Redis.Close(); err != nil {
+		log.Printf("REDIS_ERROR: %s", err.Error())
+		return
+	}
+	log.Println("Redis connection pool closed . . . .")
+}
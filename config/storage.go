@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/pkg/storage"
+	"log"
+	"strings"
+	"time"
+)
+
+// InitFileStore open the file store archiving the exports, the local
+// directory is used when no driver is configured.
+func (cfg *Config) InitFileStore() {
+	log.Println("Trying to open export file store . . . .")
+	fileStoreSingleton.Do(func() {
+		switch common.FileStoreDriver(strings.ToLower(cfg.FileStoreDriver)) {
+		case "", common.FileStoreLocal:
+			dir := cfg.FileStoreLocalDir
+			if dir == "" {
+				dir = common.ExportArchiveDir
+			}
+			FileStore = storage.NewLocalFileStore(dir)
+		case common.FileStoreS3:
+			store, err := storage.NewS3FileStore(storage.S3Config{
+				Endpoint:  cfg.FileStoreS3Endpoint,
+				Region:    cfg.FileStoreS3Region,
+				Bucket:    cfg.FileStoreS3Bucket,
+				AccessKey: cfg.FileStoreS3AccessKey,
+				SecretKey: cfg.FileStoreS3SecretKey,
+				PathStyle: cfg.FileStoreS3PathStyle,
+				Timeout:   time.Duration(cfg.FileStoreS3Timeout) * time.Second,
+			})
+			if err != nil {
+				panic(fmt.Sprintf("FILE_STORE_ERROR: %s", err.Error()))
+			}
+			FileStore = store
+		default:
+			panic(fmt.Sprintf("FILE_STORE_ERROR: unknown driver %q, use local or s3",
+				cfg.FileStoreDriver))
+		}
+		log.Println("Export file store opened . . . .")
+	})
+}
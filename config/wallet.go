@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"github.com/aasumitro/tix/pkg/wallet"
+	"log"
+)
+
+// InitWalletPassSigner load the pass type certificate, the wallet pass
+// is not generated when the certificate is not configured.
+func (cfg *Config) InitWalletPassSigner() {
+	if cfg.WalletPassCertificatePath == "" {
+		log.Println("Wallet pass certificate is not configured, skip . . . .")
+		return
+	}
+	log.Println("Trying to load wallet pass certificate . . . .")
+	walletPassSingleton.Do(func() {
+		signer, err := wallet.LoadSigner(
+			cfg.WalletPassTypeID,
+			cfg.WalletPassTeamID,
+			cfg.AppName,
+			cfg.WalletPassCertificatePath,
+			cfg.WalletPassPrivateKeyPath,
+			cfg.WalletPassIntermediatePath)
+		if err != nil {
+			panic(fmt.Sprintf("WALLET_PASS_ERROR: %s", err.Error()))
+		}
+		WalletPass = signer
+		log.Println("Wallet pass certificate loaded . . . .")
+	})
+}
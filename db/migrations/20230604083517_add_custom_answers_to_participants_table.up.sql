@@ -0,0 +1 @@
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS custom_answers JSONB;
@@ -0,0 +1 @@
+ALTER TABLE events DROP COLUMN IF EXISTS last_respond_synced_at;
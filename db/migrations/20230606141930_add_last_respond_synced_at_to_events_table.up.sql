@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS last_respond_synced_at VARCHAR(255);
@@ -0,0 +1,2 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS response_id;
+ALTER TABLE participants DROP COLUMN IF EXISTS last_submitted_time;
\ No newline at end of file
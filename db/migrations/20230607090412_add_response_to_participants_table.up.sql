@@ -0,0 +1,2 @@
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS response_id VARCHAR(255);
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS last_submitted_time VARCHAR(255);
\ No newline at end of file
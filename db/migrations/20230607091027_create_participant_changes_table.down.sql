@@ -0,0 +1 @@
+DROP TABLE IF EXISTS participant_changes;
\ No newline at end of file
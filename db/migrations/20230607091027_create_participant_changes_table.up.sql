@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS participant_changes (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    event_id BIGINT NOT NULL,
+    participant_id BIGINT NOT NULL,
+    field VARCHAR(255) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_participant_changes_participant
+    ON participant_changes (event_id, participant_id);
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    event_id BIGINT NOT NULL,
+    queue VARCHAR(255) NOT NULL,
+    status VARCHAR(25) NOT NULL DEFAULT 'queued',
+    payload TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    result TEXT,
+    queued_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    started_at BIGINT,
+    finished_at BIGINT,
+    updated_at BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_event
+    ON jobs (event_id, queued_at);
\ No newline at end of file
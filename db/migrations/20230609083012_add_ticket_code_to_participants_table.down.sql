@@ -0,0 +1 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS ticket_code;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS ticket_code VARCHAR(32) UNIQUE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS checked_in_at;
+ALTER TABLE participants DROP COLUMN IF EXISTS checked_in_by;
\ No newline at end of file
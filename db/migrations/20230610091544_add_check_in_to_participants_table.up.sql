@@ -0,0 +1,2 @@
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS checked_in_at INTEGER;
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS checked_in_by VARCHAR(255);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS branding JSONB;
\ No newline at end of file
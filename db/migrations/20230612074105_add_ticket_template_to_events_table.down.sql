@@ -0,0 +1 @@
+ALTER TABLE events DROP COLUMN IF EXISTS ticket_template;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS ticket_template TEXT;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE participants DROP COLUMN IF EXISTS ticket_sent_at;
\ No newline at end of file
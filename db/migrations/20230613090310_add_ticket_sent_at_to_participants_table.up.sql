@@ -0,0 +1 @@
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS ticket_sent_at INTEGER;
\ No newline at end of file
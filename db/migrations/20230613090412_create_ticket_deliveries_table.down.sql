@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ticket_deliveries;
\ No newline at end of file
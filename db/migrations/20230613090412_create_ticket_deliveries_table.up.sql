@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS ticket_deliveries (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    job_id BIGINT NOT NULL,
+    participant_id BIGINT NOT NULL,
+    status VARCHAR(25) NOT NULL DEFAULT 'queued',
+    error TEXT,
+    updated_at BIGINT,
+    UNIQUE (job_id, participant_id)
+);
\ No newline at end of file
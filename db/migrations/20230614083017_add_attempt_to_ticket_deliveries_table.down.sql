@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_ticket_deliveries_participant;
+
+ALTER TABLE ticket_deliveries DROP COLUMN IF EXISTS attempts;
+ALTER TABLE ticket_deliveries DROP COLUMN IF EXISTS attempted_at;
+ALTER TABLE ticket_deliveries DROP COLUMN IF EXISTS message_id;
+DELETE FROM ticket_deliveries WHERE job_id IS NULL;
+ALTER TABLE ticket_deliveries ALTER COLUMN job_id SET NOT NULL;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE ticket_deliveries ALTER COLUMN job_id DROP NOT NULL;
+ALTER TABLE ticket_deliveries ADD COLUMN IF NOT EXISTS message_id VARCHAR(255);
+ALTER TABLE ticket_deliveries ADD COLUMN IF NOT EXISTS attempted_at BIGINT;
+ALTER TABLE ticket_deliveries ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+UPDATE ticket_deliveries SET attempts = 1 WHERE status <> 'queued';
+
+CREATE INDEX IF NOT EXISTS idx_ticket_deliveries_participant
+    ON ticket_deliveries (participant_id, attempted_at);
\ No newline at end of file
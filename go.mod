@@ -23,6 +23,7 @@ require (
 	github.com/swaggo/swag v1.16.1
 	github.com/vanng822/go-premailer v1.20.2
 	github.com/xuri/excelize/v2 v2.7.1
+	go.mozilla.org/pkcs7 v0.9.0
 	google.golang.org/api v0.122.0
 	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
 )
@@ -1,7 +1,13 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/internal/job"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/aasumitro/tix/pkg/wallet"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/api/forms/v1"
@@ -14,6 +20,13 @@ type boostrap struct {
 	cache      *redis.Client
 	mailer     *gomail.Dialer
 	googleForm *forms.Service
+	walletPass *wallet.Signer
+	fileStore  storage.FileStore
+	role       common.AppRole
+
+	tixService domain.ITixService
+	jobQueue   domain.IJobQueue
+	worker     job.Worker
 }
 
 type BoostrapOption func(*boostrap)
@@ -48,11 +61,56 @@ func WithGoogleFormService(googleFromService *forms.Service) BoostrapOption {
 	}
 }
 
-func RunApp(options ...BoostrapOption) {
-	boot := &boostrap{}
+// WithWalletPassSigner enable the wallet pass of the ticket.
+func WithWalletPassSigner(signer *wallet.Signer) BoostrapOption {
+	return func(boostrap *boostrap) {
+		boostrap.walletPass = signer
+	}
+}
+
+// WithFileStore archive the exports, it must be shared by every
+// instance so the export download link can be served by any of them.
+func WithFileStore(store storage.FileStore) BoostrapOption {
+	return func(boostrap *boostrap) {
+		boostrap.fileStore = store
+	}
+}
+
+// WithRole set what the app run, default to all (the http api and the jobs).
+func WithRole(role common.AppRole) BoostrapOption {
+	return func(boostrap *boostrap) {
+		if role != "" {
+			boostrap.role = role
+		}
+	}
+}
+
+// RunApp wire and start the app, the returned function stop the background
+// jobs (waiting for the running one until the context is done) then release
+// the service resources, it is called once the http server is shut down.
+func RunApp(options ...BoostrapOption) (shutdown func(ctx context.Context) error) {
+	boot := &boostrap{role: common.AppRoleAll}
 	for _, option := range options {
 		option(boot)
 	}
-	boot.newPublicAPIProvider()
-	boot.newTixAPIProvider()
+	boot.newTixServiceProvider()
+	if boot.role != common.AppRoleWorker {
+		boot.newPublicAPIProvider()
+		boot.newTixAPIProvider()
+	}
+	if boot.role != common.AppRoleWeb {
+		boot.newWorkerProvider()
+	}
+	return boot.shutdown
+}
+
+func (boot *boostrap) shutdown(ctx context.Context) error {
+	// only the roles running the jobs save the export files
+	if boot.worker == nil {
+		return nil
+	}
+	if err := boot.worker.Shutdown(ctx); err != nil {
+		return err
+	}
+	return boot.tixService.Shutdown(ctx)
 }
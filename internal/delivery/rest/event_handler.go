@@ -2,13 +2,17 @@ package rest
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/config"
 	"github.com/aasumitro/tix/internal/domain"
 	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/pkg/calendar"
 	"github.com/aasumitro/tix/pkg/http/middleware"
 	"github.com/aasumitro/tix/pkg/http/wrapper"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -64,6 +68,131 @@ func (handler *EventRESTHandler) Validate(ctx *gin.Context) {
 	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
 }
 
+func (handler *EventRESTHandler) Mapping(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchFieldMapping(ctxWT, googleFormID)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) UpdateMapping(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestFieldMapping
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.UpdateFieldMapping(ctxWT, googleFormID, &body)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) Branding(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchEventBranding(ctxWT, googleFormID)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) UpdateBranding(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestBranding
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.UpdateEventBranding(ctxWT, googleFormID, &body)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) TicketTemplate(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchTicketTemplate(ctxWT, googleFormID)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) UpdateTicketTemplate(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestTicketTemplate
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.UpdateTicketTemplate(ctxWT, googleFormID, &body)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// PreviewTicketTemplate respond with the pdf ticket of a sample participant
+// drawn from the given template, or from the stored one when it is empty.
+func (handler *EventRESTHandler) PreviewTicketTemplate(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestTicketTemplate
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.PreviewTicketTemplate(ctxWT, googleFormID, &body)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="ticket-preview.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", data)
+}
+
 func (handler *EventRESTHandler) Overview(ctx *gin.Context) {
 	id := ctx.Param("google_form_id")
 	ctxWT, cancel := context.WithTimeout(
@@ -80,9 +209,101 @@ func (handler *EventRESTHandler) Overview(ctx *gin.Context) {
 
 func (handler *EventRESTHandler) Participants(ctx *gin.Context) {
 	id := ctx.Param("google_form_id")
+	var query request.EventRequestParticipantFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
 	defer cancel()
-	data, err := handler.Service.FetchParticipants(ctxWT, id)
+	data, err := handler.Service.FetchParticipants(ctxWT, id, &query)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *EventRESTHandler) Changes(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	participantID := ctx.Param("participant_id")
+	pid, err := strconv.ParseInt(participantID, 10, 32)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchParticipantChanges(ctxWT, googleFormID, int32(pid))
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// CheckIn redeem the scanned ticket, a rejected ticket respond
+// with the check-in error code (e.g. TICKET_ALREADY_CHECKED_IN).
+func (handler *EventRESTHandler) CheckIn(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestCheckIn
+	if err := ctx.ShouldBind(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	email := ctx.MustGet("user_email").(string)
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.CheckInTicket(ctxWT, googleFormID, body.TicketCode, email)
+	if err != nil {
+		var checkInErr *common.CheckInError
+		if errors.As(err, &checkInErr) {
+			wrapper.NewHTTPRespondWrapper(ctx, checkInStatusCode(checkInErr), checkInErr.Code)
+			return
+		}
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// CheckInBatch reconcile the check-ins recorded by an offline gate,
+// each check-in is reported as checked in, duplicate or rejected.
+func (handler *EventRESTHandler) CheckInBatch(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestCheckInBatch
+	if err := ctx.ShouldBind(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	email := ctx.MustGet("user_email").(string)
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.ReconcileCheckIns(ctxWT, googleFormID, body.CheckIns, email)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// TicketKey respond with the event public key used by the offline gate.
+func (handler *EventRESTHandler) TicketKey(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchTicketKeyBundle(ctxWT, googleFormID)
 	if err != nil {
 		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
 		return
@@ -90,19 +311,78 @@ func (handler *EventRESTHandler) Participants(ctx *gin.Context) {
 	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
 }
 
+func (handler *EventRESTHandler) VerifyTicket(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestVerifyTicket
+	if err := ctx.ShouldBind(&body); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.VerifyTicket(ctxWT, googleFormID, body.Token)
+	if err != nil {
+		var checkInErr *common.CheckInError
+		if errors.As(err, &checkInErr) {
+			wrapper.NewHTTPRespondWrapper(ctx, checkInStatusCode(checkInErr), checkInErr.Code)
+			return
+		}
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// Calendar respond with the event as an ics file, it is public
+// so the calendar apps can subscribe to it without the access token.
+func (handler *EventRESTHandler) Calendar(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchEventCalendar(ctxWT, googleFormID)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="calendar.ics"`)
+	ctx.Data(http.StatusOK, calendar.ContentType, data)
+}
+
+func checkInStatusCode(err *common.CheckInError) int {
+	switch err {
+	case common.ErrTicketNotFound:
+		return http.StatusNotFound
+	case common.ErrTicketAlreadyCheckedIn:
+		return http.StatusConflict
+	case common.ErrTicketDeclined, common.ErrTicketNotApproved:
+		return http.StatusForbidden
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
 func (handler *EventRESTHandler) Sync(ctx *gin.Context) {
 	googleFormID := ctx.Param("google_form_id")
 	ctxWT, cancel := context.WithTimeout(
 		ctx.Request.Context(),
 		common.ContextTimeout*time.Second)
 	defer cancel()
-	if err := handler.Service.PublishSyncEventDataQueue(
+	jobID, err := handler.Service.PublishSyncEventDataQueue(
 		ctxWT, googleFormID,
-	); err != nil {
+	)
+	if err != nil {
 		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
-	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, common.MsgWaitSync)
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, map[string]any{
+		"job_id":  jobID,
+		"message": common.MsgWaitSync,
+	})
 }
 
 func (handler *EventRESTHandler) Status(ctx *gin.Context) {
@@ -150,30 +430,257 @@ func (handler *EventRESTHandler) Generate(ctx *gin.Context) {
 		ctx.Request.Context(),
 		common.ContextTimeout*time.Second)
 	defer cancel()
-	if err := handler.Service.PublishGenerateEventTicketQueue(
+	jobID, err := handler.Service.PublishGenerateEventTicketQueue(
 		ctxWT, googleFormID, int32(pid),
-	); err != nil {
+	)
+	if err != nil {
 		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
-	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, common.MsgWaitGenTix)
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, map[string]any{
+		"job_id":  jobID,
+		"message": common.MsgWaitGenTix,
+	})
+}
+
+func (handler *EventRESTHandler) Ticket(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	participantID := ctx.Param("participant_id")
+	pid, err := strconv.ParseInt(participantID, 10, 32)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.DownloadTicket(ctxWT, googleFormID, int32(pid))
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrTicketNotFound):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, common.ErrParticipantNotApproved):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusForbidden, err.Error())
+		default:
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="ticket.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", data)
+}
+
+func (handler *EventRESTHandler) GenerateBulk(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	var body request.EventRequestBulkTicket
+	if err := ctx.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	jobID, err := handler.Service.PublishGenerateEventTicketsQueue(
+		ctxWT, googleFormID, body.Filter,
+	)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, map[string]any{
+		"job_id":  jobID,
+		"message": common.MsgWaitGenBulkTix,
+	})
+}
+
+func (handler *EventRESTHandler) BulkReport(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	jobID, err := strconv.ParseInt(ctx.Param("job_id"), 10, 32)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchTicketDeliveries(ctxWT, googleFormID, int32(jobID))
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
 }
 
 func (handler *EventRESTHandler) Export(ctx *gin.Context) {
 	googleFormID := ctx.Param("google_form_id")
-	exportType := ctx.Param("export_type")
+	exportType := strings.ToLower(ctx.Param("export_type"))
+	if !isEventExportType(exportType) {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusUnprocessableEntity,
+			common.ErrUnsupportedExportType.Error())
+		return
+	}
+	var query request.EventRequestExport
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if !isEventExportColumns(query.Columns) {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusUnprocessableEntity,
+			common.ErrUnsupportedExportColumn.Error())
+		return
+	}
 	email := ctx.MustGet("user_email").(string)
 	ctxWT, cancel := context.WithTimeout(
 		ctx.Request.Context(),
 		common.ContextTimeout*time.Second)
 	defer cancel()
-	if err := handler.Service.PublishExportEventDataQueue(
-		ctxWT, googleFormID, exportType, email,
+	jobID, err := handler.Service.PublishExportEventDataQueue(
+		ctxWT, googleFormID, exportType, email, &query,
+	)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, map[string]any{
+		"job_id":  jobID,
+		"message": common.MsgWaitExport,
+	})
+}
+
+// exportDownloads is the export type and the content type of the export download formats.
+var exportDownloads = map[string]struct {
+	exportType  common.EventExportType
+	contentType string
+}{
+	"csv":  {common.ExportTypeCSV, "text/csv; charset=utf-8"},
+	"xlsx": {common.ExportTypeXLS, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	"pdf":  {common.ExportTypePDF, "application/pdf"},
+}
+
+// DownloadExport stream the export file straight into the response,
+// the event too large to download is exported by email, see Export.
+func (handler *EventRESTHandler) DownloadExport(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	format := strings.ToLower(ctx.Param("format"))
+	download, ok := exportDownloads[format]
+	if !ok {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusUnprocessableEntity,
+			common.ErrUnsupportedExportFormat.Error())
+		return
+	}
+	var query request.EventRequestExport
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if !isEventExportColumns(query.Columns) {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusUnprocessableEntity,
+			common.ErrUnsupportedExportColumn.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	ctx.Header("Content-Type", download.contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="%s.%s"`, googleFormID, format))
+	if err := handler.Service.StreamEventExport(
+		ctxWT, googleFormID, string(download.exportType), &query, ctx.Writer,
 	); err != nil {
+		if ctx.Writer.Written() {
+			// part of the file is already sent, the status can not be changed
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+	}
+}
+
+func isEventExportType(exportType string) bool {
+	for _, eventExportType := range common.EventExportTypes {
+		if string(eventExportType) == exportType {
+			return true
+		}
+	}
+	return false
+}
+
+// isEventExportColumns check every comma separated column, no column is valid.
+func isEventExportColumns(columns string) bool {
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "" {
+			continue
+		}
+		known := false
+		for _, eventExportColumn := range common.EventExportColumns {
+			if string(eventExportColumn) == column {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+func (handler *EventRESTHandler) Jobs(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchEventJobs(ctxWT, googleFormID)
+	if err != nil {
 		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
-	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, common.MsgWaitExport)
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+// Stream push the event notifications (new participants, status
+// changes and finished jobs) as server-sent events until the client leave.
+func (handler *EventRESTHandler) Stream(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	notifications, err := handler.Service.SubscribeEventNotifications(
+		ctx.Request.Context(), googleFormID)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+	heartbeat := time.NewTicker(common.EventStreamHeartbeatTime)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			ctx.SSEvent(notification.Type, notification)
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", time.Now().Unix())
+		}
+		ctx.Writer.Flush()
+	}
 }
 
 func NewEventRESTHandler(
@@ -181,15 +688,34 @@ func NewEventRESTHandler(
 	service domain.ITixService,
 ) {
 	handler := &EventRESTHandler{service}
+	router.GET("/events/:google_form_id/calendar.ics", handler.Calendar)
 	router = router.Group("/events")
 	router.Use(middleware.Auth(config.Instance.SupabaseJWTSecret))
 	router.GET(common.EmptyPath, handler.Fetch)
 	router.POST(common.EmptyPath, handler.Store)
 	router.POST("/validate", handler.Validate)
+	router.GET("/:google_form_id/mapping", handler.Mapping)
+	router.PUT("/:google_form_id/mapping", handler.UpdateMapping)
+	router.GET("/:google_form_id/branding", handler.Branding)
+	router.PUT("/:google_form_id/branding", handler.UpdateBranding)
+	router.GET("/:google_form_id/ticket-template", handler.TicketTemplate)
+	router.PUT("/:google_form_id/ticket-template", handler.UpdateTicketTemplate)
+	router.POST("/:google_form_id/ticket-template/preview", handler.PreviewTicketTemplate)
 	router.GET("/:google_form_id/overview", handler.Overview)
 	router.GET("/:google_form_id/participants", handler.Participants)
 	router.POST("/:google_form_id/sync", handler.Sync)
+	router.POST("/:google_form_id/checkin", handler.CheckIn)
+	router.POST("/:google_form_id/checkin/batch", handler.CheckInBatch)
+	router.GET("/:google_form_id/tickets/key", handler.TicketKey)
+	router.POST("/:google_form_id/tickets/verify", handler.VerifyTicket)
+	router.GET("/:google_form_id/jobs", handler.Jobs)
+	router.GET("/:google_form_id/stream", handler.Stream)
+	router.GET("/:google_form_id/participants/:participant_id/changes", handler.Changes)
 	router.PATCH("/:google_form_id/participants/:participant_id/status", handler.Status)
 	router.POST("/:google_form_id/participants/:participant_id/ticket", handler.Generate)
+	router.GET("/:google_form_id/participants/:participant_id/ticket.pdf", handler.Ticket)
+	router.POST("/:google_form_id/tickets/bulk", handler.GenerateBulk)
+	router.GET("/:google_form_id/tickets/bulk/:job_id", handler.BulkReport)
 	router.POST("/:google_form_id/export/:export_type", handler.Export)
+	router.GET("/:google_form_id/export.:format", handler.DownloadExport)
 }
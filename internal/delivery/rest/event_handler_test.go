@@ -3,19 +3,25 @@ package rest_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/config"
 	"github.com/aasumitro/tix/internal/delivery/rest"
+	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
 	"github.com/aasumitro/tix/mocks"
+	"github.com/aasumitro/tix/pkg/calendar"
 	"github.com/aasumitro/tix/pkg/http/tests"
 	"github.com/aasumitro/tix/pkg/http/wrapper"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -201,32 +207,468 @@ func (s *eventHandlerTestSuite) Test_Validate_ShouldError() {
 	})
 }
 
+func (s *eventHandlerTestSuite) Test_Mapping_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchFieldMapping", mock.Anything, mock.Anything).
+		Return(&response.EventFieldMappingResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/mapping", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Mapping(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_Mapping_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchFieldMapping", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/mapping", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Mapping(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *eventHandlerTestSuite) Test_UpdateMapping_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("UpdateFieldMapping", mock.Anything, mock.Anything, mock.Anything).
+		Return(&response.EventFieldMappingResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+		"mapping": map[string]string{"1": "name"},
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.UpdateMapping(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_UpdateMapping_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("ERROR ENTITY", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateMapping(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(http.StatusUnprocessableEntity, got.Code)
+		s.Equal(http.StatusText(http.StatusUnprocessableEntity), got.Status)
+	})
+	s.T().Run("ERROR SERVICE", func(t *testing.T) {
+		svcMock.On("UpdateFieldMapping", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+			"mapping": map[string]string{"1": "name"},
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateMapping(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_Branding_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchEventBranding", mock.Anything, mock.Anything).
+		Return(&response.EventBrandingResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/branding", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Branding(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_Branding_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchEventBranding", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/branding", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Branding(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *eventHandlerTestSuite) Test_UpdateBranding_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("UpdateEventBranding", mock.Anything, mock.Anything, mock.Anything).
+		Return(&response.EventBrandingResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+		"header_title":  "lorem",
+		"primary_color": "#1A2B3C",
+		"sender_email":  "lorem@ipsum.id",
+		"locale":        "en",
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.UpdateBranding(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_UpdateBranding_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("ERROR ENTITY", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+			"primary_color": "lorem",
+			"sender_email":  "lorem",
+			"locale":        "fr",
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateBranding(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(http.StatusUnprocessableEntity, got.Code)
+		s.Equal(http.StatusText(http.StatusUnprocessableEntity), got.Status)
+	})
+	s.T().Run("ERROR SERVICE", func(t *testing.T) {
+		svcMock.On("UpdateEventBranding", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+			"header_title": "lorem",
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateBranding(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_TicketTemplate_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchTicketTemplate", mock.Anything, mock.Anything).
+		Return(&response.EventTicketTemplateResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/ticket-template", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.TicketTemplate(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_TicketTemplate_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchTicketTemplate", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/ticket-template", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.TicketTemplate(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *eventHandlerTestSuite) Test_UpdateTicketTemplate_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("UpdateTicketTemplate", mock.Anything, mock.Anything, mock.Anything).
+		Return(&response.EventTicketTemplateResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+		"template": "# {{ .Event.Name }}",
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.UpdateTicketTemplate(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_UpdateTicketTemplate_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("ERROR ENTITY", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+			"template": 1,
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateTicketTemplate(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(http.StatusUnprocessableEntity, got.Code)
+		s.Equal(http.StatusText(http.StatusUnprocessableEntity), got.Status)
+	})
+	s.T().Run("ERROR SERVICE", func(t *testing.T) {
+		svcMock.On("UpdateTicketTemplate", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "PUT", "application/json", map[string]interface{}{
+			"template": "{{ .Lorem }}",
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.UpdateTicketTemplate(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_PreviewTicketTemplate_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("PreviewTicketTemplate", mock.Anything, mock.Anything, mock.Anything).
+		Return([]byte("%PDF-1.3"), nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+		"template": "# {{ .Event.Name }}",
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.PreviewTicketTemplate(ctx)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal("application/pdf", writer.Header().Get("Content-Type"))
+	s.Equal("%PDF-1.3", writer.Body.String())
+}
+func (s *eventHandlerTestSuite) Test_PreviewTicketTemplate_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("ERROR ENTITY", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+			"template": 1,
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.PreviewTicketTemplate(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	s.T().Run("ERROR SERVICE", func(t *testing.T) {
+		svcMock.On("PreviewTicketTemplate", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.PreviewTicketTemplate(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+	})
+}
+
 func (s *eventHandlerTestSuite) Test_Overview_ShouldSuccess() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("FetchOverview", mock.Anything, mock.Anything).
-		Return(&response.EventOverviewResponse{}, nil).Once()
+	svcMock.On("FetchOverview", mock.Anything, mock.Anything).
+		Return(&response.EventOverviewResponse{}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/overview", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Overview(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_Overview_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchOverview", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/overview", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Overview(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *eventHandlerTestSuite) Test_Participant_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchParticipants", mock.Anything, mock.Anything, &request.EventRequestParticipantFilter{
+		TicketDelivery: "failed",
+	}).Return([]*response.ParticipantResponse{{}}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants?ticket_delivery=failed", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Participants(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_Participant_ShouldError() {
+	s.T().Run("error bind", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants?ticket_delivery=lorem", http.NoBody)
+		ctx.Request = req
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.Participants(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchParticipants", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants", http.NoBody)
+	ctx.Request = req
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Participants(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *eventHandlerTestSuite) Test_Changes_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchParticipantChanges", mock.Anything, mock.Anything, int32(1)).
+		Return([]*response.ParticipantChangeResponse{{}}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants/1/changes", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("participant_id", "1")
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Changes(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *eventHandlerTestSuite) Test_Changes_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("error parse", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants/asd/changes", http.NoBody)
+		ctx.Request = req
+		ctx.AddParam("participant_id", "asd")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.Changes(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock.On("FetchParticipantChanges", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants/1/changes", http.NoBody)
+		ctx.Request = req
+		ctx.AddParam("participant_id", "1")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.Changes(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_Sync_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("PublishSyncEventDataQueue", mock.Anything, mock.Anything).
+		Return(int32(1), nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	req, _ := http.NewRequest("GET", "/api/v1/events/asd/overview", http.NoBody)
-	ctx.Request = req
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Overview(ctx)
+	handler.Sync(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusOK, writer.Code)
 	s.Equal(http.StatusOK, got.Code)
 	s.Equal(http.StatusText(http.StatusOK), got.Status)
 }
-func (s *eventHandlerTestSuite) Test_Overview_ShouldError() {
+func (s *eventHandlerTestSuite) Test_Sync_ShouldError() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("FetchOverview", mock.Anything, mock.Anything).
-		Return(nil, errors.New("lorem")).Once()
+	svcMock.On("PublishSyncEventDataQueue", mock.Anything, mock.Anything).
+		Return(int32(0), errors.New("lorem")).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	req, _ := http.NewRequest("GET", "/api/v1/events/asd/overview", http.NoBody)
-	ctx.Request = req
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Overview(ctx)
+	handler.Sync(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusBadRequest, writer.Code)
@@ -234,32 +676,34 @@ func (s *eventHandlerTestSuite) Test_Overview_ShouldError() {
 	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
 }
 
-func (s *eventHandlerTestSuite) Test_Participant_ShouldSuccess() {
+func (s *eventHandlerTestSuite) Test_Jobs_ShouldSuccess() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("FetchParticipants", mock.Anything, mock.Anything).
-		Return([]*response.ParticipantResponse{{}}, nil).Once()
+	svcMock.On("FetchEventJobs", mock.Anything, "asd").
+		Return([]*response.JobResponse{{ID: 1}}, nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/jobs", http.NoBody)
 	ctx.Request = req
+	ctx.AddParam("google_form_id", "asd")
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Participants(ctx)
+	handler.Jobs(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusOK, writer.Code)
 	s.Equal(http.StatusOK, got.Code)
 	s.Equal(http.StatusText(http.StatusOK), got.Status)
 }
-func (s *eventHandlerTestSuite) Test_Participant_ShouldError() {
+func (s *eventHandlerTestSuite) Test_Jobs_ShouldError() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("FetchParticipants", mock.Anything, mock.Anything).
+	svcMock.On("FetchEventJobs", mock.Anything, mock.Anything).
 		Return(nil, errors.New("lorem")).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	req, _ := http.NewRequest("GET", "/api/v1/events/asd/participants", http.NoBody)
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/jobs", http.NoBody)
 	ctx.Request = req
+	ctx.AddParam("google_form_id", "asd")
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Participants(ctx)
+	handler.Jobs(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusBadRequest, writer.Code)
@@ -267,32 +711,40 @@ func (s *eventHandlerTestSuite) Test_Participant_ShouldError() {
 	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
 }
 
-func (s *eventHandlerTestSuite) Test_Sync_ShouldSuccess() {
+func (s *eventHandlerTestSuite) Test_Stream_ShouldSuccess() {
+	notifications := make(chan *response.EventNotificationResponse, 1)
+	notifications <- &response.EventNotificationResponse{
+		Type:         "participant_status_updated",
+		GoogleFormID: "asd",
+		Data:         map[string]any{"participant_id": 1, "status": "approved"},
+	}
+	close(notifications)
 	svcMock := new(mocks.ITixService)
-	svcMock.On("PublishSyncEventDataQueue", mock.Anything, mock.Anything).
-		Return(nil).Once()
+	svcMock.On("SubscribeEventNotifications", mock.Anything, "asd").
+		Return((<-chan *response.EventNotificationResponse)(notifications), nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	ctx.Request = &http.Request{Header: make(http.Header)}
-	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/stream", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("google_form_id", "asd")
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Sync(ctx)
-	var got wrapper.CommonRespond
-	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	handler.Stream(ctx)
 	s.Equal(http.StatusOK, writer.Code)
-	s.Equal(http.StatusOK, got.Code)
-	s.Equal(http.StatusText(http.StatusOK), got.Status)
+	s.Equal("text/event-stream", writer.Header().Get("Content-Type"))
+	s.Contains(writer.Body.String(), "event:participant_status_updated")
+	s.Contains(writer.Body.String(), `"participant_id":1`)
 }
-func (s *eventHandlerTestSuite) Test_Sync_ShouldError() {
+func (s *eventHandlerTestSuite) Test_Stream_ShouldError() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("PublishSyncEventDataQueue", mock.Anything, mock.Anything).
-		Return(errors.New("lorem")).Once()
+	svcMock.On("SubscribeEventNotifications", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
-	ctx.Request = &http.Request{Header: make(http.Header)}
-	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+	req, _ := http.NewRequest("GET", "/api/v1/events/asd/stream", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("google_form_id", "asd")
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Sync(ctx)
+	handler.Stream(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusBadRequest, writer.Code)
@@ -388,7 +840,7 @@ func (s *eventHandlerTestSuite) Test_Status_ShouldError() {
 func (s *eventHandlerTestSuite) Test_Generate_ShouldSuccess() {
 	svcMock := new(mocks.ITixService)
 	svcMock.On("PublishGenerateEventTicketQueue", mock.Anything, mock.Anything, mock.Anything).
-		Return(nil).Once()
+		Return(int32(1), nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
 	ctx.Request = &http.Request{Header: make(http.Header)}
@@ -420,7 +872,7 @@ func (s *eventHandlerTestSuite) Test_Generate_ShouldError() {
 	})
 	s.T().Run("error service", func(t *testing.T) {
 		svcMock.On("PublishGenerateEventTicketQueue", mock.Anything, mock.Anything, mock.Anything).
-			Return(errors.New("lorem")).Once()
+			Return(int32(0), errors.New("lorem")).Once()
 		writer := httptest.NewRecorder()
 		ctx, _ := gin.CreateTestContext(writer)
 		ctx.Request = &http.Request{Header: make(http.Header)}
@@ -436,39 +888,601 @@ func (s *eventHandlerTestSuite) Test_Generate_ShouldError() {
 	})
 }
 
+func (s *eventHandlerTestSuite) Test_Ticket_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("DownloadTicket", mock.Anything, "asd", int32(1)).
+		Return([]byte("%PDF-lorem"), nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	ctx.AddParam("participant_id", "1")
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.Ticket(ctx)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal("application/pdf", writer.Header().Get("Content-Type"))
+	s.Contains(writer.Header().Get("Content-Disposition"), "ticket.pdf")
+	s.Equal("%PDF-lorem", writer.Body.String())
+}
+func (s *eventHandlerTestSuite) Test_Ticket_ShouldError() {
+	s.T().Run("error parse", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		ctx.AddParam("participant_id", "asd")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.Ticket(ctx)
+		s.Equal(http.StatusBadRequest, writer.Code)
+	})
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{common.ErrTicketNotFound, http.StatusNotFound},
+		{common.ErrParticipantNotApproved, http.StatusForbidden},
+		{errors.New("lorem"), http.StatusBadRequest},
+	} {
+		s.T().Run(tt.err.Error(), func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("DownloadTicket", mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = &http.Request{Header: make(http.Header)}
+			ctx.AddParam("participant_id", "1")
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.Ticket(ctx)
+			var got wrapper.CommonRespond
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.code, got.Code)
+		})
+	}
+}
+
+func (s *eventHandlerTestSuite) Test_GenerateBulk_ShouldSuccess() {
+	for _, filter := range []string{"", "unsent"} {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("PublishGenerateEventTicketsQueue", mock.Anything, "asd", filter).
+			Return(int32(1), nil).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		ctx.AddParam("google_form_id", "asd")
+		if filter == "" {
+			// the body is optional
+			ctx.Request.Method = http.MethodPost
+			ctx.Request.Body = http.NoBody
+		} else {
+			tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+				"filter": filter,
+			})
+		}
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.GenerateBulk(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusOK, writer.Code)
+		s.Equal(http.StatusOK, got.Code)
+		svcMock.AssertExpectations(s.T())
+	}
+}
+func (s *eventHandlerTestSuite) Test_GenerateBulk_ShouldError() {
+	s.T().Run("error bind", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+			"filter": "lorem",
+		})
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.GenerateBulk(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("PublishGenerateEventTicketsQueue", mock.Anything, mock.Anything, mock.Anything).
+			Return(int32(0), common.ErrRateLimitingPushQueue).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+			"filter": "approved",
+		})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.GenerateBulk(ctx)
+		s.Equal(http.StatusBadRequest, writer.Code)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_BulkReport_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchTicketDeliveries", mock.Anything, "asd", int32(7)).
+		Return(&response.TicketDeliveryReportResponse{JobID: 7, Total: 1, Sent: 1}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	ctx.AddParam("job_id", "7")
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.BulkReport(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+}
+func (s *eventHandlerTestSuite) Test_BulkReport_ShouldError() {
+	s.T().Run("error parse", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		ctx.AddParam("job_id", "asd")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.BulkReport(ctx)
+		s.Equal(http.StatusBadRequest, writer.Code)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("FetchTicketDeliveries", mock.Anything, mock.Anything, int32(7)).
+			Return(nil, common.ErrJobNotFound).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		ctx.AddParam("job_id", "7")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.BulkReport(ctx)
+		s.Equal(http.StatusBadRequest, writer.Code)
+	})
+}
+
 func (s *eventHandlerTestSuite) Test_Export_ShouldSuccess() {
+	for _, tt := range []struct {
+		exportType string
+		query      string
+		want       *request.EventRequestExport
+	}{
+		{"pdf", "", &request.EventRequestExport{}},
+		{"XLS", "", &request.EventRequestExport{}},
+		{"csv", "?bom=true", &request.EventRequestExport{BOM: true}},
+		{"json", "", &request.EventRequestExport{}},
+		{"ndjson", "", &request.EventRequestExport{}},
+		{"pdf", "?locale=en", &request.EventRequestExport{Locale: "en"}},
+		{"csv", "?status=approved&checked_in=false&registered_from=1686600000&registered_to=1686700000" +
+			"&search=tix&columns=name,email,answers", &request.EventRequestExport{
+			Status: "approved", CheckedIn: new(bool), RegisteredFrom: 1686600000,
+			RegisteredTo: 1686700000, Search: "tix", Columns: "name,email,answers",
+		}},
+	} {
+		s.T().Run(tt.exportType+tt.query, func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("PublishExportEventDataQueue", mock.Anything, "asd",
+				strings.ToLower(tt.exportType), "hello@tix.id", tt.want).
+				Return(int32(1), nil).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/"+tt.query, http.NoBody)
+			ctx.AddParam("google_form_id", "asd")
+			ctx.AddParam("export_type", tt.exportType)
+			ctx.Set("user_email", "hello@tix.id")
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.Export(ctx)
+			var got wrapper.CommonRespond
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(http.StatusOK, writer.Code)
+			s.Equal(http.StatusOK, got.Code)
+			s.Equal(http.StatusText(http.StatusOK), got.Status)
+			svcMock.AssertExpectations(t)
+		})
+	}
+}
+func (s *eventHandlerTestSuite) Test_Export_ShouldError() {
+	s.T().Run("error unsupported type", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+		ctx.AddParam("export_type", "docx")
+		ctx.Set("user_email", "hello@tix.id")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.Export(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(common.ErrUnsupportedExportType.Error(), got.Data)
+	})
+	for _, query := range []string{"?bom=lorem", "?status=lorem", "?locale=fr", "?registered_from=1686700000&registered_to=1686600000"} {
+		s.T().Run("error bind query "+query, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/"+query, http.NoBody)
+			ctx.AddParam("export_type", "csv")
+			ctx.Set("user_email", "hello@tix.id")
+			handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+			handler.Export(ctx)
+			s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		})
+	}
+	s.T().Run("error unsupported column", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/?columns=name,lorem", http.NoBody)
+		ctx.AddParam("export_type", "csv")
+		ctx.Set("user_email", "hello@tix.id")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.Export(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(common.ErrUnsupportedExportColumn.Error(), got.Data)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("PublishExportEventDataQueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(int32(0), errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+		ctx.AddParam("export_type", "pdf")
+		ctx.Set("user_email", "hello@tix.id")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.Export(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_DownloadExport_ShouldSuccess() {
+	for _, tt := range []struct {
+		format      string
+		query       string
+		exportType  common.EventExportType
+		withBOM     bool
+		contentType string
+	}{
+		{"csv", "?bom=true", common.ExportTypeCSV, true, "text/csv; charset=utf-8"},
+		{"XLSX", "", common.ExportTypeXLS, false,
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+		{"pdf", "", common.ExportTypePDF, false, "application/pdf"},
+	} {
+		s.T().Run(tt.format, func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("StreamEventExport", mock.Anything, "asd", string(tt.exportType),
+				&request.EventRequestExport{BOM: tt.withBOM}, mock.Anything).
+				Return(nil).Once().Run(func(args mock.Arguments) {
+				_, _ = args.Get(4).(io.Writer).Write([]byte("lorem"))
+			})
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, http.NoBody)
+			ctx.AddParam("google_form_id", "asd")
+			ctx.AddParam("format", tt.format)
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.DownloadExport(ctx)
+			s.Equal(http.StatusOK, writer.Code)
+			s.Equal(tt.contentType, writer.Header().Get("Content-Type"))
+			s.Equal(fmt.Sprintf(`attachment; filename="asd.%s"`, strings.ToLower(tt.format)),
+				writer.Header().Get("Content-Disposition"))
+			s.Equal("lorem", writer.Body.String())
+			svcMock.AssertExpectations(t)
+		})
+	}
+}
+func (s *eventHandlerTestSuite) Test_DownloadExport_ShouldError() {
+	s.T().Run("error unsupported format", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		ctx.AddParam("format", "json")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.DownloadExport(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(common.ErrUnsupportedExportFormat.Error(), got.Data)
+	})
+	s.T().Run("error bind query", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/?bom=lorem", http.NoBody)
+		ctx.AddParam("format", "csv")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.DownloadExport(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	s.T().Run("error unsupported column", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/?columns=lorem", http.NoBody)
+		ctx.AddParam("format", "csv")
+		handler := rest.EventRESTHandler{Service: new(mocks.ITixService)}
+		handler.DownloadExport(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		s.Equal(common.ErrUnsupportedExportColumn.Error(), got.Data)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("StreamEventExport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(common.ErrExportTooLarge).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		ctx.AddParam("format", "csv")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.DownloadExport(ctx)
+		var got wrapper.CommonRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(common.ErrExportTooLarge.Error(), got.Data)
+		s.Contains(writer.Header().Get("Content-Type"), "application/json")
+		s.Empty(writer.Header().Get("Content-Disposition"))
+	})
+	s.T().Run("error service after writing", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		svcMock.On("StreamEventExport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("lorem")).Once().Run(func(args mock.Arguments) {
+			_, _ = args.Get(4).(io.Writer).Write([]byte("lorem"))
+		})
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		ctx.AddParam("format", "csv")
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.DownloadExport(ctx)
+		// the status is already sent along with the first part of the file
+		s.Equal(http.StatusOK, writer.Code)
+		s.Equal("lorem", writer.Body.String())
+		s.Len(ctx.Errors, 1)
+	})
+}
+
+func (s *eventHandlerTestSuite) Test_CheckIn_ShouldSuccess() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("PublishExportEventDataQueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(nil).Once()
+	svcMock.On("CheckInTicket", mock.Anything, "asd", "7K2M-QX4D-9PLA-ZR3T", "hello@tix.id").
+		Return(&response.CheckInResponse{ParticipantID: 1}, nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
 	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
 	ctx.Set("user_email", "hello@tix.id")
-	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+		"ticket_code": "7K2M-QX4D-9PLA-ZR3T",
+	})
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Export(ctx)
+	handler.CheckIn(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusOK, writer.Code)
 	s.Equal(http.StatusOK, got.Code)
 	s.Equal(http.StatusText(http.StatusOK), got.Status)
 }
-func (s *eventHandlerTestSuite) Test_Export_ShouldError() {
+func (s *eventHandlerTestSuite) Test_CheckIn_ShouldError() {
+	s.T().Run("error validation", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		ctx.Set("user_email", "hello@tix.id")
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.CheckIn(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	for _, tt := range []struct {
+		err  error
+		code int
+		data string
+	}{
+		{common.ErrTicketNotFound, http.StatusNotFound, "TICKET_NOT_FOUND"},
+		{common.ErrTicketWrongEvent, http.StatusUnprocessableEntity, "TICKET_WRONG_EVENT"},
+		{common.ErrTicketDeclined, http.StatusForbidden, "TICKET_DECLINED"},
+		{common.ErrTicketNotApproved, http.StatusForbidden, "TICKET_NOT_APPROVED"},
+		{common.ErrTicketAlreadyCheckedIn, http.StatusConflict, "TICKET_ALREADY_CHECKED_IN"},
+		{errors.New("lorem"), http.StatusBadRequest, "lorem"},
+	} {
+		s.T().Run(tt.data, func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("CheckInTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = &http.Request{Header: make(http.Header)}
+			ctx.Set("user_email", "hello@tix.id")
+			tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+				"ticket_code": "7K2M-QX4D-9PLA-ZR3T",
+			})
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.CheckIn(ctx)
+			var got struct {
+				Code int    `json:"code"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.code, got.Code)
+			s.Equal(tt.data, got.Data)
+		})
+	}
+}
+
+func (s *eventHandlerTestSuite) Test_CheckInBatch_ShouldSuccess() {
 	svcMock := new(mocks.ITixService)
-	svcMock.On("PublishExportEventDataQueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(errors.New("lorem")).Once()
+	svcMock.On("ReconcileCheckIns", mock.Anything, "asd", mock.Anything, "hello@tix.id").
+		Return(&response.CheckInBatchResponse{Total: 1, CheckedIn: 1}, nil).Once()
 	writer := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(writer)
 	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
 	ctx.Set("user_email", "hello@tix.id")
-	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+		"check_ins": []map[string]interface{}{
+			{"token": "a.b.c", "checked_in_at": 1686384000},
+		},
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.CheckInBatch(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	svcMock.AssertExpectations(s.T())
+}
+func (s *eventHandlerTestSuite) Test_CheckInBatch_ShouldError() {
+	for _, tt := range []struct {
+		name string
+		body map[string]interface{}
+		err  error
+		code int
+	}{
+		{name: "error empty batch", body: map[string]interface{}{"check_ins": []interface{}{}},
+			code: http.StatusUnprocessableEntity},
+		{name: "error missing token", body: map[string]interface{}{"check_ins": []map[string]interface{}{
+			{"checked_in_at": 1686384000},
+		}}, code: http.StatusUnprocessableEntity},
+		{name: "error service", body: map[string]interface{}{"check_ins": []map[string]interface{}{
+			{"token": "a.b.c", "checked_in_at": 1686384000},
+		}}, err: errors.New("lorem"), code: http.StatusBadRequest},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			if tt.err != nil {
+				svcMock.On("ReconcileCheckIns", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, tt.err).Once()
+			}
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = &http.Request{Header: make(http.Header)}
+			ctx.Set("user_email", "hello@tix.id")
+			tests.MockJSONRequest(ctx, "POST", "application/json", tt.body)
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.CheckInBatch(ctx)
+			s.Equal(tt.code, writer.Code)
+		})
+	}
+}
+
+func (s *eventHandlerTestSuite) Test_TicketKey_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchTicketKeyBundle", mock.Anything, "asd").
+		Return(&response.TicketKeyBundleResponse{EventID: 1, Algorithm: "EdDSA"}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.TicketKey(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+}
+func (s *eventHandlerTestSuite) Test_TicketKey_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchTicketKeyBundle", mock.Anything, "asd").
+		Return(nil, common.ErrTicketSigningDisabled).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.TicketKey(ctx)
+	s.Equal(http.StatusBadRequest, writer.Code)
+}
+
+func (s *eventHandlerTestSuite) Test_VerifyTicket_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("VerifyTicket", mock.Anything, "asd", "a.b.c").
+		Return(&response.TicketVerificationResponse{ParticipantID: 1}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+		"token": "a.b.c",
+	})
+	handler := rest.EventRESTHandler{Service: svcMock}
+	handler.VerifyTicket(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+}
+func (s *eventHandlerTestSuite) Test_VerifyTicket_ShouldError() {
+	s.T().Run("error validation", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		ctx.Request = &http.Request{Header: make(http.Header)}
+		tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{})
+		handler := rest.EventRESTHandler{Service: svcMock}
+		handler.VerifyTicket(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+	})
+	for _, tt := range []struct {
+		err  error
+		code int
+		data string
+	}{
+		{common.ErrTicketInvalid, http.StatusUnprocessableEntity, "TICKET_INVALID"},
+		{common.ErrTicketExpired, http.StatusUnprocessableEntity, "TICKET_EXPIRED"},
+		{common.ErrTicketNotFound, http.StatusNotFound, "TICKET_NOT_FOUND"},
+		{errors.New("lorem"), http.StatusBadRequest, "lorem"},
+	} {
+		s.T().Run(tt.data, func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("VerifyTicket", mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = &http.Request{Header: make(http.Header)}
+			tests.MockJSONRequest(ctx, "POST", "application/json", map[string]interface{}{
+				"token": "a.b.c",
+			})
+			handler := rest.EventRESTHandler{Service: svcMock}
+			handler.VerifyTicket(ctx)
+			var got struct {
+				Code int    `json:"code"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.data, got.Data)
+		})
+	}
+}
+
+func (s *eventHandlerTestSuite) Test_Calendar_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchEventCalendar", mock.Anything, "asd").
+		Return([]byte("BEGIN:VCALENDAR"), nil).Once()
+	// the calendar is served without the access token
+	engine := gin.New()
+	rest.NewEventRESTHandler(engine.Group("api/v1"), svcMock)
+	writer := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/asd/calendar.ics", http.NoBody)
+	engine.ServeHTTP(writer, req)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(calendar.ContentType, writer.Header().Get("Content-Type"))
+	s.Equal("BEGIN:VCALENDAR", writer.Body.String())
+	svcMock.AssertExpectations(s.T())
+}
+func (s *eventHandlerTestSuite) Test_Calendar_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchEventCalendar", mock.Anything, "asd").
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
 	handler := rest.EventRESTHandler{Service: svcMock}
-	handler.Export(ctx)
+	handler.Calendar(ctx)
 	var got wrapper.CommonRespond
 	_ = json.Unmarshal(writer.Body.Bytes(), &got)
 	s.Equal(http.StatusBadRequest, writer.Code)
 	s.Equal(http.StatusBadRequest, got.Code)
-	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
 }
 
 func TestEventHandlerService(t *testing.T) {
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/pkg/http/wrapper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ExportRESTHandler serve the archived export of the download link sent by
+// email, it is not authenticated, the signed token is the secret of the link.
+type ExportRESTHandler struct {
+	Service domain.ITixService
+}
+
+func (handler *ExportRESTHandler) Download(ctx *gin.Context) {
+	downloadToken := ctx.Query("token")
+	// the body of the archive is streamed with the opened file, so it is bound
+	// to the request only, a timeout would cut off the long download.
+	file, fileName, contentType, err := handler.Service.OpenEventExport(
+		ctx.Request.Context(), downloadToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrExportNotFound),
+			errors.Is(err, common.ErrTicketSigningDisabled):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, common.ErrExportLinkInvalid),
+			errors.Is(err, common.ErrExportLinkExpired):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusForbidden, err.Error())
+		default:
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	defer func() { _ = file.Close() }()
+	ctx.DataFromReader(http.StatusOK, -1, contentType, file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, fileName),
+	})
+}
+
+func NewExportRESTHandler(
+	router *gin.RouterGroup,
+	service domain.ITixService,
+) {
+	handler := &ExportRESTHandler{service}
+	router = router.Group("/exports")
+	router.GET("/download", handler.Download)
+}
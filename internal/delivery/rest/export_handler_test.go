@@ -0,0 +1,90 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/config"
+	"github.com/aasumitro/tix/internal/delivery/rest"
+	"github.com/aasumitro/tix/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type exportHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (s *exportHandlerTestSuite) SetupSuite() {
+	viper.Reset()
+	viper.SetConfigFile("../../../.example.env")
+	viper.SetConfigType("dotenv")
+	config.LoadEnv()
+
+	svcMock := new(mocks.ITixService)
+	eg := gin.Default().Group("test/export")
+	rest.NewExportRESTHandler(eg, svcMock)
+}
+
+func (s *exportHandlerTestSuite) Test_Download_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	// the streamed archive is not cut off by a timeout
+	noDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return !ok
+	})
+	svcMock.On("OpenEventExport", noDeadline, "lorem.ipsum.dolor").
+		Return(io.NopCloser(strings.NewReader("No,Nama\n")), "asd.csv", "text/csv; charset=utf-8", nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/?token=lorem.ipsum.dolor", http.NoBody)
+	handler := rest.ExportRESTHandler{Service: svcMock}
+	handler.Download(ctx)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal("text/csv; charset=utf-8", writer.Header().Get("Content-Type"))
+	s.Equal(`attachment; filename="asd.csv"`, writer.Header().Get("Content-Disposition"))
+	s.Equal("No,Nama\n", writer.Body.String())
+	svcMock.AssertExpectations(s.T())
+}
+func (s *exportHandlerTestSuite) Test_Download_ShouldError() {
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{common.ErrExportNotFound, http.StatusNotFound},
+		{common.ErrTicketSigningDisabled, http.StatusNotFound},
+		{common.ErrExportLinkInvalid, http.StatusForbidden},
+		{common.ErrExportLinkExpired, http.StatusForbidden},
+		{errors.New("lorem"), http.StatusBadRequest},
+	} {
+		s.T().Run(tt.err.Error(), func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("OpenEventExport", mock.Anything, mock.Anything).
+				Return(nil, "", "", tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			handler := rest.ExportRESTHandler{Service: svcMock}
+			handler.Download(ctx)
+			var got struct {
+				Code int    `json:"code"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.err.Error(), got.Data)
+		})
+	}
+}
+
+func TestExportHandlerService(t *testing.T) {
+	suite.Run(t, new(exportHandlerTestSuite))
+}
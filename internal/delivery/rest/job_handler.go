@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"context"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/config"
+	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/pkg/http/middleware"
+	"github.com/aasumitro/tix/pkg/http/wrapper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type JobRESTHandler struct {
+	Service domain.ITixService
+}
+
+func (handler *JobRESTHandler) Job(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchJob(ctxWT, int32(id))
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *JobRESTHandler) DeadLetters(ctx *gin.Context) {
+	var query request.JobRequestDeadLetters
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		wrapper.NewHTTPRespondWrapper(
+			ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchDeadLetterJobs(ctxWT, &query)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *JobRESTHandler) DeadLetter(ctx *gin.Context) {
+	id := ctx.Param("id")
+	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.FetchDeadLetterJob(ctxWT, id)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, data)
+}
+
+func (handler *JobRESTHandler) Retry(ctx *gin.Context) {
+	id := ctx.Param("id")
+	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
+	defer cancel()
+	jobID, err := handler.Service.RetryDeadLetterJob(ctxWT, id)
+	if err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusOK, map[string]string{
+		"job_id": jobID,
+	})
+}
+
+func (handler *JobRESTHandler) Discard(ctx *gin.Context) {
+	id := ctx.Param("id")
+	ctxWT, cancel := context.WithTimeout(ctx.Request.Context(), common.ContextTimeout*time.Second)
+	defer cancel()
+	if err := handler.Service.DiscardDeadLetterJob(ctxWT, id); err != nil {
+		wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	wrapper.NewHTTPRespondWrapper(ctx, http.StatusNoContent, nil)
+}
+
+func NewJobRESTHandler(
+	router *gin.RouterGroup,
+	service domain.ITixService,
+) {
+	handler := &JobRESTHandler{service}
+	router = router.Group("/jobs")
+	router.Use(middleware.Auth(config.Instance.SupabaseJWTSecret))
+	router.GET("/:id", handler.Job)
+	router.GET("/dead-letters", handler.DeadLetters)
+	router.GET("/dead-letters/:id", handler.DeadLetter)
+	router.POST("/dead-letters/:id/retry", handler.Retry)
+	router.DELETE("/dead-letters/:id", handler.Discard)
+}
@@ -0,0 +1,240 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/aasumitro/tix/config"
+	"github.com/aasumitro/tix/internal/delivery/rest"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"github.com/aasumitro/tix/mocks"
+	"github.com/aasumitro/tix/pkg/http/wrapper"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type jobHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (s *jobHandlerTestSuite) SetupSuite() {
+	viper.Reset()
+	viper.SetConfigFile("../../../.example.env")
+	viper.SetConfigType("dotenv")
+	config.LoadEnv()
+
+	svcMock := new(mocks.ITixService)
+	eg := gin.Default().Group("test/job")
+	rest.NewJobRESTHandler(eg, svcMock)
+}
+
+func (s *jobHandlerTestSuite) Test_Job_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchJob", mock.Anything, int32(1)).
+		Return(&response.JobResponse{ID: 1, Status: "queued"}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/jobs/1", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.Job(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *jobHandlerTestSuite) Test_Job_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	s.T().Run("error parse", func(t *testing.T) {
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/jobs/asd", http.NoBody)
+		ctx.Request = req
+		ctx.AddParam("id", "asd")
+		handler := rest.JobRESTHandler{Service: svcMock}
+		handler.Job(ctx)
+		var got wrapper.ErrorRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+	s.T().Run("error service", func(t *testing.T) {
+		svcMock.On("FetchJob", mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/jobs/1", http.NoBody)
+		ctx.Request = req
+		ctx.AddParam("id", "1")
+		handler := rest.JobRESTHandler{Service: svcMock}
+		handler.Job(ctx)
+		var got wrapper.ErrorRespond
+		_ = json.Unmarshal(writer.Body.Bytes(), &got)
+		s.Equal(http.StatusBadRequest, writer.Code)
+		s.Equal(http.StatusBadRequest, got.Code)
+		s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+	})
+}
+
+func (s *jobHandlerTestSuite) Test_DeadLetters_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchDeadLetterJobs", mock.Anything, &request.JobRequestDeadLetters{Cursor: "2-0", Limit: 1}).
+		Return(&response.DeadLetterJobPageResponse{Items: []*response.DeadLetterJobResponse{{
+			ID:       "1-0",
+			Queue:    "req_gen_event_tix_queue",
+			Payload:  `{"google_form_id":"asd","participant_id":1}`,
+			Attempts: 5,
+			Error:    "lorem",
+		}}, NextCursor: "1-0"}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/jobs/dead-letters?cursor=2-0&limit=1", http.NoBody)
+	ctx.Request = req
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.DeadLetters(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+	svcMock.AssertExpectations(s.T())
+}
+func (s *jobHandlerTestSuite) Test_DeadLetters_ShouldError() {
+	s.T().Run("error limit", func(t *testing.T) {
+		svcMock := new(mocks.ITixService)
+		writer := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(writer)
+		req, _ := http.NewRequest("GET", "/api/v1/jobs/dead-letters?limit=1000", http.NoBody)
+		ctx.Request = req
+		handler := rest.JobRESTHandler{Service: svcMock}
+		handler.DeadLetters(ctx)
+		s.Equal(http.StatusUnprocessableEntity, writer.Code)
+		svcMock.AssertNotCalled(t, "FetchDeadLetterJobs", mock.Anything, mock.Anything)
+	})
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchDeadLetterJobs", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/jobs/dead-letters", http.NoBody)
+	ctx.Request = req
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.DeadLetters(ctx)
+	var got wrapper.ErrorRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *jobHandlerTestSuite) Test_DeadLetter_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchDeadLetterJob", mock.Anything, "1-0").
+		Return(&response.DeadLetterJobResponse{ID: "1-0"}, nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/jobs/dead-letters/1-0", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.DeadLetter(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *jobHandlerTestSuite) Test_DeadLetter_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("FetchDeadLetterJob", mock.Anything, mock.Anything).
+		Return(nil, errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("GET", "/api/v1/jobs/dead-letters/1-0", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.DeadLetter(ctx)
+	var got wrapper.ErrorRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *jobHandlerTestSuite) Test_Retry_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("RetryDeadLetterJob", mock.Anything, "1-0").
+		Return("2-0", nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("POST", "/api/v1/jobs/dead-letters/1-0/retry", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.Retry(ctx)
+	var got wrapper.CommonRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(http.StatusOK, got.Code)
+	s.Equal(http.StatusText(http.StatusOK), got.Status)
+}
+func (s *jobHandlerTestSuite) Test_Retry_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("RetryDeadLetterJob", mock.Anything, mock.Anything).
+		Return("", errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("POST", "/api/v1/jobs/dead-letters/1-0/retry", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.Retry(ctx)
+	var got wrapper.ErrorRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func (s *jobHandlerTestSuite) Test_Discard_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("DiscardDeadLetterJob", mock.Anything, "1-0").Return(nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("DELETE", "/api/v1/jobs/dead-letters/1-0", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.Discard(ctx)
+	s.Equal(http.StatusNoContent, writer.Code)
+}
+func (s *jobHandlerTestSuite) Test_Discard_ShouldError() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("DiscardDeadLetterJob", mock.Anything, mock.Anything).
+		Return(errors.New("lorem")).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	req, _ := http.NewRequest("DELETE", "/api/v1/jobs/dead-letters/1-0", http.NoBody)
+	ctx.Request = req
+	ctx.AddParam("id", "1-0")
+	handler := rest.JobRESTHandler{Service: svcMock}
+	handler.Discard(ctx)
+	var got wrapper.ErrorRespond
+	_ = json.Unmarshal(writer.Body.Bytes(), &got)
+	s.Equal(http.StatusBadRequest, writer.Code)
+	s.Equal(http.StatusBadRequest, got.Code)
+	s.Equal(http.StatusText(http.StatusBadRequest), got.Status)
+}
+
+func TestJobHandlerService(t *testing.T) {
+	suite.Run(t, new(jobHandlerTestSuite))
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/pkg/http/wrapper"
+	"github.com/aasumitro/tix/pkg/wallet"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// TicketRESTHandler serve the participant facing ticket links, they are
+// not authenticated, the ticket code or the signed token is the secret of the link.
+type TicketRESTHandler struct {
+	Service domain.ITixService
+}
+
+func (handler *TicketRESTHandler) WalletPass(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	ticketCode := ctx.Param("ticket_code")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.GenerateWalletPass(ctxWT, googleFormID, ticketCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrTicketNotFound),
+			errors.Is(err, common.ErrWalletPassDisabled):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, common.ErrParticipantNotApproved):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusForbidden, err.Error())
+		default:
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="ticket.pkpass"`)
+	ctx.Data(http.StatusOK, wallet.ContentType, data)
+}
+
+func (handler *TicketRESTHandler) Download(ctx *gin.Context) {
+	googleFormID := ctx.Param("google_form_id")
+	downloadToken := ctx.Query("token")
+	ctxWT, cancel := context.WithTimeout(
+		ctx.Request.Context(),
+		common.ContextTimeout*time.Second)
+	defer cancel()
+	data, err := handler.Service.DownloadTicketByToken(ctxWT, googleFormID, downloadToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrTicketNotFound),
+			errors.Is(err, common.ErrTicketSigningDisabled):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, common.ErrTicketInvalid),
+			errors.Is(err, common.ErrTicketExpired),
+			errors.Is(err, common.ErrParticipantNotApproved):
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusForbidden, err.Error())
+		default:
+			wrapper.NewHTTPRespondWrapper(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="ticket.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", data)
+}
+
+func NewTicketRESTHandler(
+	router *gin.RouterGroup,
+	service domain.ITixService,
+) {
+	handler := &TicketRESTHandler{service}
+	router = router.Group("/tickets")
+	router.GET("/:google_form_id/:ticket_code/pass", handler.WalletPass)
+	router.GET("/:google_form_id/ticket.pdf", handler.Download)
+}
@@ -0,0 +1,130 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/config"
+	"github.com/aasumitro/tix/internal/delivery/rest"
+	"github.com/aasumitro/tix/mocks"
+	"github.com/aasumitro/tix/pkg/wallet"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ticketHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (s *ticketHandlerTestSuite) SetupSuite() {
+	viper.Reset()
+	viper.SetConfigFile("../../../.example.env")
+	viper.SetConfigType("dotenv")
+	config.LoadEnv()
+
+	svcMock := new(mocks.ITixService)
+	eg := gin.Default().Group("test/ticket")
+	rest.NewTicketRESTHandler(eg, svcMock)
+}
+
+func (s *ticketHandlerTestSuite) Test_WalletPass_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("GenerateWalletPass", mock.Anything, "asd", "7K2M-QX4D-9PLA-ZR3T").
+		Return([]byte("lorem"), nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = &http.Request{Header: make(http.Header)}
+	ctx.AddParam("google_form_id", "asd")
+	ctx.AddParam("ticket_code", "7K2M-QX4D-9PLA-ZR3T")
+	handler := rest.TicketRESTHandler{Service: svcMock}
+	handler.WalletPass(ctx)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal(wallet.ContentType, writer.Header().Get("Content-Type"))
+	s.Contains(writer.Header().Get("Content-Disposition"), "ticket.pkpass")
+	s.Equal("lorem", writer.Body.String())
+}
+func (s *ticketHandlerTestSuite) Test_WalletPass_ShouldError() {
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{common.ErrTicketNotFound, http.StatusNotFound},
+		{common.ErrWalletPassDisabled, http.StatusNotFound},
+		{common.ErrParticipantNotApproved, http.StatusForbidden},
+		{errors.New("lorem"), http.StatusBadRequest},
+	} {
+		s.T().Run(tt.err.Error(), func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("GenerateWalletPass", mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = &http.Request{Header: make(http.Header)}
+			handler := rest.TicketRESTHandler{Service: svcMock}
+			handler.WalletPass(ctx)
+			var got struct {
+				Code int    `json:"code"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.err.Error(), got.Data)
+		})
+	}
+}
+
+func (s *ticketHandlerTestSuite) Test_Download_ShouldSuccess() {
+	svcMock := new(mocks.ITixService)
+	svcMock.On("DownloadTicketByToken", mock.Anything, "asd", "lorem.ipsum.dolor").
+		Return([]byte("%PDF-lorem"), nil).Once()
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/?token=lorem.ipsum.dolor", http.NoBody)
+	ctx.AddParam("google_form_id", "asd")
+	handler := rest.TicketRESTHandler{Service: svcMock}
+	handler.Download(ctx)
+	s.Equal(http.StatusOK, writer.Code)
+	s.Equal("application/pdf", writer.Header().Get("Content-Type"))
+	s.Contains(writer.Header().Get("Content-Disposition"), "ticket.pdf")
+	s.Equal("%PDF-lorem", writer.Body.String())
+}
+func (s *ticketHandlerTestSuite) Test_Download_ShouldError() {
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{common.ErrTicketNotFound, http.StatusNotFound},
+		{common.ErrTicketSigningDisabled, http.StatusNotFound},
+		{common.ErrTicketInvalid, http.StatusForbidden},
+		{common.ErrTicketExpired, http.StatusForbidden},
+		{common.ErrParticipantNotApproved, http.StatusForbidden},
+		{errors.New("lorem"), http.StatusBadRequest},
+	} {
+		s.T().Run(tt.err.Error(), func(t *testing.T) {
+			svcMock := new(mocks.ITixService)
+			svcMock.On("DownloadTicketByToken", mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, tt.err).Once()
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			handler := rest.TicketRESTHandler{Service: svcMock}
+			handler.Download(ctx)
+			var got struct {
+				Code int    `json:"code"`
+				Data string `json:"data"`
+			}
+			_ = json.Unmarshal(writer.Body.Bytes(), &got)
+			s.Equal(tt.code, writer.Code)
+			s.Equal(tt.err.Error(), got.Data)
+		})
+	}
+}
+
+func TestTicketHandlerService(t *testing.T) {
+	suite.Run(t, new(ticketHandlerTestSuite))
+}
@@ -2,11 +2,13 @@ package domain
 
 import (
 	"context"
+	"database/sql"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain/entity"
 	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
 	"google.golang.org/api/forms/v1"
+	"io"
 )
 
 type (
@@ -32,10 +34,34 @@ type (
 		) (*forms.Form, error)
 		GetResponses(
 			ctx context.Context,
-			formID string,
+			formID, submittedAfter string,
 		) (*forms.ListFormResponsesResponse, error)
 	}
 
+	// JobHandler process a single queue message, the message
+	// is acknowledged once the handler return.
+	JobHandler func(ctx context.Context, message *entity.QueueMessage) error
+
+	IJobQueue interface {
+		Publish(
+			ctx context.Context,
+			queue string,
+			payload []byte,
+		) (id string, err error)
+		Consume(
+			ctx context.Context,
+			queue string,
+			handler JobHandler,
+		) error
+
+		// ListDeadLetters list the newest dead-lettered jobs older than the
+		// cursor (the id of the last listed job), from the newest when it is empty.
+		ListDeadLetters(ctx context.Context, cursor string, count int64) (jobs []*entity.DeadLetterJob, err error)
+		GetDeadLetter(ctx context.Context, id string) (job *entity.DeadLetterJob, err error)
+		RetryDeadLetter(ctx context.Context, id string) (jobID string, err error)
+		DiscardDeadLetter(ctx context.Context, id string) error
+	}
+
 	IPostgreSQLRepository interface {
 		CountUsers(ctx context.Context) int
 		GetAllUsers(ctx context.Context, email string) (users []*entity.User, err error)
@@ -46,6 +72,10 @@ type (
 		GetAllEvents(ctx context.Context) (events []*entity.Event, err error)
 		GetEventByGoogleFormID(ctx context.Context, googleFormID string) (event *entity.Event, err error)
 		InsertNewEvent(ctx context.Context, param *request.EventRequestMakeNew) (event *entity.Event, err error)
+		UpdateEventFieldMapping(ctx context.Context, googleFormID string, mapping entity.FieldMapping) error
+		UpdateEventBranding(ctx context.Context, googleFormID string, branding entity.Branding) error
+		UpdateEventTicketTemplate(ctx context.Context, googleFormID string, template sql.NullString) error
+		UpdateEventLastRespondSyncedAt(ctx context.Context, eventID int32, lastSubmittedTime string) error
 
 		CountParticipants(
 			ctx context.Context,
@@ -69,6 +99,13 @@ type (
 			participant *entity.Participant,
 			err error,
 		)
+		GetParticipantByResponseIDAndEventID(
+			ctx context.Context,
+			responseID string, eventID int32,
+		) (
+			participant *entity.Participant,
+			err error,
+		)
 		GetParticipantByIDAndEventID(
 			ctx context.Context,
 			participantID, eventID int32,
@@ -76,6 +113,19 @@ type (
 			participant *entity.Participant,
 			err error,
 		)
+		GetParticipantByTicketCode(
+			ctx context.Context,
+			ticketCode string,
+		) (
+			participant *entity.Participant,
+			err error,
+		)
+		CheckInParticipant(
+			ctx context.Context,
+			id int32,
+			checkedInAt int64,
+			checkedInBy string,
+		) error
 		InsertManyParticipants(
 			ctx context.Context,
 			participants []*entity.Participant,
@@ -87,6 +137,69 @@ type (
 			declinedReason *string,
 			id int32,
 		) error
+		UpdateParticipantTicketCode(
+			ctx context.Context,
+			id int32,
+			ticketCode string,
+		) (
+			storedTicketCode string,
+			err error,
+		)
+		GetTicketRecipientIDs(
+			ctx context.Context,
+			eventID int32,
+			unsentOnly bool,
+		) (
+			ids []int32,
+			err error,
+		)
+		UpdateParticipantTicketSentAt(
+			ctx context.Context,
+			id int32,
+			sentAt int64,
+		) error
+		BackfillParticipantResponse(
+			ctx context.Context,
+			participant *entity.Participant,
+		) error
+		UpdateParticipantResponse(
+			ctx context.Context,
+			participant *entity.Participant,
+			changes []*entity.ParticipantChange,
+			resetStatus bool,
+			updatedAt int64,
+		) error
+		GetParticipantChanges(
+			ctx context.Context,
+			eventID, participantID int32,
+		) (
+			changes []*entity.ParticipantChange,
+			err error,
+		)
+
+		InsertJob(ctx context.Context, job *entity.Job) (id int32, err error)
+		UpdateJob(ctx context.Context, job *entity.Job, updatedAt int64) error
+		GetJobByID(ctx context.Context, id int32) (job *entity.Job, err error)
+		GetJobsByEventID(ctx context.Context, eventID, limit int32) (jobs []*entity.Job, err error)
+
+		InsertTicketDeliveries(
+			ctx context.Context,
+			jobID int32,
+			participantIDs []int32,
+			createdAt int64,
+		) error
+		GetTicketDeliveriesByJobID(
+			ctx context.Context,
+			jobID int32,
+		) (
+			deliveries []*entity.TicketDelivery,
+			err error,
+		)
+		SaveTicketDelivery(
+			ctx context.Context,
+			delivery *entity.TicketDelivery,
+			updatedAt int64,
+		) error
 	}
 
 	ITixService interface {
@@ -96,12 +209,44 @@ type (
 		) (items []*response.GoogleFormQuestion, err error)
 		FetchResponds(
 			ctx context.Context,
-			formID string,
+			formID, submittedAfter string,
 		) (items []*response.GoogleFormRespond, err error)
 		SyncRespondData(
 			ctx context.Context,
 			formID string,
 		) error
+		FetchFieldMapping(
+			ctx context.Context,
+			googleFormID string,
+		) (item *response.EventFieldMappingResponse, err error)
+		UpdateFieldMapping(
+			ctx context.Context,
+			googleFormID string,
+			form *request.EventRequestFieldMapping,
+		) (item *response.EventFieldMappingResponse, err error)
+		FetchEventBranding(
+			ctx context.Context,
+			googleFormID string,
+		) (item *response.EventBrandingResponse, err error)
+		UpdateEventBranding(
+			ctx context.Context,
+			googleFormID string,
+			form *request.EventRequestBranding,
+		) (item *response.EventBrandingResponse, err error)
+		FetchTicketTemplate(
+			ctx context.Context,
+			googleFormID string,
+		) (item *response.EventTicketTemplateResponse, err error)
+		UpdateTicketTemplate(
+			ctx context.Context,
+			googleFormID string,
+			form *request.EventRequestTicketTemplate,
+		) (item *response.EventTicketTemplateResponse, err error)
+		PreviewTicketTemplate(
+			ctx context.Context,
+			googleFormID string,
+			form *request.EventRequestTicketTemplate,
+		) ([]byte, error)
 
 		FetchEvents(ctx context.Context) (
 			items []*response.EventResponse,
@@ -121,10 +266,49 @@ type (
 		FetchParticipants(
 			ctx context.Context,
 			googleFormID string,
+			filter *request.EventRequestParticipantFilter,
 		) (
 			items []*response.ParticipantResponse,
 			err error,
 		)
+		FetchParticipantChanges(
+			ctx context.Context,
+			googleFormID string,
+			participantID int32,
+		) (
+			items []*response.ParticipantChangeResponse,
+			err error,
+		)
+		CheckInTicket(
+			ctx context.Context,
+			googleFormID, ticketCode, checkedInBy string,
+		) (
+			item *response.CheckInResponse,
+			err error,
+		)
+		ReconcileCheckIns(
+			ctx context.Context,
+			googleFormID string,
+			checkIns []*request.EventRequestOfflineCheckIn,
+			checkedInBy string,
+		) (
+			item *response.CheckInBatchResponse,
+			err error,
+		)
+		FetchTicketKeyBundle(
+			ctx context.Context,
+			googleFormID string,
+		) (
+			item *response.TicketKeyBundleResponse,
+			err error,
+		)
+		VerifyTicket(
+			ctx context.Context,
+			googleFormID, ticketToken string,
+		) (
+			item *response.TicketVerificationResponse,
+			err error,
+		)
 
 		GenerateMagicLink(
 			ctx context.Context,
@@ -158,25 +342,119 @@ type (
 		PublishSyncEventDataQueue(
 			ctx context.Context,
 			googleFormID string,
-		) error
+		) (jobID int32, err error)
 		PublishExportEventDataQueue(
 			ctx context.Context,
 			googleFormID, exportType, email string,
-		) error
+			form *request.EventRequestExport,
+		) (jobID int32, err error)
 		PublishGenerateEventTicketQueue(
 			ctx context.Context,
 			googleFormID string,
 			participantID int32,
+		) (jobID int32, err error)
+		PublishGenerateEventTicketsQueue(
+			ctx context.Context,
+			googleFormID, filter string,
+		) (jobID int32, err error)
+
+		SubscribeEventNotifications(
+			ctx context.Context,
+			googleFormID string,
+		) (<-chan *response.EventNotificationResponse, error)
+
+		FetchJob(
+			ctx context.Context,
+			id int32,
+		) (item *response.JobResponse, err error)
+		FetchEventJobs(
+			ctx context.Context,
+			googleFormID string,
+		) (
+			items []*response.JobResponse,
+			err error,
+		)
+		UpdateJobStatus(
+			ctx context.Context,
+			id int32,
+			status common.JobStatus,
+			attempt int,
+			result, errorText string,
+		) error
+
+		FetchDeadLetterJobs(
+			ctx context.Context,
+			form *request.JobRequestDeadLetters,
+		) (
+			item *response.DeadLetterJobPageResponse,
+			err error,
+		)
+		FetchDeadLetterJob(
+			ctx context.Context,
+			id string,
+		) (item *response.DeadLetterJobResponse, err error)
+		RetryDeadLetterJob(
+			ctx context.Context,
+			id string,
+		) (jobID string, err error)
+		DiscardDeadLetterJob(
+			ctx context.Context,
+			id string,
 		) error
 
 		ExportEvent(
 			ctx context.Context,
 			googleFormID, exportFileType, targetEmail string,
+			form *request.EventRequestExport,
+		) error
+		StreamEventExport(
+			ctx context.Context,
+			googleFormID, exportFileType string,
+			form *request.EventRequestExport,
+			w io.Writer,
 		) error
+		OpenEventExport(
+			ctx context.Context,
+			downloadToken string,
+		) (
+			file io.ReadCloser,
+			fileName, contentType string,
+			err error,
+		)
 		GenerateTicket(
 			ctx context.Context,
 			googleFormID string,
 			participantID int32,
 		) error
+		GenerateEventTickets(
+			ctx context.Context,
+			googleFormID string,
+			jobID int32,
+			filter string,
+		) (item *response.TicketDeliveryReportResponse, err error)
+		FetchTicketDeliveries(
+			ctx context.Context,
+			googleFormID string,
+			jobID int32,
+		) (item *response.TicketDeliveryReportResponse, err error)
+		GenerateWalletPass(
+			ctx context.Context,
+			googleFormID, ticketCode string,
+		) ([]byte, error)
+		FetchEventCalendar(
+			ctx context.Context,
+			googleFormID string,
+		) ([]byte, error)
+		DownloadTicket(
+			ctx context.Context,
+			googleFormID string,
+			participantID int32,
+		) ([]byte, error)
+		DownloadTicketByToken(
+			ctx context.Context,
+			googleFormID, downloadToken string,
+		) ([]byte, error)
+
+		Shutdown(ctx context.Context) error
 	}
 )
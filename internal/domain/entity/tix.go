@@ -1,6 +1,11 @@
 package entity
 
-import "database/sql"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
 
 type (
 	User struct {
@@ -14,30 +19,190 @@ type (
 	}
 
 	Event struct {
-		ID                int32
-		GoogleFormID      string
-		Name              string
-		Location          string
-		PreregisterDate   int32
-		EventDate         int32
-		TotalParticipants int32
-		CreatedAt         sql.NullInt32
-		UpdatedAt         sql.NullInt32
+		ID                  int32
+		GoogleFormID        string
+		Name                string
+		Location            string
+		PreregisterDate     int32
+		EventDate           int32
+		TotalParticipants   int32
+		FieldMapping        FieldMapping
+		Branding            Branding
+		TicketTemplate      sql.NullString
+		LastRespondSyncedAt sql.NullString
+		CreatedAt           sql.NullInt32
+		UpdatedAt           sql.NullInt32
 	}
 
 	Participant struct {
-		ID             int32
-		EventID        int32
-		Name           string
-		Email          string
-		Phone          string
-		Job            string
-		PoP            string
-		DoB            string
-		ApprovedAt     sql.NullInt32
-		DeclinedAt     sql.NullInt32
-		DeclinedReason sql.NullString
-		CreatedAt      sql.NullInt32
-		UpdatedAt      sql.NullInt32
+		ID                 int32
+		EventID            int32
+		Name               string
+		Email              string
+		Phone              string
+		Job                string
+		PoP                string
+		DoB                string
+		ApprovedAt         sql.NullInt32
+		DeclinedAt         sql.NullInt32
+		DeclinedReason     sql.NullString
+		CustomAnswers      CustomAnswers
+		ResponseID         sql.NullString
+		LastSubmittedTime  sql.NullString
+		TicketCode         sql.NullString
+		CheckedInAt        sql.NullInt32
+		CheckedInBy        sql.NullString
+		TicketSentAt       sql.NullInt32
+		LastTicketDelivery LastTicketDelivery
+		CreatedAt          sql.NullInt32
+		UpdatedAt          sql.NullInt32
+	}
+
+	// LastTicketDelivery is the result of the latest attempt to send
+	// the ticket, every field is null when the ticket is never sent.
+	LastTicketDelivery struct {
+		Status      sql.NullString
+		Error       sql.NullString
+		MessageID   sql.NullString
+		AttemptedAt sql.NullInt32
+	}
+
+	// ParticipantChange records a single field updated by an edited google form response.
+	ParticipantChange struct {
+		ID            int32
+		EventID       int32
+		ParticipantID int32
+		Field         string
+		OldValue      string
+		NewValue      string
+		CreatedAt     sql.NullInt32
+	}
+
+	// QueueMessage is a job delivered by the durable work queue.
+	QueueMessage struct {
+		ID          string
+		Queue       string
+		Payload     []byte
+		Attempt     int
+		MaxAttempts int
+	}
+
+	// Job tracks the lifecycle of a published queue message.
+	Job struct {
+		ID         int32
+		EventID    int32
+		Queue      string
+		Status     string
+		Payload    string
+		Attempts   int32
+		Error      sql.NullString
+		Result     sql.NullString
+		QueuedAt   sql.NullInt32
+		StartedAt  sql.NullInt32
+		FinishedAt sql.NullInt32
+	}
+
+	// TicketDelivery records an attempt to send the ticket to a participant,
+	// the job is null when the ticket is not sent by a tracked job.
+	TicketDelivery struct {
+		ID               int32
+		JobID            sql.NullInt32
+		ParticipantID    int32
+		ParticipantName  string
+		ParticipantEmail string
+		Status           string
+		Error            sql.NullString
+		MessageID        sql.NullString
+		// Attempts count the attempts to send the ticket, only the
+		// status, error and message id of the last one are kept
+		Attempts    int32
+		AttemptedAt sql.NullInt32
+		UpdatedAt   sql.NullInt32
+	}
+
+	// DeadLetterJob is a queue message that exhausted its retry policy.
+	DeadLetterJob struct {
+		ID       string
+		Queue    string
+		Payload  []byte
+		Attempts int
+		Error    string
+		FailedAt int64
+	}
+
+	// FieldMapping binds a google form question id (key)
+	// to a participant field (value), stored as JSONB.
+	FieldMapping map[string]string
+
+	// Branding customise the tickets, the exports and the emails of the
+	// event, stored as JSONB. An empty field use the default tix branding.
+	Branding struct {
+		LogoURL        string `json:"logo_url,omitempty"`
+		PrimaryColor   string `json:"primary_color,omitempty"`
+		SecondaryColor string `json:"secondary_color,omitempty"`
+		HeaderTitle    string `json:"header_title,omitempty"`
+		HeaderSubtitle string `json:"header_subtitle,omitempty"`
+		FooterText     string `json:"footer_text,omitempty"`
+		SenderName     string `json:"sender_name,omitempty"`
+		SenderEmail    string `json:"sender_email,omitempty"`
+		WebsiteURL     string `json:"website_url,omitempty"`
+		// Locale is the language of the exports, the tickets and the emails, see i18n.Locales
+		Locale string `json:"locale,omitempty"`
+	}
+
+	// CustomAnswers keep every google form answer that is not bound
+	// to a participant field, keyed by the google form question id.
+	CustomAnswers map[string]*CustomAnswer
+
+	CustomAnswer struct {
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+		Position int    `json:"position"`
 	}
 )
+
+func (mapping *FieldMapping) Scan(value any) error {
+	return scanJSON(value, mapping)
+}
+
+func (mapping FieldMapping) Value() (driver.Value, error) {
+	if mapping == nil {
+		return nil, nil
+	}
+	return json.Marshal(mapping)
+}
+
+func (answers *CustomAnswers) Scan(value any) error {
+	return scanJSON(value, answers)
+}
+
+func (answers CustomAnswers) Value() (driver.Value, error) {
+	if answers == nil {
+		return nil, nil
+	}
+	return json.Marshal(answers)
+}
+
+func (branding *Branding) Scan(value any) error {
+	return scanJSON(value, branding)
+}
+
+func (branding Branding) Value() (driver.Value, error) {
+	if branding == (Branding{}) {
+		return nil, nil
+	}
+	return json.Marshal(branding)
+}
+
+func scanJSON(value, dest any) error {
+	switch data := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	default:
+		return errors.New("unsupported json column type")
+	}
+}
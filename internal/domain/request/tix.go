@@ -27,7 +27,88 @@ type (
 		DeclinedReason string `json:"declined_reason,omitempty" form:"declined_reason,omitempty"`
 	}
 
+	EventRequestCheckIn struct {
+		TicketCode string `json:"ticket_code" form:"ticket_code" binding:"required"`
+	}
+
+	EventRequestVerifyTicket struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+
+	EventRequestCheckInBatch struct {
+		CheckIns []*EventRequestOfflineCheckIn `json:"check_ins" binding:"required,min=1,dive"`
+	}
+
+	// EventRequestOfflineCheckIn is a ticket token scanned by an offline gate,
+	// checked_in_at is the unix time of the scan.
+	EventRequestOfflineCheckIn struct {
+		Token       string `json:"token" binding:"required"`
+		CheckedInAt int64  `json:"checked_in_at" binding:"required"`
+	}
+
 	EventValidationRequest struct {
 		GoogleFormID string `json:"google_form_id" form:"google_form_id" binding:"required"`
 	}
+
+	EventRequestFieldMapping struct {
+		// Mapping of google form question id to participant field
+		Mapping map[string]string `json:"mapping" binding:"required"`
+	}
+
+	// EventRequestBranding replace the branding of the event,
+	// an empty field fallback to the default tix branding.
+	EventRequestBranding struct {
+		LogoURL        string `json:"logo_url" binding:"omitempty,url,max=255"`
+		PrimaryColor   string `json:"primary_color" binding:"omitempty,hexcolor"`
+		SecondaryColor string `json:"secondary_color" binding:"omitempty,hexcolor"`
+		HeaderTitle    string `json:"header_title" binding:"max=255"`
+		HeaderSubtitle string `json:"header_subtitle" binding:"max=255"`
+		FooterText     string `json:"footer_text" binding:"max=255"`
+		SenderName     string `json:"sender_name" binding:"max=255"`
+		SenderEmail    string `json:"sender_email" binding:"omitempty,email,max=255"`
+		WebsiteURL     string `json:"website_url" binding:"omitempty,url,max=255"`
+		Locale         string `json:"locale" binding:"omitempty,oneof=id en"`
+	}
+
+	// EventRequestTicketTemplate is the ticket layout of the event,
+	// an empty template fallback to the built-in ticket layout.
+	EventRequestTicketTemplate struct {
+		Template string `json:"template" binding:"max=65536"`
+	}
+
+	// EventRequestParticipantFilter narrow down the participant list,
+	// e.g. ticket_delivery=failed list the approved participant whose ticket failed.
+	EventRequestParticipantFilter struct {
+		TicketDelivery string `form:"ticket_delivery" binding:"omitempty,oneof=sent failed"`
+	}
+
+	// JobRequestDeadLetters page through the dead-lettered jobs from the newest,
+	// the cursor is the next_cursor of the previous page.
+	JobRequestDeadLetters struct {
+		Cursor string `form:"cursor" binding:"max=64"`
+		Limit  int64  `form:"limit" binding:"omitempty,min=1,max=100"`
+	}
+
+	// EventRequestBulkTicket choose the participants receiving the ticket,
+	// every approved participant when the filter is not provided.
+	EventRequestBulkTicket struct {
+		Filter string `json:"filter" binding:"omitempty,oneof=approved unsent"`
+	}
+
+	// EventRequestExport tune the export file, e.g. bom=true prepend the UTF-8
+	// byte order mark to the csv export so excel does not garble it.
+	// The filters narrow down the exported participants, registered_from and
+	// registered_to are unix times, columns is the comma separated list of
+	// common.EventExportColumns written in the given order. The locale replace
+	// the locale of the event branding for this export only.
+	EventRequestExport struct {
+		BOM            bool   `json:"bom" form:"bom"`
+		Locale         string `json:"locale" form:"locale" binding:"omitempty,oneof=id en"`
+		Status         string `json:"status" form:"status" binding:"omitempty,oneof=approved declined waiting"`
+		CheckedIn      *bool  `json:"checked_in" form:"checked_in"`
+		RegisteredFrom int64  `json:"registered_from" form:"registered_from" binding:"gte=0"`
+		RegisteredTo   int64  `json:"registered_to" form:"registered_to" binding:"omitempty,gtefield=RegisteredFrom"`
+		Search         string `json:"search" form:"search" binding:"max=255"`
+		Columns        string `json:"columns" form:"columns" binding:"max=255"`
+	}
 )
@@ -14,6 +14,39 @@ type (
 	GoogleFormQuestion struct {
 		ID    string `json:"id"`
 		Title string `json:"title"`
+		Field string `json:"field,omitempty"`
+	}
+
+	EventFieldMappingResponse struct {
+		GoogleFormID string                `json:"google_form_id"`
+		IsSuggested  bool                  `json:"is_suggested"`
+		Questions    []*GoogleFormQuestion `json:"questions"`
+	}
+
+	// EventBrandingResponse is the branding applied to the event,
+	// is_default tell the event does not customise any of it.
+	EventBrandingResponse struct {
+		GoogleFormID   string `json:"google_form_id"`
+		IsDefault      bool   `json:"is_default"`
+		LogoURL        string `json:"logo_url"`
+		PrimaryColor   string `json:"primary_color"`
+		SecondaryColor string `json:"secondary_color"`
+		HeaderTitle    string `json:"header_title"`
+		HeaderSubtitle string `json:"header_subtitle"`
+		FooterText     string `json:"footer_text"`
+		SenderName     string `json:"sender_name"`
+		SenderEmail    string `json:"sender_email"`
+		WebsiteURL     string `json:"website_url"`
+		Locale         string `json:"locale"`
+	}
+
+	// EventTicketTemplateResponse is the ticket layout of the event, the
+	// template is the built-in layout when is_default is true.
+	EventTicketTemplateResponse struct {
+		GoogleFormID string   `json:"google_form_id"`
+		IsDefault    bool     `json:"is_default"`
+		Template     string   `json:"template"`
+		Placeholders []string `json:"placeholders"`
 	}
 
 	GoogleFormRespond struct {
@@ -30,6 +63,15 @@ type (
 		Name  string `json:"name"`
 		Phone string `json:"phone"`
 		Job   string `json:"job"`
+		// Custom answers for questions that are not bound to a participant field
+		Custom []*ParticipantCustomAnswer `json:"custom"`
+	}
+
+	ParticipantCustomAnswer struct {
+		QuestionID string `json:"question_id"`
+		Question   string `json:"question"`
+		Answer     string `json:"answer"`
+		Position   int    `json:"position"`
 	}
 
 	SupabaseRespond struct {
@@ -49,18 +91,85 @@ type (
 	}
 
 	ParticipantResponse struct {
-		ID             int32  `json:"id"`
-		EventID        int32  `json:"event_id"`
-		Name           string `json:"name"`
-		Email          string `json:"email"`
-		Phone          string `json:"phone"`
-		Job            string `json:"job"`
-		PoP            string `json:"prof_of_payment"`
-		DoB            string `json:"date_of_birth"`
-		ApprovedAt     *int32 `json:"approved_at"`
-		DeclinedAt     *int32 `json:"declined_at"`
-		DeclinedReason string `json:"declined_reason"`
-		Status         string `json:"status"`
+		ID             int32                      `json:"id"`
+		EventID        int32                      `json:"event_id"`
+		Name           string                     `json:"name"`
+		Email          string                     `json:"email"`
+		Phone          string                     `json:"phone"`
+		Job            string                     `json:"job"`
+		PoP            string                     `json:"prof_of_payment"`
+		DoB            string                     `json:"date_of_birth"`
+		ApprovedAt     *int32                     `json:"approved_at"`
+		DeclinedAt     *int32                     `json:"declined_at"`
+		DeclinedReason string                     `json:"declined_reason"`
+		Status         string                     `json:"status"`
+		TicketCode     string                     `json:"ticket_code"`
+		CheckedInAt    *int32                     `json:"checked_in_at"`
+		CheckedInBy    string                     `json:"checked_in_by"`
+		TicketSentAt   *int32                     `json:"ticket_sent_at"`
+		TicketDelivery *ParticipantTicketDelivery `json:"ticket_delivery"`
+		CustomAnswers  []*ParticipantCustomAnswer `json:"custom_answers"`
+	}
+
+	// ParticipantTicketDelivery is the latest attempt to send the ticket,
+	// it is null when the ticket is never sent.
+	ParticipantTicketDelivery struct {
+		Status      string `json:"status"`
+		Error       string `json:"error"`
+		MessageID   string `json:"message_id"`
+		AttemptedAt int32  `json:"attempted_at"`
+	}
+
+	CheckInResponse struct {
+		ParticipantID int32  `json:"participant_id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		TicketCode    string `json:"ticket_code"`
+		CheckedInAt   int32  `json:"checked_in_at"`
+		CheckedInBy   string `json:"checked_in_by"`
+	}
+
+	OfflineCheckInResponse struct {
+		Index         int    `json:"index"`
+		ParticipantID int32  `json:"participant_id"`
+		Status        string `json:"status"`
+		Code          string `json:"code,omitempty"`
+		CheckedInAt   int32  `json:"checked_in_at"`
+	}
+
+	CheckInBatchResponse struct {
+		Total      int                       `json:"total"`
+		CheckedIn  int                       `json:"checked_in"`
+		Duplicates int                       `json:"duplicates"`
+		Rejected   int                       `json:"rejected"`
+		Items      []*OfflineCheckInResponse `json:"items"`
+	}
+
+	TicketKeyBundleResponse struct {
+		EventID      int32  `json:"event_id"`
+		GoogleFormID string `json:"google_form_id"`
+		Algorithm    string `json:"algorithm"`
+		PublicKey    string `json:"public_key"`
+		ExpiresAt    int64  `json:"expires_at"`
+	}
+
+	TicketVerificationResponse struct {
+		ParticipantID int32  `json:"participant_id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		TicketCode    string `json:"ticket_code"`
+		Status        string `json:"status"`
+		CheckedInAt   *int32 `json:"checked_in_at"`
+		ExpiresAt     int64  `json:"expires_at"`
+	}
+
+	ParticipantChangeResponse struct {
+		ID            int32  `json:"id"`
+		ParticipantID int32  `json:"participant_id"`
+		Field         string `json:"field"`
+		OldValue      string `json:"old_value"`
+		NewValue      string `json:"new_value"`
+		CreatedAt     int32  `json:"created_at"`
 	}
 
 	WeeklyOverviewResponse struct {
@@ -73,10 +182,70 @@ type (
 		TotalApprovedParticipant        int                       `json:"total_approved_participant"`
 		TotalWaitingApprovalParticipant int                       `json:"total_waiting_approval_participant"`
 		TotalDeclinedParticipant        int                       `json:"total_declined_participant"`
+		TotalCheckedInParticipant       int                       `json:"total_checked_in_participant"`
 		WeeklyOverview                  []*WeeklyOverviewResponse `json:"weekly_overview"`
 		LatestRespondents               []*ParticipantResponse    `json:"latest_respondents"`
 	}
 
+	DeadLetterJobResponse struct {
+		ID       string `json:"id"`
+		Queue    string `json:"queue"`
+		Payload  string `json:"payload"`
+		Attempts int    `json:"attempts"`
+		Error    string `json:"error"`
+		FailedAt int64  `json:"failed_at"`
+	}
+
+	// DeadLetterJobPageResponse is a page of the dead-lettered jobs, the
+	// next_cursor is empty on the last page.
+	DeadLetterJobPageResponse struct {
+		Items      []*DeadLetterJobResponse `json:"items"`
+		NextCursor string                   `json:"next_cursor"`
+	}
+
+	JobResponse struct {
+		ID         int32  `json:"id"`
+		EventID    int32  `json:"event_id"`
+		Queue      string `json:"queue"`
+		Status     string `json:"status"`
+		Attempts   int32  `json:"attempts"`
+		Error      string `json:"error"`
+		Result     string `json:"result"`
+		QueuedAt   int32  `json:"queued_at"`
+		StartedAt  *int32 `json:"started_at"`
+		FinishedAt *int32 `json:"finished_at"`
+	}
+
+	// TicketDeliveryReportResponse summarise the bulk ticket job,
+	// pending count the ticket not processed yet.
+	TicketDeliveryReportResponse struct {
+		JobID      int32                     `json:"job_id"`
+		Total      int                       `json:"total"`
+		Sent       int                       `json:"sent"`
+		Failed     int                       `json:"failed"`
+		Pending    int                       `json:"pending"`
+		Deliveries []*TicketDeliveryResponse `json:"deliveries"`
+	}
+
+	TicketDeliveryResponse struct {
+		ParticipantID    int32  `json:"participant_id"`
+		ParticipantName  string `json:"participant_name"`
+		ParticipantEmail string `json:"participant_email"`
+		Status           string `json:"status"`
+		Error            string `json:"error"`
+		MessageID        string `json:"message_id"`
+		Attempts         int32  `json:"attempts"`
+		AttemptedAt      int32  `json:"attempted_at"`
+		UpdatedAt        int32  `json:"updated_at"`
+	}
+
+	EventNotificationResponse struct {
+		Type         string `json:"type"`
+		GoogleFormID string `json:"google_form_id"`
+		Data         any    `json:"data"`
+		CreatedAt    int64  `json:"created_at"`
+	}
+
 	UserResponse struct {
 		ID         int32  `json:"id"`
 		UUID       string `json:"uuid"`
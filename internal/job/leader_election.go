@@ -0,0 +1,102 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"github.com/getsentry/sentry-go"
+	"github.com/redis/go-redis/v9"
+	"sync/atomic"
+	"time"
+)
+
+// acquireScript extend the lock when it is already held by the token,
+// otherwise try to take it over (only when no one else hold it).
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// LeaderElection elect a single instance (through a redis lock) to run
+// the scheduled jobs, the leader keep renewing the lock while it is alive
+// and another instance take it over once the lock expire.
+type LeaderElection struct {
+	redisClient *redis.Client
+	key         string
+	token       string
+	ttl         time.Duration
+	leader      atomic.Bool
+}
+
+func (election *LeaderElection) IsLeader() bool {
+	return election.leader.Load()
+}
+
+// Run campaign for the leadership until the context is done,
+// then release the lock so another instance can take it over right away.
+func (election *LeaderElection) Run(ctx context.Context) {
+	ticker := time.NewTicker(election.ttl / 3)
+	defer ticker.Stop()
+	for {
+		election.campaign(ctx)
+		select {
+		case <-ctx.Done():
+			election.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (election *LeaderElection) campaign(ctx context.Context) {
+	acquired, err := acquireScript.Run(ctx, election.redisClient,
+		[]string{election.key}, election.token,
+		election.ttl.Milliseconds()).Int()
+	if err != nil {
+		if ctx.Err() == nil {
+			ptn := "[%d] - LEADER_ELECTION_ERR (CAMPAIGN): %s"
+			msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+			sentry.CaptureMessage(msg)
+		}
+		// can not tell whether the lock is still ours, step down.
+		election.leader.Store(false)
+		return
+	}
+	election.leader.Store(acquired == 1)
+}
+
+func (election *LeaderElection) resign() {
+	if !election.leader.Swap(false) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), election.ttl)
+	defer cancel()
+	_ = releaseScript.Run(ctx, election.redisClient,
+		[]string{election.key}, election.token).Err()
+}
+
+func NewLeaderElection(
+	redisClient *redis.Client,
+	key string,
+	ttl time.Duration,
+) *LeaderElection {
+	// unique per process, even if the instances share the hostname
+	token := fmt.Sprintf("%s-%d", defaultConsumerName(), time.Now().UnixNano())
+	return &LeaderElection{
+		redisClient: redisClient,
+		key:         key,
+		token:       token,
+		ttl:         ttl,
+	}
+}
@@ -0,0 +1,85 @@
+package job_test
+
+import (
+	"context"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/job"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type leaderElectionTestSuite struct {
+	suite.Suite
+	miniRedis   *miniredis.Miniredis
+	redisClient *redis.Client
+}
+
+func (s *leaderElectionTestSuite) SetupTest() {
+	s.miniRedis = miniredis.RunT(s.T())
+	s.redisClient = redis.NewClient(&redis.Options{
+		Addr: s.miniRedis.Addr(),
+	})
+}
+
+func (s *leaderElectionTestSuite) TearDownTest() {
+	_ = s.redisClient.Close()
+	s.miniRedis.Close()
+}
+
+func (s *leaderElectionTestSuite) TestRun_ShouldElectSingleLeader() {
+	ttl := 300 * time.Millisecond
+	first := job.NewLeaderElection(s.redisClient, common.SchedulerLeaderLockKey, ttl)
+	second := job.NewLeaderElection(s.redisClient, common.SchedulerLeaderLockKey, ttl)
+	firstCtx, firstCancel := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		first.Run(firstCtx)
+		close(firstDone)
+	}()
+	s.Eventually(first.IsLeader, time.Second, 10*time.Millisecond)
+
+	secondCtx, secondCancel := context.WithCancel(context.Background())
+	defer secondCancel()
+	go second.Run(secondCtx)
+	// the leader keep renewing the lock, so it is never taken over
+	s.Never(second.IsLeader, 2*ttl, 10*time.Millisecond)
+	s.True(first.IsLeader())
+
+	// the leader resign on stop, the other instance take over
+	firstCancel()
+	<-firstDone
+	s.False(first.IsLeader())
+	s.Eventually(second.IsLeader, time.Second, 10*time.Millisecond)
+}
+
+func (s *leaderElectionTestSuite) TestRun_ShouldTakeOverExpiredLock() {
+	ttl := 300 * time.Millisecond
+	// an instance crashed while holding the lock
+	s.Nil(s.redisClient.Set(context.TODO(), common.SchedulerLeaderLockKey, "crashed", ttl).Err())
+	election := job.NewLeaderElection(s.redisClient, common.SchedulerLeaderLockKey, ttl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go election.Run(ctx)
+	s.Never(election.IsLeader, ttl/2, 10*time.Millisecond)
+	s.miniRedis.FastForward(ttl)
+	s.Eventually(election.IsLeader, time.Second, 10*time.Millisecond)
+}
+
+func (s *leaderElectionTestSuite) TestRun_ShouldStepDownOnError() {
+	election := job.NewLeaderElection(s.redisClient, common.SchedulerLeaderLockKey, 300*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go election.Run(ctx)
+	s.Eventually(election.IsLeader, time.Second, 10*time.Millisecond)
+	s.miniRedis.Close()
+	s.Eventually(func() bool {
+		return !election.IsLeader()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLeaderElection(t *testing.T) {
+	suite.Run(t, new(leaderElectionTestSuite))
+}
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+)
+
+func (queue *redisStreamQueue) ListDeadLetters(
+	ctx context.Context,
+	cursor string,
+	count int64,
+) (jobs []*entity.DeadLetterJob, err error) {
+	end := "+"
+	if cursor != "" {
+		// exclusive, the job of the cursor is already listed
+		end = "(" + cursor
+	}
+	messages, err := queue.redisClient.XRevRangeN(
+		ctx, common.QueueDeadLetterKey, end, "-", count).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		jobs = append(jobs, newDeadLetterJob(message))
+	}
+	return jobs, nil
+}
+
+func (queue *redisStreamQueue) GetDeadLetter(
+	ctx context.Context,
+	id string,
+) (*entity.DeadLetterJob, error) {
+	messages, err := queue.redisClient.XRange(
+		ctx, common.QueueDeadLetterKey, id, id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, common.ErrDeadLetterJobNotFound
+	}
+	return newDeadLetterJob(messages[0]), nil
+}
+
+// RetryDeadLetter publish the dead-lettered job back to its queue
+// as a fresh job (the attempts start over) and remove it from the store.
+func (queue *redisStreamQueue) RetryDeadLetter(
+	ctx context.Context,
+	id string,
+) (string, error) {
+	job, err := queue.GetDeadLetter(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	jobID, err := queue.Publish(ctx, job.Queue, job.Payload)
+	if err != nil {
+		return "", err
+	}
+	return jobID, queue.redisClient.XDel(
+		ctx, common.QueueDeadLetterKey, id).Err()
+}
+
+func (queue *redisStreamQueue) DiscardDeadLetter(
+	ctx context.Context,
+	id string,
+) error {
+	deleted, err := queue.redisClient.XDel(
+		ctx, common.QueueDeadLetterKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return common.ErrDeadLetterJobNotFound
+	}
+	return nil
+}
+
+func newDeadLetterJob(message redis.XMessage) *entity.DeadLetterJob {
+	job := &entity.DeadLetterJob{
+		ID:       message.ID,
+		Payload:  messagePayload(message),
+		Attempts: messageAttempt(message),
+	}
+	if value, ok := message.Values[common.QueueNameField].(string); ok {
+		job.Queue = value
+	}
+	if value, ok := message.Values[common.QueueErrorField].(string); ok {
+		job.Error = value
+	}
+	if value, ok := message.Values[common.QueueFailedAtField].(string); ok {
+		job.FailedAt, _ = strconv.ParseInt(value, 10, 64)
+	}
+	return job
+}
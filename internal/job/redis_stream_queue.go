@@ -0,0 +1,467 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/getsentry/sentry-go"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisStreamQueue is a durable work queue backed by redis streams,
+// every queue is a stream consumed by a shared consumer group so the
+// jobs survive restarts and can be processed by multiple instances.
+type redisStreamQueue struct {
+	redisClient   *redis.Client
+	group         string
+	consumer      string
+	readBlock     time.Duration
+	claimMinIdle  time.Duration
+	retryPolicies map[string]RetryPolicy
+}
+
+// RetryPolicy decide how many times a failed job is attempted
+// and how long to wait (doubled on every attempt) between them.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    common.QueueMaxAttempts,
+	InitialBackoff: common.QueueInitialBackoff,
+	MaxBackoff:     common.QueueMaxBackoff,
+}
+
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := policy.InitialBackoff
+	for i := 1; i < attempt && backoff < policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	return backoff
+}
+
+// delayedJob is a failed job waiting for its next attempt.
+type delayedJob struct {
+	ID      string `json:"id"`
+	Queue   string `json:"queue"`
+	Payload []byte `json:"payload"`
+	Attempt int    `json:"attempt"`
+}
+
+type QueueOption func(*redisStreamQueue)
+
+func WithConsumerGroup(group string) QueueOption {
+	return func(queue *redisStreamQueue) {
+		queue.group = group
+	}
+}
+
+func WithConsumerName(consumer string) QueueOption {
+	return func(queue *redisStreamQueue) {
+		queue.consumer = consumer
+	}
+}
+
+func WithReadBlock(duration time.Duration) QueueOption {
+	return func(queue *redisStreamQueue) {
+		queue.readBlock = duration
+	}
+}
+
+// WithClaimMinIdle set how long a message stay pending (not acknowledged)
+// before another consumer reclaim it, e.g. the worker crash mid-job.
+func WithClaimMinIdle(duration time.Duration) QueueOption {
+	return func(queue *redisStreamQueue) {
+		queue.claimMinIdle = duration
+	}
+}
+
+// WithRetryPolicy override the default retry policy for the given queue.
+func WithRetryPolicy(stream string, policy RetryPolicy) QueueOption {
+	return func(queue *redisStreamQueue) {
+		queue.retryPolicies[stream] = policy
+	}
+}
+
+func WithRetryPolicies(policies map[string]RetryPolicy) QueueOption {
+	return func(queue *redisStreamQueue) {
+		for stream, policy := range policies {
+			queue.retryPolicies[stream] = policy
+		}
+	}
+}
+
+func (queue *redisStreamQueue) Publish(
+	ctx context.Context,
+	stream string,
+	payload []byte,
+) (string, error) {
+	return queue.add(ctx, stream, payload, 1)
+}
+
+func (queue *redisStreamQueue) add(
+	ctx context.Context,
+	stream string,
+	payload []byte,
+	attempt int,
+) (string, error) {
+	return queue.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: common.QueueStreamMaxLen,
+		Approx: true,
+		Values: map[string]any{
+			common.QueuePayloadField: payload,
+			common.QueueAttemptField: attempt,
+		},
+	}).Result()
+}
+
+func (queue *redisStreamQueue) retryPolicy(stream string) RetryPolicy {
+	if policy, ok := queue.retryPolicies[stream]; ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// Consume block until the context is done (or the redis client is closed),
+// reclaiming stale pending messages before reading the new one.
+func (queue *redisStreamQueue) Consume(
+	ctx context.Context,
+	stream string,
+	handler domain.JobHandler,
+) error {
+	if err := queue.createGroup(ctx, stream); err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := queue.promoteDelayed(ctx)
+		if err == nil {
+			err = queue.reclaim(ctx, stream, handler)
+		}
+		if err == nil {
+			err = queue.read(ctx, stream, handler)
+		}
+		if err == nil || errors.Is(err, redis.Nil) {
+			continue
+		}
+		if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+			return err
+		}
+		// the stream (and the group) removed from redis, create it again.
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			if err := queue.createGroup(ctx, stream); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ptn := "[%d] - QUEUE_ERR (%s): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), stream, err.Error())
+		sentry.CaptureMessage(msg)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(common.QueueErrorWaitDuration):
+		}
+	}
+}
+
+func (queue *redisStreamQueue) createGroup(
+	ctx context.Context,
+	stream string,
+) error {
+	err := queue.redisClient.XGroupCreateMkStream(
+		ctx, stream, queue.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func (queue *redisStreamQueue) reclaim(
+	ctx context.Context,
+	stream string,
+	handler domain.JobHandler,
+) error {
+	start := "0-0"
+	for {
+		messages, next, err := queue.redisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    queue.group,
+			Consumer: queue.consumer,
+			MinIdle:  queue.claimMinIdle,
+			Start:    start,
+			Count:    common.QueueReadCount,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		queue.process(ctx, stream, messages, handler)
+		if next == "0-0" || next == "" || len(messages) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+func (queue *redisStreamQueue) read(
+	ctx context.Context,
+	stream string,
+	handler domain.JobHandler,
+) error {
+	streams, err := queue.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    queue.group,
+		Consumer: queue.consumer,
+		Streams:  []string{stream, ">"},
+		Count:    common.QueueReadCount,
+		Block:    queue.readBlock,
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, item := range streams {
+		queue.process(ctx, item.Stream, item.Messages, handler)
+	}
+	return nil
+}
+
+func (queue *redisStreamQueue) process(
+	ctx context.Context,
+	stream string,
+	messages []redis.XMessage,
+	handler domain.JobHandler,
+) {
+	// a message already read is processed (and acknowledged) to the end
+	// even when the consumer is stopped meanwhile.
+	jobCtx := detachedContext{ctx}
+	stopClaim := queue.keepClaimed(jobCtx, stream, messages)
+	defer stopClaim()
+	for _, message := range messages {
+		// the consumer is stopped, leave the rest pending for another consumer.
+		if ctx.Err() != nil {
+			return
+		}
+		job := &entity.QueueMessage{
+			ID:      message.ID,
+			Queue:   stream,
+			Payload: messagePayload(message),
+			Attempt: messageAttempt(message),
+			// the handler need it to know whether a failure is final
+			MaxAttempts: queue.retryPolicy(stream).MaxAttempts,
+		}
+		if err := handler(jobCtx, job); err != nil {
+			if err := queue.fail(jobCtx, job, err); err != nil {
+				// keep the message pending, it will be reclaimed later.
+				ptn := "[%d] - QUEUE_ERR (%s): %s"
+				msg := fmt.Sprintf(ptn, time.Now().Unix(), stream, err.Error())
+				sentry.CaptureMessage(msg)
+				continue
+			}
+		}
+		_ = queue.redisClient.XAck(jobCtx, stream, queue.group, message.ID).Err()
+	}
+}
+
+// keepClaimed keep the messages being processed claimed by this consumer
+// (their idle time is reset) until the returned function is called, so the
+// job running longer than claimMinIdle is not reclaimed and run again by
+// another consumer. The message already acknowledged is not claimed back.
+func (queue *redisStreamQueue) keepClaimed(
+	ctx context.Context,
+	stream string,
+	messages []redis.XMessage,
+) (stop func()) {
+	interval := queue.claimMinIdle / common.QueueClaimRenewPerIdle
+	if interval <= 0 || len(messages) == 0 {
+		return func() {}
+	}
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := queue.redisClient.XClaimJustID(ctx, &redis.XClaimArgs{
+					Stream:   stream,
+					Group:    queue.group,
+					Consumer: queue.consumer,
+					Messages: ids,
+				}).Err(); err != nil {
+					ptn := "[%d] - QUEUE_ERR (%s): %s"
+					msg := fmt.Sprintf(ptn, time.Now().Unix(), stream, err.Error())
+					sentry.CaptureMessage(msg)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// fail schedule the next attempt of the failed job with exponential backoff,
+// or move it to the dead-letter store once it exhausted the retry policy.
+func (queue *redisStreamQueue) fail(
+	ctx context.Context,
+	job *entity.QueueMessage,
+	jobErr error,
+) error {
+	policy := queue.retryPolicy(job.Queue)
+	if job.Attempt >= policy.MaxAttempts || errors.Is(jobErr, common.ErrUnrecoverableJob) {
+		return queue.redisClient.XAdd(ctx, &redis.XAddArgs{
+			Stream: common.QueueDeadLetterKey,
+			MaxLen: common.QueueStreamMaxLen,
+			Approx: true,
+			Values: map[string]any{
+				common.QueueNameField:     job.Queue,
+				common.QueuePayloadField:  job.Payload,
+				common.QueueAttemptField:  job.Attempt,
+				common.QueueErrorField:    jobErr.Error(),
+				common.QueueFailedAtField: time.Now().Unix(),
+			},
+		}).Err()
+	}
+
+	member, err := json.Marshal(&delayedJob{
+		ID:      job.ID,
+		Queue:   job.Queue,
+		Payload: job.Payload,
+		Attempt: job.Attempt + 1,
+	})
+	if err != nil {
+		return err
+	}
+	dueAt := time.Now().Add(policy.backoff(job.Attempt))
+	return queue.redisClient.ZAdd(ctx, common.QueueDelayedKey, redis.Z{
+		Score:  float64(dueAt.UnixMilli()),
+		Member: member,
+	}).Err()
+}
+
+// promoteScript move the delayed job back to its queue, the member is
+// removed and the job is published in one step, so only one consumer
+// publish it and the job is never lost in between.
+var promoteScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("XADD", KEYS[2], "MAXLEN", "~", ARGV[2], "*", ARGV[3], ARGV[4], ARGV[5], ARGV[6])
+return 1
+`)
+
+// promoteDelayed publish every delayed job that is due back to its queue.
+func (queue *redisStreamQueue) promoteDelayed(ctx context.Context) error {
+	members, err := queue.redisClient.ZRangeByScore(ctx, common.QueueDelayedKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   fmt.Sprintf("%d", time.Now().UnixMilli()),
+		Count: common.QueueReadCount,
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		var job delayedJob
+		if err := json.Unmarshal([]byte(member), &job); err != nil {
+			// it can never be published, drop it
+			if err := queue.redisClient.ZRem(ctx, common.QueueDelayedKey, member).Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := promoteScript.Run(ctx, queue.redisClient,
+			[]string{common.QueueDelayedKey, job.Queue},
+			member, common.QueueStreamMaxLen,
+			common.QueuePayloadField, job.Payload,
+			common.QueueAttemptField, job.Attempt,
+		).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachedContext keep the values of its parent but is never canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (detachedContext) Done() <-chan struct{}                   { return nil }
+func (detachedContext) Err() error                              { return nil }
+
+func messagePayload(message redis.XMessage) []byte {
+	switch value := message.Values[common.QueuePayloadField].(type) {
+	case string:
+		return []byte(value)
+	case []byte:
+		return value
+	default:
+		return nil
+	}
+}
+
+func messageAttempt(message redis.XMessage) int {
+	value, ok := message.Values[common.QueueAttemptField].(string)
+	if !ok {
+		return 1
+	}
+	attempt, err := strconv.Atoi(value)
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+func defaultConsumerName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "tix"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func NewRedisStreamQueue(
+	redisClient *redis.Client,
+	options ...QueueOption,
+) domain.IJobQueue {
+	queue := &redisStreamQueue{
+		redisClient:   redisClient,
+		group:         common.QueueConsumerGroup,
+		consumer:      defaultConsumerName(),
+		readBlock:     common.QueueReadBlockTime,
+		claimMinIdle:  common.QueueClaimMinIdleTime,
+		retryPolicies: map[string]RetryPolicy{},
+	}
+	for _, option := range options {
+		option(queue)
+	}
+	return queue
+}
@@ -0,0 +1,360 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/job"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type redisStreamQueueTestSuite struct {
+	suite.Suite
+	miniRedis   *miniredis.Miniredis
+	redisClient *redis.Client
+}
+
+func (s *redisStreamQueueTestSuite) SetupTest() {
+	s.miniRedis = miniredis.RunT(s.T())
+	s.redisClient = redis.NewClient(&redis.Options{
+		Addr: s.miniRedis.Addr(),
+	})
+}
+
+func (s *redisStreamQueueTestSuite) TearDownTest() {
+	_ = s.redisClient.Close()
+	s.miniRedis.Close()
+}
+
+func (s *redisStreamQueueTestSuite) consume(
+	ctx context.Context,
+	queue string,
+	options ...job.QueueOption,
+) (<-chan *entity.QueueMessage, <-chan error) {
+	options = append(options, job.WithReadBlock(50*time.Millisecond))
+	consumer := job.NewRedisStreamQueue(s.redisClient, options...)
+	messages := make(chan *entity.QueueMessage, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Consume(ctx, queue, func(ctx context.Context, message *entity.QueueMessage) error {
+			messages <- message
+			if string(message.Payload) == "fail" {
+				return errors.New("lorem")
+			}
+			return nil
+		})
+	}()
+	return messages, done
+}
+
+func (s *redisStreamQueueTestSuite) pending(queue string) int64 {
+	pending, err := s.redisClient.XPending(context.TODO(), queue, common.QueueConsumerGroup).Result()
+	s.Nil(err)
+	return pending.Count
+}
+
+func (s *redisStreamQueueTestSuite) TestPublishConsume_ShouldSuccess() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	// published before any consumer is connected
+	id, err := queue.Publish(ctx, "lorem_queue", []byte(`{"google_form_id":"asd"}`))
+	s.Nil(err)
+	s.NotEmpty(id)
+	messages, done := s.consume(ctx, "lorem_queue")
+	select {
+	case message := <-messages:
+		s.Equal(id, message.ID)
+		s.Equal("lorem_queue", message.Queue)
+		s.Equal(`{"google_form_id":"asd"}`, string(message.Payload))
+	case <-time.After(time.Second):
+		s.Fail("message not delivered")
+	}
+	s.Eventually(func() bool {
+		return s.pending("lorem_queue") == 0
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		s.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		s.Fail("consumer not stopped")
+	}
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldRetryFailedMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	_, err := queue.Publish(ctx, "lorem_queue", []byte("fail"))
+	s.Nil(err)
+	messages, _ := s.consume(ctx, "lorem_queue", job.WithRetryPolicy("lorem_queue", job.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}))
+	for attempt := 1; attempt <= 3; attempt++ {
+		select {
+		case message := <-messages:
+			s.Equal("fail", string(message.Payload))
+			s.Equal(attempt, message.Attempt)
+		case <-time.After(time.Second):
+			s.FailNow("message not delivered", "attempt %d", attempt)
+		}
+	}
+	s.Eventually(func() bool {
+		return s.redisClient.XLen(ctx, common.QueueDeadLetterKey).Val() == 1
+	}, time.Second, 10*time.Millisecond)
+	s.Equal(int64(0), s.pending("lorem_queue"))
+	s.Equal(int64(0), s.redisClient.ZCard(ctx, common.QueueDelayedKey).Val())
+	deadLetters, err := queue.ListDeadLetters(ctx, "", 10)
+	s.Nil(err)
+	s.Equal(1, len(deadLetters))
+	s.Equal("lorem_queue", deadLetters[0].Queue)
+	s.Equal("fail", string(deadLetters[0].Payload))
+	s.Equal(3, deadLetters[0].Attempts)
+	s.Equal("lorem", deadLetters[0].Error)
+	s.NotZero(deadLetters[0].FailedAt)
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldDelayFailedMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	_, err := queue.Publish(ctx, "lorem_queue", []byte("fail"))
+	s.Nil(err)
+	messages, _ := s.consume(ctx, "lorem_queue")
+	<-messages
+	s.Eventually(func() bool {
+		return s.redisClient.ZCard(ctx, common.QueueDelayedKey).Val() == 1
+	}, time.Second, 10*time.Millisecond)
+	delayed := s.redisClient.ZRangeWithScores(ctx, common.QueueDelayedKey, 0, -1).Val()
+	dueAt := time.UnixMilli(int64(delayed[0].Score))
+	s.WithinDuration(time.Now().Add(common.QueueInitialBackoff), dueAt, time.Second)
+	s.Equal(int64(0), s.pending("lorem_queue"))
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldPromoteDueDelayedMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dueAt := float64(time.Now().Add(-time.Second).UnixMilli())
+	s.Nil(s.redisClient.ZAdd(ctx, common.QueueDelayedKey,
+		redis.Z{Score: dueAt, Member: `{"id":"1-0","queue":"lorem_queue","payload":"bG9yZW0=","attempt":2}`},
+		// it can never be published, so it is dropped
+		redis.Z{Score: dueAt, Member: "lorem"},
+	).Err())
+	messages, _ := s.consume(ctx, "lorem_queue")
+	select {
+	case message := <-messages:
+		s.Equal("lorem", string(message.Payload))
+		s.Equal(2, message.Attempt)
+	case <-time.After(time.Second):
+		s.FailNow("delayed message not promoted")
+	}
+	s.Equal(int64(0), s.redisClient.ZCard(ctx, common.QueueDelayedKey).Val())
+	s.Equal(int64(1), s.redisClient.XLen(ctx, "lorem_queue").Val())
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldDeadLetterUnrecoverableMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient, job.WithReadBlock(50*time.Millisecond))
+	_, err := queue.Publish(ctx, "lorem_queue", []byte("lorem"))
+	s.Nil(err)
+	go func() {
+		_ = queue.Consume(ctx, "lorem_queue", func(ctx context.Context, message *entity.QueueMessage) error {
+			return fmt.Errorf("%w: lorem", common.ErrUnrecoverableJob)
+		})
+	}()
+	s.Eventually(func() bool {
+		return s.redisClient.XLen(ctx, common.QueueDeadLetterKey).Val() == 1
+	}, time.Second, 10*time.Millisecond)
+	s.Equal(int64(0), s.redisClient.ZCard(ctx, common.QueueDelayedKey).Val())
+}
+
+func (s *redisStreamQueueTestSuite) TestDeadLetter_RetryAndDiscard() {
+	ctx := context.Background()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	ids := make([]string, 2)
+	for i := range ids {
+		id, err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
+			Stream: common.QueueDeadLetterKey,
+			Values: map[string]any{
+				common.QueueNameField:     "lorem_queue",
+				common.QueuePayloadField:  "lorem",
+				common.QueueAttemptField:  5,
+				common.QueueErrorField:    "lorem",
+				common.QueueFailedAtField: time.Now().Unix(),
+			},
+		}).Result()
+		s.Nil(err)
+		ids[i] = id
+	}
+
+	// the newest first, a page at a time
+	deadLetters, err := queue.ListDeadLetters(ctx, "", 1)
+	s.Nil(err)
+	s.Equal(1, len(deadLetters))
+	s.Equal(ids[1], deadLetters[0].ID)
+	deadLetters, err = queue.ListDeadLetters(ctx, deadLetters[0].ID, 1)
+	s.Nil(err)
+	s.Equal(1, len(deadLetters))
+	s.Equal(ids[0], deadLetters[0].ID)
+	deadLetters, err = queue.ListDeadLetters(ctx, deadLetters[0].ID, 1)
+	s.Nil(err)
+	s.Empty(deadLetters)
+
+	deadLetter, err := queue.GetDeadLetter(ctx, ids[0])
+	s.Nil(err)
+	s.Equal(ids[0], deadLetter.ID)
+	s.Equal(5, deadLetter.Attempts)
+
+	jobID, err := queue.RetryDeadLetter(ctx, ids[0])
+	s.Nil(err)
+	messages := s.redisClient.XRange(ctx, "lorem_queue", jobID, jobID).Val()
+	s.Equal(1, len(messages))
+	s.Equal("lorem", messages[0].Values[common.QueuePayloadField])
+	s.Equal("1", messages[0].Values[common.QueueAttemptField])
+	_, err = queue.GetDeadLetter(ctx, ids[0])
+	s.ErrorIs(err, common.ErrDeadLetterJobNotFound)
+
+	s.Nil(queue.DiscardDeadLetter(ctx, ids[1]))
+	s.ErrorIs(queue.DiscardDeadLetter(ctx, ids[1]), common.ErrDeadLetterJobNotFound)
+	_, err = queue.RetryDeadLetter(ctx, ids[1])
+	s.ErrorIs(err, common.ErrDeadLetterJobNotFound)
+
+	deadLetters, err = queue.ListDeadLetters(ctx, "", 10)
+	s.Nil(err)
+	s.Empty(deadLetters)
+}
+
+func (s *redisStreamQueueTestSuite) TestDeadLetter_ShouldError() {
+	_ = s.redisClient.Close()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	_, err := queue.ListDeadLetters(context.TODO(), "", 10)
+	s.NotNil(err)
+	_, err = queue.GetDeadLetter(context.TODO(), "1-0")
+	s.NotNil(err)
+	_, err = queue.RetryDeadLetter(context.TODO(), "1-0")
+	s.NotNil(err)
+	s.NotNil(queue.DiscardDeadLetter(context.TODO(), "1-0"))
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldReclaimPendingMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	id, err := queue.Publish(ctx, "lorem_queue", []byte("lorem"))
+	s.Nil(err)
+	// a worker read the message then crash before acknowledge it
+	s.Nil(s.redisClient.XGroupCreateMkStream(ctx, "lorem_queue", common.QueueConsumerGroup, "0").Err())
+	_, err = s.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    common.QueueConsumerGroup,
+		Consumer: "crashed-worker",
+		Streams:  []string{"lorem_queue", ">"},
+	}).Result()
+	s.Nil(err)
+	s.Equal(int64(1), s.pending("lorem_queue"))
+	messages, _ := s.consume(ctx, "lorem_queue",
+		job.WithConsumerName("healthy-worker"),
+		job.WithClaimMinIdle(time.Millisecond))
+	select {
+	case message := <-messages:
+		s.Equal(id, message.ID)
+	case <-time.After(time.Second):
+		s.Fail("pending message not reclaimed")
+	}
+	s.Eventually(func() bool {
+		return s.pending("lorem_queue") == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldNotReclaimRunningMessage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient,
+		job.WithConsumerName("busy-worker"),
+		job.WithClaimMinIdle(100*time.Millisecond),
+		job.WithReadBlock(50*time.Millisecond))
+	_, err := queue.Publish(ctx, "lorem_queue", []byte("lorem"))
+	s.Nil(err)
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_ = queue.Consume(ctx, "lorem_queue", func(_ context.Context, message *entity.QueueMessage) error {
+			close(started)
+			// the job outlive the min-idle time of the other worker
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	messages, _ := s.consume(ctx, "lorem_queue",
+		job.WithConsumerName("idle-worker"),
+		job.WithClaimMinIdle(100*time.Millisecond))
+	select {
+	case message := <-messages:
+		s.Failf("running message reclaimed", "message %s delivered twice", message.ID)
+	case <-time.After(500 * time.Millisecond):
+	}
+	close(release)
+	s.Eventually(func() bool {
+		return s.pending("lorem_queue") == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldFinishRunningMessageOnCancel() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := job.NewRedisStreamQueue(s.redisClient, job.WithReadBlock(50*time.Millisecond))
+	_, err := queue.Publish(ctx, "lorem_queue", []byte("lorem"))
+	s.Nil(err)
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Consume(ctx, "lorem_queue", func(jobCtx context.Context, message *entity.QueueMessage) error {
+			close(started)
+			<-ctx.Done()
+			// the running message is not interrupted by the shutdown
+			return jobCtx.Err()
+		})
+	}()
+	<-started
+	cancel()
+	select {
+	case err := <-done:
+		s.ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		s.Fail("consumer not stopped")
+	}
+	s.Equal(int64(0), s.pending("lorem_queue"))
+	s.Equal(int64(0), s.redisClient.ZCard(context.TODO(), common.QueueDelayedKey).Val())
+}
+
+func (s *redisStreamQueueTestSuite) TestConsume_ShouldError() {
+	_ = s.redisClient.Close()
+	_, done := s.consume(context.Background(), "lorem_queue")
+	select {
+	case err := <-done:
+		s.NotNil(err)
+	case <-time.After(time.Second):
+		s.Fail("consumer not stopped")
+	}
+}
+
+func (s *redisStreamQueueTestSuite) TestPublish_ShouldError() {
+	_ = s.redisClient.Close()
+	queue := job.NewRedisStreamQueue(s.redisClient)
+	id, err := queue.Publish(context.TODO(), "lorem_queue", []byte("lorem"))
+	s.NotNil(err)
+	s.Empty(id)
+}
+
+func TestRedisStreamQueue(t *testing.T) {
+	suite.Run(t, new(redisStreamQueueTestSuite))
+}
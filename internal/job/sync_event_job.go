@@ -7,30 +7,111 @@ import (
 	"fmt"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
 	"github.com/getsentry/sentry-go"
 	"github.com/go-co-op/gocron"
 	"github.com/redis/go-redis/v9"
+	"sync"
 	"time"
 )
 
+// EventJobRetryPolicies is the retry policy of every event job queue,
+// sync and export are retried less since the admin can request them again.
+var EventJobRetryPolicies = map[string]RetryPolicy{
+	common.ReqSyncEventQueueKey: {
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     10 * time.Minute,
+	},
+	common.ReqGenEventTixQueueKey:     DefaultRetryPolicy,
+	common.ReqGenEventBulkTixQueueKey: DefaultRetryPolicy,
+	common.ReqExpEventDataQueueKey: {
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     15 * time.Minute,
+	},
+}
+
+// Worker run the background jobs until it is shut down.
+type Worker interface {
+	// Shutdown stop taking new jobs and wait (until the context is done)
+	// for the running jobs to finish.
+	Shutdown(ctx context.Context) error
+}
+
 type syncEventJob struct {
 	service     domain.ITixService
 	redisClient *redis.Client
+	queue       domain.IJobQueue
+	leader      *LeaderElection
+	scheduler   *gocron.Scheduler
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
 }
 
 func NewEventJob(
 	service domain.ITixService,
 	redisClient *redis.Client,
-) {
-	event := &syncEventJob{service, redisClient}
+	queue domain.IJobQueue,
+	leader *LeaderElection,
+) Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	event := &syncEventJob{
+		service:     service,
+		redisClient: redisClient,
+		queue:       queue,
+		leader:      leader,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	event.regisLeaderElection()
 	event.regisCronJob()
-	event.regisQueueSubscriber()
+	event.regisQueueConsumer()
+	return event
+}
+
+func (e *syncEventJob) Shutdown(ctx context.Context) error {
+	e.cancel()
+	done := make(chan struct{})
+	go func() {
+		e.scheduler.Stop()
+		e.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *syncEventJob) regisLeaderElection() {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.leader.Run(e.ctx)
+	}()
+}
+
+// leaderOnly skip the scheduled task unless this instance is the elected leader,
+// so the scheduled jobs run once no matter how many worker instances are running.
+func (e *syncEventJob) leaderOnly(task func()) func() {
+	return func() {
+		if !e.leader.IsLeader() {
+			return
+		}
+		task()
+	}
 }
 
 func (e *syncEventJob) regisCronJob() {
 	scheduler := gocron.NewScheduler(time.UTC)
-	_, _ = scheduler.Every(common.EventRemovalScheduleTime).Minute().Do(func() {
+	e.scheduler = scheduler
+	_, _ = scheduler.Every(common.EventRemovalScheduleTime).Minute().Do(e.leaderOnly(func() {
 		// Retrieve data from Redis cache
 		cacheData, err := e.redisClient.Get(context.Background(), common.AutoSyncEventKey).Result()
 		if err != nil {
@@ -66,8 +147,8 @@ func (e *syncEventJob) regisCronJob() {
 		).Err(); err != nil {
 			return
 		}
-	})
-	_, _ = scheduler.Every(common.EventSyncScheduleTime).Minute().Do(func() {
+	}))
+	_, _ = scheduler.Every(common.EventSyncScheduleTime).Minute().Do(e.leaderOnly(func() {
 		// Retrieve data from Redis cache
 		cacheData, err := e.redisClient.Get(context.Background(), common.AutoSyncEventKey).Result()
 		if err != nil {
@@ -85,125 +166,194 @@ func (e *syncEventJob) regisCronJob() {
 			return
 		}
 		// remove expired event
+		e.wg.Add(1)
 		go func() {
+			defer e.wg.Done()
 			for _, event := range cacheItems {
+				if e.ctx.Err() != nil {
+					return
+				}
 				_ = e.service.SyncRespondData(context.Background(), event.FormID)
 			}
 		}()
-	})
+	}))
 	scheduler.StartAsync()
 }
 
-func (e *syncEventJob) regisQueueSubscriber() {
-	e.subscribeSyncEvent()
-	e.subscribeGenerateTicket()
-	e.subscribeExportData()
+// trackedJobHandler process a queue message and return the job result artefact.
+type trackedJobHandler func(ctx context.Context, message *entity.QueueMessage) (string, error)
+
+func (e *syncEventJob) regisQueueConsumer() {
+	e.consume(common.ReqSyncEventQueueKey, e.track(e.syncEvent))
+	e.consume(common.ReqGenEventTixQueueKey, e.track(e.generateTicket))
+	e.consume(common.ReqExpEventDataQueueKey, e.track(e.exportData))
+	e.consume(common.ReqGenEventBulkTixQueueKey, e.track(e.generateTickets))
 }
 
-func (e *syncEventJob) subscribeSyncEvent() {
-	subscriber := e.redisClient.Subscribe(context.Background(), common.ReqSyncEventQueueKey)
-	go func() {
-		for {
-			msg, err := subscriber.ReceiveMessage(context.Background())
-			if err != nil {
-				ptn := "[%d] - SYNC_EVENT_ERR (QUEUE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+// track report the lifecycle (running, succeeded, failed or queued again
+// for the next attempt) of the job published with a job id.
+func (e *syncEventJob) track(handler trackedJobHandler) domain.JobHandler {
+	return func(ctx context.Context, message *entity.QueueMessage) error {
+		var job struct {
+			JobID int32 `json:"job_id"`
+		}
+		_ = json.Unmarshal(message.Payload, &job)
+		if job.JobID == 0 {
+			_, err := handler(ctx, message)
+			return err
+		}
 
-			var eventData struct {
-				GoogleFormID string `json:"google_form_id"`
-			}
+		e.updateJobStatus(ctx, job.JobID, common.JobStatusRunning, message.Attempt, "", "")
+		result, err := handler(ctx, message)
+		if err == nil {
+			e.updateJobStatus(ctx, job.JobID, common.JobStatusSucceeded, message.Attempt, result, "")
+			return nil
+		}
 
-			if err := json.Unmarshal([]byte(msg.Payload), &eventData); err != nil {
-				ptn := "[%d] - SYNC_EVENT_ERR (DECODE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+		status := common.JobStatusFailed
+		if message.Attempt < message.MaxAttempts && !errors.Is(err, common.ErrUnrecoverableJob) {
+			status = common.JobStatusQueued
+		}
+		e.updateJobStatus(ctx, job.JobID, status, message.Attempt, "", err.Error())
+		return err
+	}
+}
 
-			if err := e.service.SyncRespondData(context.Background(), eventData.GoogleFormID); err != nil {
-				ptn := "[%d] - SYNC_EVENT_ERR (ACTION): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+func (e *syncEventJob) updateJobStatus(
+	ctx context.Context,
+	jobID int32,
+	status common.JobStatus,
+	attempt int,
+	result, errorText string,
+) {
+	if err := e.service.UpdateJobStatus(
+		ctx, jobID, status, attempt, result, errorText,
+	); err != nil {
+		ptn := "[%d] - JOB_STATUS_ERR (%d): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), jobID, err.Error())
+		sentry.CaptureMessage(msg)
+	}
+}
+
+func (e *syncEventJob) consume(queue string, handler domain.JobHandler) {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		if err := e.queue.Consume(e.ctx, queue, handler); err != nil && e.ctx.Err() == nil {
+			ptn := "[%d] - QUEUE_ERR (CONSUME): %s"
+			msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+			sentry.CaptureMessage(msg)
 		}
 	}()
 }
 
-func (e *syncEventJob) subscribeGenerateTicket() {
-	subscriber := e.redisClient.Subscribe(context.Background(), common.ReqGenEventTixQueueKey)
-	go func() {
-		for {
-			msg, err := subscriber.ReceiveMessage(context.Background())
-			if err != nil {
-				ptn := "[%d] - GEN_TIX_ERR (QUEUE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+func (e *syncEventJob) syncEvent(ctx context.Context, message *entity.QueueMessage) (string, error) {
+	var eventData struct {
+		GoogleFormID string `json:"google_form_id"`
+	}
 
-			var eventData struct {
-				GoogleFormID  string `json:"google_form_id"`
-				ParticipantID int32  `json:"participant_id"`
-			}
+	if err := json.Unmarshal(message.Payload, &eventData); err != nil {
+		ptn := "[%d] - SYNC_EVENT_ERR (DECODE): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
+	}
 
-			if err := json.Unmarshal([]byte(msg.Payload), &eventData); err != nil {
-				ptn := "[%d] - GEN_TIX_ERR (DECODE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+	if err := e.service.SyncRespondData(ctx, eventData.GoogleFormID); err != nil {
+		ptn := "[%d] - SYNC_EVENT_ERR (ACTION): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", err
+	}
 
-			if err := e.service.GenerateTicket(
-				context.Background(), eventData.GoogleFormID,
-				eventData.ParticipantID,
-			); err != nil {
-				ptn := "[%d] - EXPORT_DATA_ERR (ACTION): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+	return fmt.Sprintf("event %s synced", eventData.GoogleFormID), nil
+}
+
+func (e *syncEventJob) generateTicket(ctx context.Context, message *entity.QueueMessage) (string, error) {
+	var eventData struct {
+		GoogleFormID  string `json:"google_form_id"`
+		ParticipantID int32  `json:"participant_id"`
+	}
+
+	if err := json.Unmarshal(message.Payload, &eventData); err != nil {
+		ptn := "[%d] - GEN_TIX_ERR (DECODE): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
+	}
+
+	if err := e.service.GenerateTicket(
+		ctx, eventData.GoogleFormID,
+		eventData.ParticipantID,
+	); err != nil {
+		ptn := "[%d] - GEN_TIX_ERR (ACTION): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		if errors.Is(err, common.ErrParticipantNotApproved) {
+			return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
 		}
-	}()
+		return "", err
+	}
+
+	return fmt.Sprintf("ticket sent to participant %d", eventData.ParticipantID), nil
 }
 
-func (e *syncEventJob) subscribeExportData() {
-	subscriber := e.redisClient.Subscribe(context.Background(), common.ReqExpEventDataQueueKey)
-	go func() {
-		for {
-			msg, err := subscriber.ReceiveMessage(context.Background())
-			if err != nil {
-				ptn := "[%d] - EXPORT_DATA_ERR (QUEUE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+func (e *syncEventJob) generateTickets(ctx context.Context, message *entity.QueueMessage) (string, error) {
+	var eventData struct {
+		GoogleFormID string `json:"google_form_id"`
+		JobID        int32  `json:"job_id"`
+		Filter       string `json:"filter"`
+	}
 
-			var eventData struct {
-				GoogleFormID string `json:"google_form_id"`
-				ExportType   string `json:"export_type"`
-				Email        string `json:"email"`
-			}
+	if err := json.Unmarshal(message.Payload, &eventData); err != nil {
+		ptn := "[%d] - GEN_BULK_TIX_ERR (DECODE): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
+	}
 
-			if err := json.Unmarshal([]byte(msg.Payload), &eventData); err != nil {
-				ptn := "[%d] - EXPORT_DATA_ERR (DECODE): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+	report, err := e.service.GenerateEventTickets(
+		ctx, eventData.GoogleFormID,
+		eventData.JobID, eventData.Filter,
+	)
+	if err != nil {
+		ptn := "[%d] - GEN_BULK_TIX_ERR (ACTION): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", err
+	}
 
-			if err := e.service.ExportEvent(
-				context.Background(), eventData.GoogleFormID,
-				eventData.ExportType, eventData.Email,
-			); err != nil {
-				ptn := "[%d] - EXPORT_DATA_ERR (ACTION): %s"
-				msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
-				sentry.CaptureMessage(msg)
-				continue
-			}
+	return fmt.Sprintf("tickets sent to %d of %d participants", report.Sent, report.Total), nil
+}
+
+func (e *syncEventJob) exportData(ctx context.Context, message *entity.QueueMessage) (string, error) {
+	var eventData struct {
+		GoogleFormID string `json:"google_form_id"`
+		ExportType   string `json:"export_type"`
+		Email        string `json:"email"`
+		request.EventRequestExport
+	}
+
+	if err := json.Unmarshal(message.Payload, &eventData); err != nil {
+		ptn := "[%d] - EXPORT_DATA_ERR (DECODE): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
+	}
+
+	if err := e.service.ExportEvent(
+		ctx, eventData.GoogleFormID,
+		eventData.ExportType, eventData.Email, &eventData.EventRequestExport,
+	); err != nil {
+		ptn := "[%d] - EXPORT_DATA_ERR (ACTION): %s"
+		msg := fmt.Sprintf(ptn, time.Now().Unix(), err.Error())
+		sentry.CaptureMessage(msg)
+		if errors.Is(err, common.ErrUnsupportedExportType) ||
+			errors.Is(err, common.ErrUnsupportedExportColumn) {
+			return "", fmt.Errorf("%w: %s", common.ErrUnrecoverableJob, err.Error())
 		}
-	}()
+		return "", err
+	}
+
+	return fmt.Sprintf("%s export sent to %s", eventData.ExportType, eventData.Email), nil
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
 	"github.com/aasumitro/tix/internal/job"
 	"github.com/aasumitro/tix/mocks"
@@ -20,6 +21,12 @@ type tixJobTestSuite struct {
 	suite.Suite
 }
 
+func (s *tixJobTestSuite) leader(redisClient *redis.Client) *job.LeaderElection {
+	return job.NewLeaderElection(redisClient,
+		common.SchedulerLeaderLockKey,
+		common.SchedulerLeaderLockTTL)
+}
+
 func (s *tixJobTestSuite) TestEventCronJob_success() {
 	miniRedis := miniredis.RunT(s.T())
 	redisClient := redis.NewClient(&redis.Options{
@@ -46,7 +53,7 @@ func (s *tixJobTestSuite) TestEventCronJob_success() {
 	}
 	tixService.On("SyncRespondData", mock.Anything, mock.Anything).Return(nil).Once()
 	tixService.On("SyncRespondData", mock.Anything, mock.Anything).Return(nil).Once()
-	job.NewEventJob(tixService, redisClient)
+	job.NewEventJob(tixService, redisClient, job.NewRedisStreamQueue(redisClient), s.leader(redisClient))
 	miniRedis.Close()
 	if err := redisClient.Close(); err != nil {
 		s.Error(err)
@@ -71,7 +78,7 @@ func (s *tixJobTestSuite) TestEventCronJob_ErrorDecodeData() {
 	if !miniRedis.Exists(common.AutoSyncEventKey) {
 		s.Error(errors.New("key not exists"))
 	}
-	job.NewEventJob(tixService, redisClient)
+	job.NewEventJob(tixService, redisClient, job.NewRedisStreamQueue(redisClient), s.leader(redisClient))
 	miniRedis.Close()
 	if err := redisClient.Close(); err != nil {
 		s.Error(err)
@@ -85,7 +92,7 @@ func (s *tixJobTestSuite) TestEventCronJob_ErrorNoData() {
 	})
 	tixService := new(mocks.ITixService)
 	redisClient.Del(context.Background(), common.AutoSyncEventKey)
-	job.NewEventJob(tixService, redisClient)
+	job.NewEventJob(tixService, redisClient, job.NewRedisStreamQueue(redisClient), s.leader(redisClient))
 	miniRedis.Close()
 	if err := redisClient.Close(); err != nil {
 		s.Error(err)
@@ -102,7 +109,7 @@ func (s *tixJobTestSuite) TestEventCronJob_ErrorNilData() {
 		s.Error(err)
 	}
 	redisClient.Set(context.Background(), common.AutoSyncEventKey, nil, 1)
-	job.NewEventJob(tixService, redisClient)
+	job.NewEventJob(tixService, redisClient, job.NewRedisStreamQueue(redisClient), s.leader(redisClient))
 	miniRedis.Close()
 }
 
@@ -112,7 +119,8 @@ func (s *tixJobTestSuite) TestEventStreamer_SYNC_Success() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]string{
 		"google_form_id": "asd",
 	})
@@ -120,7 +128,7 @@ func (s *tixJobTestSuite) TestEventStreamer_SYNC_Success() {
 		s.Error(err)
 	}
 	tixService.On("SyncRespondData", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData).Err(); err != nil {
+	if _, err := queue.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData); err != nil {
 		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -136,7 +144,8 @@ func (s *tixJobTestSuite) TestEventStreamer_SYNC_ErrorService() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]string{
 		"google_form_id": "asd",
 	})
@@ -144,8 +153,8 @@ func (s *tixJobTestSuite) TestEventStreamer_SYNC_ErrorService() {
 		s.Error(err)
 	}
 	tixService.On("SyncRespondData", mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData); err == nil {
-		s.Error(err.Err())
+	if _, err := queue.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData); err != nil {
+		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
 	miniRedis.Close()
@@ -160,12 +169,13 @@ func (s *tixJobTestSuite) TestEventStreamer_SYNC_ErrorMarshal() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(1)
 	if err != nil {
 		return
 	}
-	redisClient.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData)
+	_, _ = queue.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData)
 	time.Sleep(100 * time.Millisecond)
 	miniRedis.Close()
 	if err := redisClient.Close(); err != nil {
@@ -179,7 +189,8 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_Success() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]any{
 		"google_form_id": "asd",
 		"participant_id": 1,
@@ -188,7 +199,7 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_Success() {
 		s.Error(err)
 	}
 	tixService.On("GenerateTicket", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData).Err(); err != nil {
+	if _, err := queue.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData); err != nil {
 		s.Error(err)
 	}
 
@@ -205,7 +216,8 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_ErrorService() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]any{
 		"google_form_id": "asd",
 		"participant_id": 1,
@@ -214,8 +226,8 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_ErrorService() {
 		s.Error(err)
 	}
 	tixService.On("GenerateTicket", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData); err == nil {
-		s.Error(err.Err())
+	if _, err := queue.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData); err != nil {
+		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
 	miniRedis.Close()
@@ -230,12 +242,13 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_ErrorMarshal() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(1)
 	if err != nil {
 		return
 	}
-	redisClient.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData)
+	_, _ = queue.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData)
 	time.Sleep(100 * time.Millisecond)
 	miniRedis.Close()
 	if err := redisClient.Close(); err != nil {
@@ -243,13 +256,90 @@ func (s *tixJobTestSuite) TestEventStreamer_GEN_ErrorMarshal() {
 	}
 }
 
+func (s *tixJobTestSuite) TestEventStreamer_BULK_Success() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"filter":         "unsent",
+		"job_id":         1,
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").Return(nil).Once()
+	tixService.On("GenerateEventTickets", mock.Anything, "asd", int32(1), "unsent").
+		Return(&response.TicketDeliveryReportResponse{JobID: 1, Total: 2, Sent: 2}, nil).Once()
+	done := make(chan struct{})
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusSucceeded, 1, "tickets sent to 2 of 2 participants", "").
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqGenEventBulkTixQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
+func (s *tixJobTestSuite) TestEventStreamer_BULK_ErrorService() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"filter":         "approved",
+		"job_id":         1,
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").Return(nil).Once()
+	tixService.On("GenerateEventTickets", mock.Anything, "asd", int32(1), "approved").
+		Return(&response.TicketDeliveryReportResponse{JobID: 1, Total: 2, Sent: 1, Failed: 1},
+			common.ErrTicketDeliveryFailed).Once()
+	done := make(chan struct{})
+	// the failed tickets are sent again on the next attempt
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusQueued, 1, "", common.ErrTicketDeliveryFailed.Error()).
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqGenEventBulkTixQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
 func (s *tixJobTestSuite) TestEventStreamer_EXP_Success() {
 	miniRedis := miniredis.RunT(s.T())
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]string{
 		"google_form_id": "asd",
 		"export_type":    "pdf",
@@ -258,8 +348,8 @@ func (s *tixJobTestSuite) TestEventStreamer_EXP_Success() {
 	if err != nil {
 		s.Error(err)
 	}
-	tixService.On("ExportEvent", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData).Err(); err != nil {
+	tixService.On("ExportEvent", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), &request.EventRequestExport{}).Return(nil).Once()
+	if _, err := queue.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData); err != nil {
 		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -275,7 +365,8 @@ func (s *tixJobTestSuite) TestEventStreamer_EXP_ErrorService() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(map[string]string{
 		"google_form_id": "asd",
 		"export_type":    "pdf",
@@ -284,8 +375,8 @@ func (s *tixJobTestSuite) TestEventStreamer_EXP_ErrorService() {
 	if err != nil {
 		s.Error(err)
 	}
-	tixService.On("ExportEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
-	if err := redisClient.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData).Err(); err != nil {
+	tixService.On("ExportEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
+	if _, err := queue.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData); err != nil {
 		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -301,12 +392,13 @@ func (s *tixJobTestSuite) TestEventStreamer_EXP_ErrorMarshal() {
 		Addr: miniRedis.Addr(),
 	})
 	tixService := new(mocks.ITixService)
-	job.NewEventJob(tixService, redisClient)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
 	jsonData, err := json.Marshal(1)
 	if err != nil {
 		return
 	}
-	if err := redisClient.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData).Err(); err != nil {
+	if _, err := queue.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData); err != nil {
 		s.Error(err)
 	}
 	time.Sleep(100 * time.Millisecond)
@@ -316,6 +408,211 @@ func (s *tixJobTestSuite) TestEventStreamer_EXP_ErrorMarshal() {
 	}
 }
 
+func (s *tixJobTestSuite) TestEventStreamer_TrackJob_Success() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"export_type":    "pdf",
+		"email":          "asd@hello.id",
+		"job_id":         1,
+		"bom":            false,
+		"status":         "approved",
+		"checked_in":     true,
+		"search":         "tix",
+		"columns":        "name,email",
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").Return(nil).Once()
+	checkedIn := true
+	tixService.On("ExportEvent", mock.Anything, "asd", "pdf", "asd@hello.id", &request.EventRequestExport{
+		Status: "approved", CheckedIn: &checkedIn, Search: "tix", Columns: "name,email",
+	}).Return(nil).Once()
+	done := make(chan struct{})
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusSucceeded, 1, "pdf export sent to asd@hello.id", "").
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
+func (s *tixJobTestSuite) TestEventStreamer_TrackJob_Error() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"job_id":         1,
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	// the first attempt failed, the job is queued again for the next attempt
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").
+		Return(errors.New("lorem")).Once()
+	tixService.On("SyncRespondData", mock.Anything, "asd").Return(errors.New("lorem")).Once()
+	done := make(chan struct{})
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusQueued, 1, "", "lorem").
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
+func (s *tixJobTestSuite) TestEventStreamer_TrackJob_NotApproved() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"participant_id": 1,
+		"job_id":         1,
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	// the ticket is never available for the participant, no retry
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").Return(nil).Once()
+	tixService.On("GenerateTicket", mock.Anything, "asd", int32(1)).Return(common.ErrParticipantNotApproved).Once()
+	done := make(chan struct{})
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusFailed, 1, "", mock.Anything).
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqGenEventTixQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
+func (s *tixJobTestSuite) TestEventStreamer_TrackJob_UnsupportedExportType() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]any{
+		"google_form_id": "asd",
+		"export_type":    "docx",
+		"email":          "hello@tix.id",
+		"job_id":         1,
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	// the export type never get an exporter, no retry
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusRunning, 1, "", "").Return(nil).Once()
+	tixService.On("ExportEvent", mock.Anything, "asd", "docx", "hello@tix.id", &request.EventRequestExport{}).
+		Return(common.ErrUnsupportedExportType).Once()
+	done := make(chan struct{})
+	tixService.On("UpdateJobStatus", mock.Anything, int32(1), common.JobStatusFailed, 1, "", mock.Anything).
+		Return(nil).Once().Run(func(args mock.Arguments) { close(done) })
+	if _, err := queue.Publish(context.TODO(), common.ReqExpEventDataQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("job status not tracked")
+	}
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
+func (s *tixJobTestSuite) TestEventStreamer_Shutdown_DrainRunningJob() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	tixService := new(mocks.ITixService)
+	queue := job.NewRedisStreamQueue(redisClient)
+	worker := job.NewEventJob(tixService, redisClient, queue, s.leader(redisClient))
+	jsonData, err := json.Marshal(map[string]string{
+		"google_form_id": "asd",
+	})
+	if err != nil {
+		s.Error(err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	tixService.On("SyncRespondData", mock.Anything, "asd").Return(nil).Once().
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-release
+		})
+	if _, err := queue.Publish(context.TODO(), common.ReqSyncEventQueueKey, jsonData); err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		s.FailNow("job not started")
+	}
+	// the running job outlive the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.ErrorIs(worker.Shutdown(ctx), context.DeadlineExceeded)
+	// the running job finish, then it is acknowledged
+	close(release)
+	s.Nil(worker.Shutdown(context.Background()))
+	pending, err := redisClient.XPending(context.TODO(),
+		common.ReqSyncEventQueueKey, common.QueueConsumerGroup).Result()
+	s.Nil(err)
+	s.Equal(int64(0), pending.Count)
+	tixService.AssertExpectations(s.T())
+	miniRedis.Close()
+	if err := redisClient.Close(); err != nil {
+		s.Error(err)
+	}
+}
+
 func TestTixJob(t *testing.T) {
 	suite.Run(t, new(tixJobTestSuite))
 }
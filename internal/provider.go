@@ -62,8 +62,7 @@ func (boot *boostrap) newPublicAPIProvider() {
 				common.SwaggerDefaultModelsExpandDepth)))
 }
 
-func (boot *boostrap) newTixAPIProvider() {
-	routerGroupV1 := boot.engine.Group("api/v1")
+func (boot *boostrap) newTixServiceProvider() {
 	authRepository := restRepository.NewAuthRESTRepository(
 		config.Instance.SupabaseProjectURL,
 		config.Instance.SupabaseAPIKey,
@@ -72,14 +71,36 @@ func (boot *boostrap) newTixAPIProvider() {
 	gsRepository := restRepository.NewGoogleServiceRepository(&config.FormsServiceWrapper{
 		Service: boot.googleForm.Forms,
 	})
-	tixService := service.NewTixService(
+	boot.jobQueue = job.NewRedisStreamQueue(boot.cache,
+		job.WithRetryPolicies(job.EventJobRetryPolicies))
+	boot.tixService = service.NewTixService(
 		service.WithGoogleServiceRepository(gsRepository),
 		service.WithRedisCache(boot.cache),
 		service.WithAuthRESTRepository(authRepository),
 		service.WithPostgreSQLRepository(tixRepository),
-		service.WithMailer(boot.mailer))
-	rest.NewAccountRESTHandler(routerGroupV1, tixService)
-	rest.NewEventRESTHandler(routerGroupV1, tixService)
-	rest.NewUserRESTHandler(routerGroupV1, tixService)
-	job.NewEventJob(tixService, boot.cache)
+		service.WithMailer(boot.mailer),
+		service.WithJobQueue(boot.jobQueue),
+		service.WithResetStatusOnEdit(config.Instance.SyncResetStatusOnEdit),
+		service.WithTicketSigningSecret(config.Instance.TicketSigningSecret),
+		service.WithWalletPassSigner(boot.walletPass),
+		service.WithPublicURL(config.Instance.AppPublicURL),
+		service.WithFileStore(boot.fileStore),
+		service.WithExportAttachmentMaxSize(config.Instance.ExportAttachmentMaxSize))
+}
+
+func (boot *boostrap) newTixAPIProvider() {
+	routerGroupV1 := boot.engine.Group("api/v1")
+	rest.NewAccountRESTHandler(routerGroupV1, boot.tixService)
+	rest.NewEventRESTHandler(routerGroupV1, boot.tixService)
+	rest.NewUserRESTHandler(routerGroupV1, boot.tixService)
+	rest.NewJobRESTHandler(routerGroupV1, boot.tixService)
+	rest.NewTicketRESTHandler(routerGroupV1, boot.tixService)
+	rest.NewExportRESTHandler(routerGroupV1, boot.tixService)
+}
+
+func (boot *boostrap) newWorkerProvider() {
+	leader := job.NewLeaderElection(boot.cache,
+		common.SchedulerLeaderLockKey,
+		common.SchedulerLeaderLockTTL)
+	boot.worker = job.NewEventJob(boot.tixService, boot.cache, boot.jobQueue, leader)
 }
@@ -2,6 +2,8 @@ package rest
 
 import (
 	"context"
+	"fmt"
+	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain"
 	"google.golang.org/api/forms/v1"
 )
@@ -28,13 +30,23 @@ func (repository *googleServiceRepository) GetEvent(
 	return data, nil
 }
 
+// GetResponses follow every next page token and return all responses
+// submitted after the given RFC3339 timestamp (all responses when empty).
 func (repository *googleServiceRepository) GetResponses(
 	ctx context.Context,
-	formID string,
+	formID, submittedAfter string,
 ) (*forms.ListFormResponsesResponse, error) {
-	data, err := repository.googleFormService.Responses().
-		List(formID).Context(ctx).Do()
-	if err != nil {
+	call := repository.googleFormService.Responses().
+		List(formID).PageSize(common.GoogleFormResponsesPageSize)
+	if submittedAfter != "" {
+		call = call.Filter(fmt.Sprintf("timestamp > %s", submittedAfter))
+	}
+
+	data := &forms.ListFormResponsesResponse{}
+	if err := call.Pages(ctx, func(page *forms.ListFormResponsesResponse) error {
+		data.Responses = append(data.Responses, page.Responses...)
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
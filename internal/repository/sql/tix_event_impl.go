@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"github.com/aasumitro/tix/internal/domain/entity"
 	"github.com/aasumitro/tix/internal/domain/request"
+	"time"
 )
 
 func (repository *tixPostgreSQLRepository) GetAllEvents(
@@ -62,6 +63,10 @@ func (repository *tixPostgreSQLRepository) GetEventByGoogleFormID(
 		    events.location, 
 		    events.preregister_date, 
 		    events.event_date,
+		    events.field_mapping,
+		    events.branding,
+		    events.ticket_template,
+		    events.last_respond_synced_at,
 		    COUNT(participants.id) AS total_participants
 		FROM events
 		LEFT JOIN participants on events.id = participants.event_id
@@ -76,6 +81,10 @@ func (repository *tixPostgreSQLRepository) GetEventByGoogleFormID(
 		&event.Name, &event.Location,
 		&event.PreregisterDate,
 		&event.EventDate,
+		&event.FieldMapping,
+		&event.Branding,
+		&event.TicketTemplate,
+		&event.LastRespondSyncedAt,
 		&event.TotalParticipants,
 	); err != nil {
 		return nil, err
@@ -107,3 +116,63 @@ func (repository *tixPostgreSQLRepository) InsertNewEvent(
 	}
 	return event, nil
 }
+
+func (repository *tixPostgreSQLRepository) UpdateEventFieldMapping(
+	ctx context.Context,
+	googleFormID string,
+	mapping entity.FieldMapping,
+) error {
+	query := `
+		UPDATE events SET field_mapping = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, mapping, time.Now().Unix(), googleFormID)
+	var event entity.Event
+	return row.Scan(&event.ID)
+}
+
+func (repository *tixPostgreSQLRepository) UpdateEventBranding(
+	ctx context.Context,
+	googleFormID string,
+	branding entity.Branding,
+) error {
+	query := `
+		UPDATE events SET branding = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, branding, time.Now().Unix(), googleFormID)
+	var event entity.Event
+	return row.Scan(&event.ID)
+}
+
+func (repository *tixPostgreSQLRepository) UpdateEventTicketTemplate(
+	ctx context.Context,
+	googleFormID string,
+	template sql.NullString,
+) error {
+	query := `
+		UPDATE events SET ticket_template = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, template, time.Now().Unix(), googleFormID)
+	var event entity.Event
+	return row.Scan(&event.ID)
+}
+
+func (repository *tixPostgreSQLRepository) UpdateEventLastRespondSyncedAt(
+	ctx context.Context,
+	eventID int32,
+	lastSubmittedTime string,
+) error {
+	query := `
+		UPDATE events SET last_respond_synced_at = $1, updated_at = $2
+		WHERE id = $3 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, lastSubmittedTime, time.Now().Unix(), eventID)
+	var event entity.Event
+	return row.Scan(&event.ID)
+}
@@ -0,0 +1,99 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/aasumitro/tix/internal/domain/entity"
+)
+
+func (repository *tixPostgreSQLRepository) InsertJob(
+	ctx context.Context,
+	job *entity.Job,
+) (
+	id int32,
+	err error,
+) {
+	query := `
+		INSERT INTO jobs (event_id, queue, status, payload, queued_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, job.EventID, job.Queue,
+		job.Status, job.Payload, job.QueuedAt)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (repository *tixPostgreSQLRepository) UpdateJob(
+	ctx context.Context,
+	job *entity.Job,
+	updatedAt int64,
+) error {
+	query := `
+		UPDATE jobs SET status = $1, attempts = $2, error = $3, result = $4,
+		started_at = $5, finished_at = $6, updated_at = $7
+		WHERE id = $8 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, job.Status, job.Attempts, job.Error, job.Result,
+		job.StartedAt, job.FinishedAt, updatedAt, job.ID)
+	var id int32
+	return row.Scan(&id)
+}
+
+func (repository *tixPostgreSQLRepository) GetJobByID(
+	ctx context.Context,
+	id int32,
+) (
+	job *entity.Job,
+	err error,
+) {
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE id = $1 LIMIT 1
+	`
+	row := repository.db.QueryRowContext(ctx, query, id)
+	job = &entity.Job{}
+	if err := scanJob(row, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (repository *tixPostgreSQLRepository) GetJobsByEventID(
+	ctx context.Context,
+	eventID, limit int32,
+) (
+	jobs []*entity.Job,
+	err error,
+) {
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE event_id = $1
+	ORDER BY queued_at DESC, id DESC LIMIT $2
+	`
+	rows, err := repository.db.QueryContext(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var job entity.Job
+		if err := scanJob(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+func scanJob(row interface{ Scan(dest ...any) error }, job *entity.Job) error {
+	return row.Scan(
+		&job.ID, &job.EventID, &job.Queue,
+		&job.Status, &job.Payload, &job.Attempts,
+		&job.Error, &job.Result, &job.QueuedAt,
+		&job.StartedAt, &job.FinishedAt,
+	)
+}
@@ -25,6 +25,8 @@ func (repository *tixPostgreSQLRepository) CountParticipants(
 			query += " AND approved_at IS NULL AND declined_at IS NOT NULL"
 		case common.ParticipantRequestWaiting:
 			query += " AND approved_at IS NULL AND declined_at IS NULL"
+		case common.ParticipantCheckedIn:
+			query += " AND checked_in_at IS NOT NULL"
 		}
 	}
 	if startBetween != 0 && endBetween != 0 {
@@ -49,8 +51,15 @@ func (repository *tixPostgreSQLRepository) GetAllParticipants(
 ) {
 	query := `
 	SELECT id, event_id, name, email, phone, job, pop, 
-	       dob, approved_at, declined_at, declined_reason 
-	FROM participants WHERE event_id = $1
+	       dob, approved_at, declined_at, declined_reason,
+	       custom_answers, ticket_code, checked_in_at, checked_in_by,
+	       ticket_sent_at, created_at, delivery.status, delivery.error,
+	       delivery.message_id, delivery.attempted_at
+	FROM participants LEFT JOIN LATERAL (
+		SELECT status, error, message_id, attempted_at FROM ticket_deliveries
+		WHERE participant_id = participants.id AND attempted_at IS NOT NULL
+		ORDER BY attempted_at DESC, ticket_deliveries.id DESC LIMIT 1
+	) AS delivery ON TRUE WHERE event_id = $1
 	`
 	if filter != "" {
 		query += fmt.Sprintf(" AND (name LIKE '%%%s%%' OR email LIKE '%%%s%%' OR phone LIKE '%%%s%%')", filter, filter, filter)
@@ -80,6 +89,16 @@ func (repository *tixPostgreSQLRepository) GetAllParticipants(
 			&participant.PoP, &participant.DoB,
 			&participant.ApprovedAt, &participant.DeclinedAt,
 			&participant.DeclinedReason,
+			&participant.CustomAnswers,
+			&participant.TicketCode,
+			&participant.CheckedInAt,
+			&participant.CheckedInBy,
+			&participant.TicketSentAt,
+			&participant.CreatedAt,
+			&participant.LastTicketDelivery.Status,
+			&participant.LastTicketDelivery.Error,
+			&participant.LastTicketDelivery.MessageID,
+			&participant.LastTicketDelivery.AttemptedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -95,10 +114,50 @@ func (repository *tixPostgreSQLRepository) GetParticipantByEmailAndEventID(
 	participant *entity.Participant,
 	err error,
 ) {
-	query := "SELECT id  FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1"
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1
+	`
 	row := repository.db.QueryRowContext(ctx, query, email, eventID)
 	participant = &entity.Participant{}
-	if err := row.Scan(&participant.ID); err != nil {
+	if err := row.Scan(
+		&participant.ID, &participant.EventID,
+		&participant.Name, &participant.Email,
+		&participant.Phone, &participant.Job,
+		&participant.PoP, &participant.DoB,
+		&participant.CustomAnswers,
+		&participant.ResponseID,
+		&participant.LastSubmittedTime,
+	); err != nil {
+		return nil, err
+	}
+	return participant, err
+}
+
+func (repository *tixPostgreSQLRepository) GetParticipantByResponseIDAndEventID(
+	ctx context.Context,
+	responseID string, eventID int32,
+) (
+	participant *entity.Participant,
+	err error,
+) {
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE response_id = $1 AND event_id = $2 LIMIT 1
+	`
+	row := repository.db.QueryRowContext(ctx, query, responseID, eventID)
+	participant = &entity.Participant{}
+	if err := row.Scan(
+		&participant.ID, &participant.EventID,
+		&participant.Name, &participant.Email,
+		&participant.Phone, &participant.Job,
+		&participant.PoP, &participant.DoB,
+		&participant.CustomAnswers,
+		&participant.ResponseID,
+		&participant.LastSubmittedTime,
+	); err != nil {
 		return nil, err
 	}
 	return participant, err
@@ -111,15 +170,68 @@ func (repository *tixPostgreSQLRepository) GetParticipantByIDAndEventID(
 	participant *entity.Participant,
 	err error,
 ) {
-	query := "SELECT id, name, email FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1"
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1
+	`
 	row := repository.db.QueryRowContext(ctx, query, participantID, eventID)
 	participant = &entity.Participant{}
-	if err := row.Scan(&participant.ID, &participant.Name, &participant.Email); err != nil {
+	if err := row.Scan(
+		&participant.ID, &participant.EventID,
+		&participant.Name, &participant.Email,
+		&participant.ApprovedAt, &participant.DeclinedAt,
+		&participant.TicketCode, &participant.CheckedInAt,
+		&participant.CheckedInBy,
+	); err != nil {
 		return nil, err
 	}
 	return participant, err
 }
 
+func (repository *tixPostgreSQLRepository) GetParticipantByTicketCode(
+	ctx context.Context,
+	ticketCode string,
+) (
+	participant *entity.Participant,
+	err error,
+) {
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE ticket_code = $1 LIMIT 1
+	`
+	row := repository.db.QueryRowContext(ctx, query, ticketCode)
+	participant = &entity.Participant{}
+	if err := row.Scan(
+		&participant.ID, &participant.EventID,
+		&participant.Name, &participant.Email,
+		&participant.ApprovedAt, &participant.DeclinedAt,
+		&participant.TicketCode, &participant.CheckedInAt,
+		&participant.CheckedInBy,
+	); err != nil {
+		return nil, err
+	}
+	return participant, err
+}
+
+// CheckInParticipant record the participant attendance only once,
+// sql.ErrNoRows is returned when the participant is already checked in.
+func (repository *tixPostgreSQLRepository) CheckInParticipant(
+	ctx context.Context,
+	id int32,
+	checkedInAt int64,
+	checkedInBy string,
+) error {
+	query := `
+		UPDATE participants SET checked_in_at = $1, checked_in_by = $2
+		WHERE id = $3 AND checked_in_at IS NULL RETURNING id;
+	`
+	row := repository.db.QueryRowContext(ctx, query, checkedInAt, checkedInBy, id)
+	data := entity.Participant{}
+	return row.Scan(&data.ID)
+}
+
 func (repository *tixPostgreSQLRepository) InsertManyParticipants(
 	ctx context.Context,
 	participants []*entity.Participant,
@@ -137,8 +249,8 @@ func (repository *tixPostgreSQLRepository) InsertManyParticipants(
 		err = tx.Commit()
 	}()
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO participants (event_id, name, email, phone, job, pop, dob, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO participants (event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`)
 	if err != nil {
 		return err
@@ -148,7 +260,8 @@ func (repository *tixPostgreSQLRepository) InsertManyParticipants(
 		if _, err = stmt.ExecContext(
 			ctx, p.EventID, p.Name, p.Email,
 			p.Phone, p.Job, p.PoP, p.DoB,
-			createdAt,
+			p.CustomAnswers, p.ResponseID,
+			p.LastSubmittedTime, createdAt,
 		); err != nil {
 			return err
 		}
@@ -171,3 +284,182 @@ func (repository *tixPostgreSQLRepository) UpdateParticipants(
 	data := entity.Participant{}
 	return row.Scan(&data.ID)
 }
+
+// UpdateParticipantTicketCode store the ticket code only when the participant
+// does not have one yet, the stored ticket code is returned so the ticket
+// generated twice (e.g. a retried job) keep the same code.
+func (repository *tixPostgreSQLRepository) UpdateParticipantTicketCode(
+	ctx context.Context,
+	id int32,
+	ticketCode string,
+) (
+	storedTicketCode string,
+	err error,
+) {
+	query := `
+		UPDATE participants SET ticket_code = COALESCE(ticket_code, $1)
+		WHERE id = $2 RETURNING ticket_code;
+	`
+	row := repository.db.QueryRowContext(ctx, query, ticketCode, id)
+	if err := row.Scan(&storedTicketCode); err != nil {
+		return "", err
+	}
+	return storedTicketCode, nil
+}
+
+// GetTicketRecipientIDs return the approved (and not declined) participants of
+// the event, when unsentOnly is true only the one never sent a ticket.
+func (repository *tixPostgreSQLRepository) GetTicketRecipientIDs(
+	ctx context.Context,
+	eventID int32,
+	unsentOnly bool,
+) (
+	ids []int32,
+	err error,
+) {
+	query := `
+	SELECT id FROM participants
+	WHERE event_id = $1 AND approved_at IS NOT NULL AND declined_at IS NULL`
+	if unsentOnly {
+		query += " AND ticket_sent_at IS NULL"
+	}
+	query += " ORDER BY id ASC"
+	rows, err := repository.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (repository *tixPostgreSQLRepository) UpdateParticipantTicketSentAt(
+	ctx context.Context,
+	id int32,
+	sentAt int64,
+) error {
+	query := `
+		UPDATE participants SET ticket_sent_at = $1
+		WHERE id = $2 RETURNING id;
+	`
+	row := repository.db.QueryRowContext(ctx, query, sentAt, id)
+	data := entity.Participant{}
+	return row.Scan(&data.ID)
+}
+
+// BackfillParticipantResponse store the google form response id and submitted
+// time of the participant synced before they were stored, the custom answers
+// are only filled when there is none. Nothing else is changed.
+func (repository *tixPostgreSQLRepository) BackfillParticipantResponse(
+	ctx context.Context,
+	participant *entity.Participant,
+) error {
+	query := `
+		UPDATE participants
+		SET response_id = $1, last_submitted_time = $2,
+		    custom_answers = COALESCE(custom_answers, $3)
+		WHERE id = $4`
+	_, err := repository.db.ExecContext(ctx, query,
+		participant.ResponseID, participant.LastSubmittedTime,
+		participant.CustomAnswers, participant.ID)
+	return err
+}
+
+// UpdateParticipantResponse write an edited google form response into the
+// existing participant row and store every changed field in participant_changes.
+// When resetStatus is true the approval state is cleared, so the participant
+// need to be reviewed again.
+func (repository *tixPostgreSQLRepository) UpdateParticipantResponse(
+	ctx context.Context,
+	participant *entity.Participant,
+	changes []*entity.ParticipantChange,
+	resetStatus bool,
+	updatedAt int64,
+) (err error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	query := `
+		UPDATE participants
+		SET name = $1, email = $2, phone = $3, job = $4, pop = $5, dob = $6,
+		    custom_answers = $7, response_id = $8, last_submitted_time = $9, updated_at = $10`
+	if resetStatus {
+		query += ", approved_at = NULL, declined_at = NULL, declined_reason = NULL"
+	}
+	query += " WHERE id = $11"
+	if _, err = tx.ExecContext(
+		ctx, query, participant.Name, participant.Email,
+		participant.Phone, participant.Job, participant.PoP,
+		participant.DoB, participant.CustomAnswers,
+		participant.ResponseID, participant.LastSubmittedTime,
+		updatedAt, participant.ID,
+	); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO participant_changes (event_id, participant_id, field, old_value, new_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, c := range changes {
+		if _, err = stmt.ExecContext(
+			ctx, c.EventID, c.ParticipantID, c.Field,
+			c.OldValue, c.NewValue, updatedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repository *tixPostgreSQLRepository) GetParticipantChanges(
+	ctx context.Context,
+	eventID, participantID int32,
+) (
+	changes []*entity.ParticipantChange,
+	err error,
+) {
+	query := `
+	SELECT id, event_id, participant_id, field, old_value, new_value, created_at
+	FROM participant_changes WHERE event_id = $1 AND participant_id = $2
+	ORDER BY created_at DESC, id DESC
+	`
+	rows, err := repository.db.QueryContext(ctx, query, eventID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var change entity.ParticipantChange
+		if err := rows.Scan(
+			&change.ID, &change.EventID,
+			&change.ParticipantID, &change.Field,
+			&change.OldValue, &change.NewValue,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+	return changes, nil
+}
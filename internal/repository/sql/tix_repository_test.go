@@ -221,8 +221,8 @@ func (s *tixSQLRepositoryTestSuite) Test_GetAllEvent_ShouldError() {
 
 func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldSuccess() {
 	dataMock := s.mock.
-		NewRows([]string{"id", "google_form_id", "name", "location", "preregister_date", "event_date", "total_participants"}).
-		AddRow(1, "123", "tix", "jalan tix", time.Now().Unix(), time.Now().Unix(), 10)
+		NewRows([]string{"id", "google_form_id", "name", "location", "preregister_date", "event_date", "field_mapping", "branding", "ticket_template", "last_respond_synced_at", "total_participants"}).
+		AddRow(1, "123", "tix", "jalan tix", time.Now().Unix(), time.Now().Unix(), []byte(`{"1":"name"}`), []byte(`{"header_title":"lorem"}`), "# lorem", "2023-06-06T10:00:00Z", 10)
 	query := `
 		SELECT 
 		    events.id, 
@@ -231,6 +231,10 @@ func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldSuccess()
 		    events.location, 
 		    events.preregister_date, 
 		    events.event_date,
+		    events.field_mapping,
+		    events.branding,
+		    events.ticket_template,
+		    events.last_respond_synced_at,
 		    COUNT(participants.id) AS total_participants
 		FROM events
 		LEFT JOIN participants on events.id = participants.event_id
@@ -243,6 +247,9 @@ func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldSuccess()
 	s.NotNil(data)
 	s.NoError(err)
 	s.Equal(data.GoogleFormID, "123")
+	s.Equal(data.FieldMapping["1"], "name")
+	s.Equal(data.Branding.HeaderTitle, "lorem")
+	s.Equal(data.TicketTemplate.String, "# lorem")
 }
 func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldError() {
 	query := `
@@ -253,6 +260,10 @@ func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldError() {
 		    events.location, 
 		    events.preregister_date, 
 		    events.event_date,
+		    events.field_mapping,
+		    events.branding,
+		    events.ticket_template,
+		    events.last_respond_synced_at,
 		    COUNT(participants.id) AS total_participants
 		FROM events
 		LEFT JOIN participants on events.id = participants.event_id
@@ -268,8 +279,8 @@ func (s *tixSQLRepositoryTestSuite) Test_GetEventByGoogleFormID_ShouldError() {
 	})
 	s.T().Run("ERROR FROM SCAN", func(t *testing.T) {
 		dataMock := s.mock.
-			NewRows([]string{"id", "google_form_id", "name", "location", "preregister_date", "event_date", "total_participants"}).
-			AddRow(2, nil, nil, nil, nil, nil, nil)
+			NewRows([]string{"id", "google_form_id", "name", "location", "preregister_date", "event_date", "field_mapping", "branding", "ticket_template", "last_respond_synced_at", "total_participants"}).
+			AddRow(2, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
 		data, err := s.repo.GetEventByGoogleFormID(context.TODO(), "123")
 		s.Nil(data)
@@ -322,6 +333,110 @@ func (s *tixSQLRepositoryTestSuite) Test_InsertNewEvent_ShouldError() {
 	s.NotNil(err)
 }
 
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventFieldMapping_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE events SET field_mapping = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs([]byte(`{"1":"name"}`), sqlmock.AnyArg(), "123").
+		WillReturnRows(dataMock)
+	err := s.repo.UpdateEventFieldMapping(context.TODO(), "123", entity.FieldMapping{"1": "name"})
+	s.Nil(err)
+	s.NoError(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventFieldMapping_ShouldError() {
+	query := `
+		UPDATE events SET field_mapping = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs([]byte(`{"1":"name"}`), sqlmock.AnyArg(), "123").
+		WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateEventFieldMapping(context.TODO(), "123", entity.FieldMapping{"1": "name"})
+	s.NotNil(err)
+	s.Error(err)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventBranding_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE events SET branding = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs([]byte(`{"header_title":"lorem"}`), sqlmock.AnyArg(), "123").
+		WillReturnRows(dataMock)
+	err := s.repo.UpdateEventBranding(context.TODO(), "123", entity.Branding{HeaderTitle: "lorem"})
+	s.Nil(err)
+	s.NoError(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventBranding_ShouldError() {
+	query := `
+		UPDATE events SET branding = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs([]byte(`{"header_title":"lorem"}`), sqlmock.AnyArg(), "123").
+		WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateEventBranding(context.TODO(), "123", entity.Branding{HeaderTitle: "lorem"})
+	s.NotNil(err)
+	s.Error(err)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventTicketTemplate_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE events SET ticket_template = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("# lorem", sqlmock.AnyArg(), "123").
+		WillReturnRows(dataMock)
+	err := s.repo.UpdateEventTicketTemplate(context.TODO(), "123", sql.NullString{String: "# lorem", Valid: true})
+	s.Nil(err)
+	s.NoError(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventTicketTemplate_ShouldError() {
+	query := `
+		UPDATE events SET ticket_template = $1, updated_at = $2
+		WHERE google_form_id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs(nil, sqlmock.AnyArg(), "123").
+		WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateEventTicketTemplate(context.TODO(), "123", sql.NullString{})
+	s.NotNil(err)
+	s.Error(err)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventLastRespondSyncedAt_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE events SET last_respond_synced_at = $1, updated_at = $2
+		WHERE id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("2023-06-06T10:00:00Z", sqlmock.AnyArg(), 1).
+		WillReturnRows(dataMock)
+	err := s.repo.UpdateEventLastRespondSyncedAt(context.TODO(), 1, "2023-06-06T10:00:00Z")
+	s.Nil(err)
+	s.NoError(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateEventLastRespondSyncedAt_ShouldError() {
+	query := `
+		UPDATE events SET last_respond_synced_at = $1, updated_at = $2
+		WHERE id = $3 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("2023-06-06T10:00:00Z", sqlmock.AnyArg(), 1).
+		WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateEventLastRespondSyncedAt(context.TODO(), 1, "2023-06-06T10:00:00Z")
+	s.NotNil(err)
+	s.Error(err)
+}
+
 // ===============================================================
 // PART OF PARTICIPANT TEST CASE
 // ===============================================================
@@ -363,6 +478,17 @@ func (s *tixSQLRepositoryTestSuite) Test_CountParticipant_ShouldSuccess() {
 		s.NotZero(res)
 		s.Equal(1, res)
 	})
+	s.T().Run("COUNT CHECKED IN", func(t *testing.T) {
+		count := s.mock.
+			NewRows([]string{"total"}).
+			AddRow(1)
+		query := "SELECT COUNT(*) AS total FROM participants WHERE event_id = $1 AND checked_in_at IS NOT NULL"
+		expectedQuery := regexp.QuoteMeta(query)
+		s.mock.ExpectQuery(expectedQuery).WillReturnRows(count)
+		res := s.repo.CountParticipants(context.TODO(), 1, common.ParticipantCheckedIn, 0, 0)
+		s.NotZero(res)
+		s.Equal(1, res)
+	})
 }
 func (s *tixSQLRepositoryTestSuite) Test_CountParticipant_ShouldError() {
 	query := "SELECT COUNT(*) AS total FROM participants WHERE event_id = $1 AND approved_at IS NULL AND declined_at IS NULL"
@@ -373,14 +499,27 @@ func (s *tixSQLRepositoryTestSuite) Test_CountParticipant_ShouldError() {
 	s.Equal(0, res)
 }
 
+var participantListColumns = []string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob",
+	"approved_at", "declined_at", "declined_reason", "custom_answers", "ticket_code", "checked_in_at",
+	"checked_in_by", "ticket_sent_at", "created_at", "status", "error", "message_id", "attempted_at"}
+
 func (s *tixSQLRepositoryTestSuite) Test_GetAllParticipant_ShouldSuccess() {
 	dataMock := s.mock.
-		NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob", "approved_at", "declined_at", "declined_reason"}).
-		AddRow(1, 1, "tix", "hellO@tix.id", "082271119900", "SE", "http://bukti.id/123", "1990-12-12", nil, nil, nil)
+		NewRows(participantListColumns).
+		AddRow(1, 1, "tix", "hellO@tix.id", "082271119900", "SE", "http://bukti.id/123", "1990-12-12", nil, nil, nil,
+			[]byte(`{"7":{"question":"Company","answer":"BAKODE","position":0}}`), "7K2M-QX4D-9PLA-ZR3T", nil, nil,
+			nil, 1686600000, "failed", "dial tcp: connection refused", nil, 1686700000)
 	query := `
 	SELECT id, event_id, name, email, phone, job, pop, 
-	       dob, approved_at, declined_at, declined_reason 
-	FROM participants WHERE event_id = $1`
+	       dob, approved_at, declined_at, declined_reason,
+	       custom_answers, ticket_code, checked_in_at, checked_in_by,
+	       ticket_sent_at, created_at, delivery.status, delivery.error,
+	       delivery.message_id, delivery.attempted_at
+	FROM participants LEFT JOIN LATERAL (
+		SELECT status, error, message_id, attempted_at FROM ticket_deliveries
+		WHERE participant_id = participants.id AND attempted_at IS NOT NULL
+		ORDER BY attempted_at DESC, ticket_deliveries.id DESC LIMIT 1
+	) AS delivery ON TRUE WHERE event_id = $1`
 	query += fmt.Sprintf(" AND (name LIKE '%%%s%%' OR email LIKE '%%%s%%' OR phone LIKE '%%%s%%')", "tix", "tix", "tix")
 	now := time.Now().Unix()
 	start := time.Unix(now, 0).Format(time.RFC3339)
@@ -394,13 +533,27 @@ func (s *tixSQLRepositoryTestSuite) Test_GetAllParticipant_ShouldSuccess() {
 	s.Nil(err)
 	s.NoError(err)
 	s.NotNil(res)
+	s.Equal("BAKODE", res[0].CustomAnswers["7"].Answer)
+	s.Equal("7K2M-QX4D-9PLA-ZR3T", res[0].TicketCode.String)
+	s.False(res[0].TicketSentAt.Valid)
+	s.Equal(int32(1686600000), res[0].CreatedAt.Int32)
+	s.Equal("failed", res[0].LastTicketDelivery.Status.String)
+	s.Equal("dial tcp: connection refused", res[0].LastTicketDelivery.Error.String)
+	s.Equal(int32(1686700000), res[0].LastTicketDelivery.AttemptedAt.Int32)
 }
 func (s *tixSQLRepositoryTestSuite) Test_GetAllParticipant_ShouldError() {
 	s.T().Run("ERROR FROM QUERY", func(t *testing.T) {
 		query := `
-		SELECT id, event_id, name, email, phone, job, pop, 
-			   dob, approved_at, declined_at, declined_reason 
-		FROM participants WHERE event_id = $1`
+		SELECT id, event_id, name, email, phone, job, pop,
+		dob, approved_at, declined_at, declined_reason,
+		custom_answers, ticket_code, checked_in_at, checked_in_by,
+		ticket_sent_at, created_at, delivery.status, delivery.error,
+		delivery.message_id, delivery.attempted_at
+		FROM participants LEFT JOIN LATERAL (
+		SELECT status, error, message_id, attempted_at FROM ticket_deliveries
+		WHERE participant_id = participants.id AND attempted_at IS NOT NULL
+		ORDER BY attempted_at DESC, ticket_deliveries.id DESC LIMIT 1
+		) AS delivery ON TRUE WHERE event_id = $1`
 		expectedQuery := regexp.QuoteMeta(query)
 		s.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("hello"))
 		res, err := s.repo.GetAllParticipants(context.TODO(), 1, "", 0, 0, 0, "", "")
@@ -410,12 +563,20 @@ func (s *tixSQLRepositoryTestSuite) Test_GetAllParticipant_ShouldError() {
 	})
 	s.T().Run("ERROR FROM SCAN", func(t *testing.T) {
 		dataMock := s.mock.
-			NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob", "approved_at", "declined_at", "declined_reason"}).
-			AddRow(1, 1, nil, nil, "082271119900", "SE", "http://bukti.id/123", "1990-12-12", nil, nil, nil)
+			NewRows(participantListColumns).
+			AddRow(1, 1, nil, nil, "082271119900", "SE", "http://bukti.id/123", "1990-12-12", nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil)
 		query := `
-		SELECT id, event_id, name, email, phone, job, pop, 
-			   dob, approved_at, declined_at, declined_reason 
-		FROM participants WHERE event_id = $1`
+		SELECT id, event_id, name, email, phone, job, pop,
+		dob, approved_at, declined_at, declined_reason,
+		custom_answers, ticket_code, checked_in_at, checked_in_by,
+		ticket_sent_at, created_at, delivery.status, delivery.error,
+		delivery.message_id, delivery.attempted_at
+		FROM participants LEFT JOIN LATERAL (
+		SELECT status, error, message_id, attempted_at FROM ticket_deliveries
+		WHERE participant_id = participants.id AND attempted_at IS NOT NULL
+		ORDER BY attempted_at DESC, ticket_deliveries.id DESC LIMIT 1
+		) AS delivery ON TRUE WHERE event_id = $1`
 		expectedQuery := regexp.QuoteMeta(query)
 		s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
 		res, err := s.repo.GetAllParticipants(context.TODO(), 1, "", 0, 0, 0, "", "")
@@ -426,38 +587,102 @@ func (s *tixSQLRepositoryTestSuite) Test_GetAllParticipant_ShouldError() {
 }
 
 func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByEmailAndEventID_ShouldSuccess() {
-	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
-	query := "SELECT id  FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1"
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob",
+		"custom_answers", "response_id", "last_submitted_time"}).
+		AddRow(1, 1, "tix", "hello@tix.id", "08272229292", "lorem", "http://bukti.id/1312312",
+			"1990-12-12", []byte(`{"3":{"question":"ukuran baju","answer":"L","position":6}}`),
+			"ACYDBNi84NuJUO", "2023-06-06T10:00:00Z")
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1
+	`
 	expectedQuery := regexp.QuoteMeta(query)
-	s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("hello@tix.id", 1).WillReturnRows(dataMock)
 	data, err := s.repo.GetParticipantByEmailAndEventID(context.TODO(), "hello@tix.id", 1)
 	s.NotNil(data)
 	s.NoError(err)
 	s.Equal(data.ID, int32(1))
+	s.Equal(data.ResponseID.String, "ACYDBNi84NuJUO")
+	s.Equal(data.CustomAnswers["3"].Answer, "L")
 }
 func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByEmailAndEventID_ShouldError() {
-	dataMock := s.mock.NewRows([]string{"id"}).AddRow(nil)
-	query := "SELECT id  FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1"
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob",
+		"custom_answers", "response_id", "last_submitted_time"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE email = $1 AND event_id = $2 LIMIT 1
+	`
 	expectedQuery := regexp.QuoteMeta(query)
-	s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("hello@tix.id", 1).WillReturnRows(dataMock)
 	data, err := s.repo.GetParticipantByEmailAndEventID(context.TODO(), "hello@tix.id", 1)
 	s.Nil(data)
 	s.Error(err)
 }
 
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByResponseIDAndEventID_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob",
+		"custom_answers", "response_id", "last_submitted_time"}).
+		AddRow(1, 1, "tix", "hello@tix.id", "08272229292", "lorem", "http://bukti.id/1312312",
+			"1990-12-12", []byte(`{"3":{"question":"ukuran baju","answer":"L","position":6}}`),
+			"ACYDBNi84NuJUO", "2023-06-06T10:00:00Z")
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE response_id = $1 AND event_id = $2 LIMIT 1
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("ACYDBNi84NuJUO", 1).WillReturnRows(dataMock)
+	data, err := s.repo.GetParticipantByResponseIDAndEventID(context.TODO(), "ACYDBNi84NuJUO", 1)
+	s.NotNil(data)
+	s.NoError(err)
+	s.Equal(data.ID, int32(1))
+	s.Equal(data.ResponseID.String, "ACYDBNi84NuJUO")
+	s.Equal(data.CustomAnswers["3"].Answer, "L")
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByResponseIDAndEventID_ShouldError() {
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "phone", "job", "pop", "dob",
+		"custom_answers", "response_id", "last_submitted_time"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := `
+	SELECT id, event_id, name, email, phone, job, pop, dob,
+	       custom_answers, response_id, last_submitted_time
+	FROM participants WHERE response_id = $1 AND event_id = $2 LIMIT 1
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("ACYDBNi84NuJUO", 1).WillReturnRows(dataMock)
+	data, err := s.repo.GetParticipantByResponseIDAndEventID(context.TODO(), "ACYDBNi84NuJUO", 1)
+	s.Nil(data)
+	s.Error(err)
+}
+
 func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByParticipantIDAndEventID_ShouldSuccess() {
-	dataMock := s.mock.NewRows([]string{"id", "name", "email"}).AddRow(1, "lorem", "lorem@lorem.id")
-	query := "SELECT  id, name, email FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1"
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "approved_at", "declined_at", "ticket_code", "checked_in_at", "checked_in_by"}).
+		AddRow(1, 1, "lorem", "lorem@lorem.id", time.Now().Unix(), nil, "7K2M-QX4D-9PLA-ZR3T", nil, nil)
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1
+	`
 	expectedQuery := regexp.QuoteMeta(query)
 	s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
 	data, err := s.repo.GetParticipantByIDAndEventID(context.TODO(), 1, 1)
 	s.NotNil(data)
 	s.NoError(err)
 	s.Equal(data.ID, int32(1))
+	s.True(data.ApprovedAt.Valid)
+	s.Equal("7K2M-QX4D-9PLA-ZR3T", data.TicketCode.String)
 }
 func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByParticipantIDAndEventID_ShouldError() {
-	dataMock := s.mock.NewRows([]string{"id", "name", "email"}).AddRow(nil, nil, nil)
-	query := "SELECT id, name, email FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1"
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "approved_at", "declined_at", "ticket_code", "checked_in_at", "checked_in_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE id = $1 AND event_id = $2 LIMIT 1
+	`
 	expectedQuery := regexp.QuoteMeta(query)
 	s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
 	data, err := s.repo.GetParticipantByIDAndEventID(context.TODO(), 1, 1)
@@ -465,10 +690,68 @@ func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByParticipantIDAndEventID
 	s.Error(err)
 }
 
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByTicketCode_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "name", "email", "approved_at", "declined_at", "ticket_code", "checked_in_at", "checked_in_by"}).
+		AddRow(1, 1, "lorem", "lorem@lorem.id", time.Now().Unix(), nil, "7K2M-QX4D-9PLA-ZR3T", nil, nil)
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE ticket_code = $1 LIMIT 1
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("7K2M-QX4D-9PLA-ZR3T").WillReturnRows(dataMock)
+	data, err := s.repo.GetParticipantByTicketCode(context.TODO(), "7K2M-QX4D-9PLA-ZR3T")
+	s.Nil(err)
+	s.Equal(int32(1), data.ID)
+	s.True(data.ApprovedAt.Valid)
+	s.False(data.CheckedInAt.Valid)
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantByTicketCode_ShouldError() {
+	query := `
+	SELECT id, event_id, name, email, approved_at, declined_at,
+	       ticket_code, checked_in_at, checked_in_by
+	FROM participants WHERE ticket_code = $1 LIMIT 1
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs("7K2M-QX4D-9PLA-ZR3T").WillReturnError(sql.ErrNoRows)
+	data, err := s.repo.GetParticipantByTicketCode(context.TODO(), "7K2M-QX4D-9PLA-ZR3T")
+	s.Nil(data)
+	s.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_CheckInParticipant_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE participants SET checked_in_at = $1, checked_in_by = $2
+		WHERE id = $3 AND checked_in_at IS NULL RETURNING id;
+	`
+	now := time.Now().Unix()
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs(now, "lorem@lorem.id", 1).
+		WillReturnRows(dataMock)
+	err := s.repo.CheckInParticipant(context.TODO(), 1, now, "lorem@lorem.id")
+	s.Nil(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_CheckInParticipant_ShouldError() {
+	query := `
+		UPDATE participants SET checked_in_at = $1, checked_in_by = $2
+		WHERE id = $3 AND checked_in_at IS NULL RETURNING id;
+	`
+	now := time.Now().Unix()
+	expectedQuery := regexp.QuoteMeta(query)
+	// already checked in, nothing is updated
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs(now, "lorem@lorem.id", 1).
+		WillReturnRows(s.mock.NewRows([]string{"id"}))
+	err := s.repo.CheckInParticipant(context.TODO(), 1, now, "lorem@lorem.id")
+	s.ErrorIs(err, sql.ErrNoRows)
+}
+
 func (s *tixSQLRepositoryTestSuite) Test_InsertManyParticipants_ShouldSuccess() {
 	s.mock.ExpectBegin()
-	s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\).*`)
-	s.mock.ExpectExec(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\).*`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\).*`)
+	s.mock.ExpectExec(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\).*`).WillReturnResult(sqlmock.NewResult(1, 1))
 	s.mock.ExpectCommit()
 	err := s.repo.InsertManyParticipants(context.Background(), []*entity.Participant{{
 		EventID: 1,
@@ -499,7 +782,7 @@ func (s *tixSQLRepositoryTestSuite) Test_InsertManyParticipants_ShouldError() {
 	})
 	s.T().Run("ERROR PREPARE TX", func(t *testing.T) {
 		s.mock.ExpectBegin()
-		s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\).*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\).*`).WillReturnError(errors.New("lorem"))
 		err := s.repo.InsertManyParticipants(context.Background(), []*entity.Participant{{
 			EventID: 1,
 			Name:    "tix",
@@ -514,8 +797,8 @@ func (s *tixSQLRepositoryTestSuite) Test_InsertManyParticipants_ShouldError() {
 	})
 	s.T().Run("ERROR EXEC TX", func(t *testing.T) {
 		s.mock.ExpectBegin()
-		s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\).*`)
-		s.mock.ExpectExec(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\).*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectPrepare(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\).*`)
+		s.mock.ExpectExec(`.*INSERT INTO participants \(event_id, name, email, phone, job, pop, dob, custom_answers, response_id, last_submitted_time, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11\).*`).WillReturnError(errors.New("lorem"))
 		err := s.repo.InsertManyParticipants(context.Background(), []*entity.Participant{{
 			EventID: 1,
 			Name:    "tix",
@@ -562,6 +845,477 @@ func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipants_ShouldError() {
 	s.Error(err)
 }
 
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantTicketCode_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"ticket_code"}).AddRow("7K2M-QX4D-9PLA-ZR3T")
+	query := `
+		UPDATE participants SET ticket_code = COALESCE(ticket_code, $1)
+		WHERE id = $2 RETURNING ticket_code;
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("AAAA-BBBB-CCCC-DDDD", 1).
+		WillReturnRows(dataMock)
+	// the participant already has a ticket code, it is kept
+	code, err := s.repo.UpdateParticipantTicketCode(context.TODO(), 1, "AAAA-BBBB-CCCC-DDDD")
+	s.Nil(err)
+	s.Equal("7K2M-QX4D-9PLA-ZR3T", code)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantTicketCode_ShouldError() {
+	query := `
+		UPDATE participants SET ticket_code = COALESCE(ticket_code, $1)
+		WHERE id = $2 RETURNING ticket_code;
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("AAAA-BBBB-CCCC-DDDD", 1).
+		WillReturnError(errors.New("lorem"))
+	code, err := s.repo.UpdateParticipantTicketCode(context.TODO(), 1, "AAAA-BBBB-CCCC-DDDD")
+	s.NotNil(err)
+	s.Empty(code)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantResponse_ShouldSuccess() {
+	participant := &entity.Participant{
+		ID:                1,
+		EventID:           1,
+		Name:              "tix",
+		Email:             "hello@tix.id",
+		Phone:             "08111111111",
+		Job:               "lorem",
+		PoP:               "http://bukti.id/1312312",
+		DoB:               "1990-12-12",
+		ResponseID:        sql.NullString{String: "ACYDBNi84NuJUO", Valid: true},
+		LastSubmittedTime: sql.NullString{String: "2023-06-06T10:00:00Z", Valid: true},
+	}
+	changes := []*entity.ParticipantChange{{
+		EventID:       1,
+		ParticipantID: 1,
+		Field:         "phone",
+		OldValue:      "08272229292",
+		NewValue:      "08111111111",
+	}}
+	s.T().Run("KEEP STATUS", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*UPDATE participants SET name = \$1, email = \$2, phone = \$3, job = \$4, pop = \$5, dob = \$6, custom_answers = \$7, response_id = \$8, last_submitted_time = \$9, updated_at = \$10 WHERE id = \$11`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectPrepare(`.*INSERT INTO participant_changes \(event_id, participant_id, field, old_value, new_value, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\).*`)
+		s.mock.ExpectExec(`.*INSERT INTO participant_changes.*`).
+			WithArgs(1, 1, "phone", "08272229292", "08111111111", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		s.mock.ExpectCommit()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, false, time.Now().Unix())
+		s.Nil(err)
+	})
+	s.T().Run("RESET STATUS", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*updated_at = \$10, approved_at = NULL, declined_at = NULL, declined_reason = NULL WHERE id = \$11`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectPrepare(`.*INSERT INTO participant_changes.*`)
+		s.mock.ExpectExec(`.*INSERT INTO participant_changes.*`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		s.mock.ExpectCommit()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, true, time.Now().Unix())
+		s.Nil(err)
+	})
+	s.T().Run("WITHOUT CHANGES", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*UPDATE participants.*`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectCommit()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, nil, false, time.Now().Unix())
+		s.Nil(err)
+	})
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantResponse_ShouldError() {
+	participant := &entity.Participant{ID: 1, EventID: 1}
+	changes := []*entity.ParticipantChange{{EventID: 1, ParticipantID: 1, Field: "phone"}}
+	s.T().Run("ERROR BEGIN TX", func(t *testing.T) {
+		s.mock.ExpectBegin().WillReturnError(errors.New("lorem"))
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, false, time.Now().Unix())
+		s.NotNil(err)
+	})
+	s.T().Run("ERROR UPDATE TX", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*UPDATE participants.*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectRollback()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, false, time.Now().Unix())
+		s.NotNil(err)
+	})
+	s.T().Run("ERROR PREPARE TX", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*UPDATE participants.*`).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectPrepare(`.*INSERT INTO participant_changes.*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectRollback()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, false, time.Now().Unix())
+		s.NotNil(err)
+	})
+	s.T().Run("ERROR EXEC TX", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectExec(`.*UPDATE participants.*`).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.mock.ExpectPrepare(`.*INSERT INTO participant_changes.*`)
+		s.mock.ExpectExec(`.*INSERT INTO participant_changes.*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectRollback()
+		err := s.repo.UpdateParticipantResponse(context.TODO(), participant, changes, false, time.Now().Unix())
+		s.NotNil(err)
+	})
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantChanges_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id", "event_id", "participant_id", "field", "old_value", "new_value", "created_at"}).
+		AddRow(1, 1, 1, "phone", "08272229292", "08111111111", time.Now().Unix())
+	query := `
+	SELECT id, event_id, participant_id, field, old_value, new_value, created_at
+	FROM participant_changes WHERE event_id = $1 AND participant_id = $2
+	ORDER BY created_at DESC, id DESC
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs(1, 1).WillReturnRows(dataMock)
+	res, err := s.repo.GetParticipantChanges(context.TODO(), 1, 1)
+	s.Nil(err)
+	s.NoError(err)
+	s.NotNil(res)
+	s.Equal(len(res), 1)
+	s.Equal(res[0].Field, "phone")
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetParticipantChanges_ShouldError() {
+	query := `
+	SELECT id, event_id, participant_id, field, old_value, new_value, created_at
+	FROM participant_changes WHERE event_id = $1 AND participant_id = $2
+	ORDER BY created_at DESC, id DESC
+	`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.T().Run("ERROR QUERY", func(t *testing.T) {
+		s.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("lorem"))
+		res, err := s.repo.GetParticipantChanges(context.TODO(), 1, 1)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+	s.T().Run("ERROR SCAN", func(t *testing.T) {
+		dataMock := s.mock.NewRows([]string{"id", "event_id", "participant_id", "field", "old_value", "new_value", "created_at"}).
+			AddRow(nil, nil, nil, nil, nil, nil, nil)
+		s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
+		res, err := s.repo.GetParticipantChanges(context.TODO(), 1, 1)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+}
+
+// ===============================================================
+// PART OF JOB TEST CASE
+// ===============================================================
+
+var jobColumns = []string{"id", "event_id", "queue", "status", "payload", "attempts",
+	"error", "result", "queued_at", "started_at", "finished_at"}
+
+func (s *tixSQLRepositoryTestSuite) Test_InsertJob_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		INSERT INTO jobs (event_id, queue, status, payload, queued_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs(1, "req_sync_event_queue", "queued", `{"google_form_id":"asd"}`, sqlmock.AnyArg()).
+		WillReturnRows(dataMock)
+	id, err := s.repo.InsertJob(context.TODO(), &entity.Job{
+		EventID: 1,
+		Queue:   "req_sync_event_queue",
+		Status:  "queued",
+		Payload: `{"google_form_id":"asd"}`,
+	})
+	s.Nil(err)
+	s.Equal(int32(1), id)
+}
+func (s *tixSQLRepositoryTestSuite) Test_InsertJob_ShouldError() {
+	query := `
+		INSERT INTO jobs (event_id, queue, status, payload, queued_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("lorem"))
+	id, err := s.repo.InsertJob(context.TODO(), &entity.Job{EventID: 1})
+	s.NotNil(err)
+	s.Zero(id)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_UpdateJob_ShouldSuccess() {
+	dataMock := s.mock.NewRows([]string{"id"}).AddRow(1)
+	query := `
+		UPDATE jobs SET status = $1, attempts = $2, error = $3, result = $4,
+		started_at = $5, finished_at = $6, updated_at = $7
+		WHERE id = $8 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).
+		WithArgs("running", 1, nil, nil, 1, nil, 2, 1).
+		WillReturnRows(dataMock)
+	err := s.repo.UpdateJob(context.TODO(), &entity.Job{
+		ID:        1,
+		Status:    "running",
+		Attempts:  1,
+		StartedAt: sql.NullInt32{Int32: 1, Valid: true},
+	}, 2)
+	s.Nil(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateJob_ShouldError() {
+	query := `
+		UPDATE jobs SET status = $1, attempts = $2, error = $3, result = $4,
+		started_at = $5, finished_at = $6, updated_at = $7
+		WHERE id = $8 RETURNING id;`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateJob(context.TODO(), &entity.Job{ID: 1}, 2)
+	s.NotNil(err)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_GetJobByID_ShouldSuccess() {
+	dataMock := s.mock.NewRows(jobColumns).
+		AddRow(1, 1, "req_sync_event_queue", "succeeded", `{"google_form_id":"asd"}`,
+			1, nil, "event asd synced", 1, 2, 3)
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE id = $1 LIMIT 1`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(dataMock)
+	res, err := s.repo.GetJobByID(context.TODO(), 1)
+	s.Nil(err)
+	s.Equal("succeeded", res.Status)
+	s.Equal("event asd synced", res.Result.String)
+	s.False(res.Error.Valid)
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetJobByID_ShouldError() {
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE id = $1 LIMIT 1`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WillReturnError(sql.ErrNoRows)
+	res, err := s.repo.GetJobByID(context.TODO(), 1)
+	s.Equal(err, sql.ErrNoRows)
+	s.Nil(res)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_GetJobsByEventID_ShouldSuccess() {
+	dataMock := s.mock.NewRows(jobColumns).
+		AddRow(2, 1, "req_sync_event_queue", "running", `{"google_form_id":"asd"}`,
+			1, nil, nil, 2, 2, nil).
+		AddRow(1, 1, "req_sync_event_queue", "failed", `{"google_form_id":"asd"}`,
+			3, "lorem", nil, 1, 1, 1)
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE event_id = $1
+	ORDER BY queued_at DESC, id DESC LIMIT $2`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.mock.ExpectQuery(expectedQuery).WithArgs(1, 50).WillReturnRows(dataMock)
+	res, err := s.repo.GetJobsByEventID(context.TODO(), 1, 50)
+	s.Nil(err)
+	s.Equal(2, len(res))
+	s.Equal("lorem", res[1].Error.String)
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetJobsByEventID_ShouldError() {
+	query := `
+	SELECT id, event_id, queue, status, payload, attempts, error,
+	result, queued_at, started_at, finished_at FROM jobs WHERE event_id = $1
+	ORDER BY queued_at DESC, id DESC LIMIT $2`
+	expectedQuery := regexp.QuoteMeta(query)
+	s.T().Run("ERROR QUERY", func(t *testing.T) {
+		s.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New("lorem"))
+		res, err := s.repo.GetJobsByEventID(context.TODO(), 1, 50)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+	s.T().Run("ERROR SCAN", func(t *testing.T) {
+		dataMock := s.mock.NewRows(jobColumns).
+			AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		s.mock.ExpectQuery(expectedQuery).WillReturnRows(dataMock)
+		res, err := s.repo.GetJobsByEventID(context.TODO(), 1, 50)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_GetTicketRecipientIDs_ShouldSuccess() {
+	s.T().Run("APPROVED", func(t *testing.T) {
+		query := `
+	SELECT id FROM participants
+	WHERE event_id = $1 AND approved_at IS NOT NULL AND declined_at IS NULL ORDER BY id ASC`
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).
+			WillReturnRows(s.mock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		res, err := s.repo.GetTicketRecipientIDs(context.TODO(), 1, false)
+		s.Nil(err)
+		s.Equal([]int32{1, 2}, res)
+	})
+	s.T().Run("UNSENT", func(t *testing.T) {
+		query := `
+	SELECT id FROM participants
+	WHERE event_id = $1 AND approved_at IS NOT NULL AND declined_at IS NULL AND ticket_sent_at IS NULL ORDER BY id ASC`
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).
+			WillReturnRows(s.mock.NewRows([]string{"id"}).AddRow(2))
+		res, err := s.repo.GetTicketRecipientIDs(context.TODO(), 1, true)
+		s.Nil(err)
+		s.Equal([]int32{2}, res)
+	})
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetTicketRecipientIDs_ShouldError() {
+	s.T().Run("ERROR QUERY", func(t *testing.T) {
+		s.mock.ExpectQuery(`.*SELECT id FROM participants.*`).WillReturnError(errors.New("lorem"))
+		res, err := s.repo.GetTicketRecipientIDs(context.TODO(), 1, false)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+	s.T().Run("ERROR SCAN", func(t *testing.T) {
+		s.mock.ExpectQuery(`.*SELECT id FROM participants.*`).
+			WillReturnRows(s.mock.NewRows([]string{"id"}).AddRow(nil))
+		res, err := s.repo.GetTicketRecipientIDs(context.TODO(), 1, false)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_BackfillParticipantResponse() {
+	participant := &entity.Participant{
+		ID:                1,
+		ResponseID:        sql.NullString{String: "ACYDBNi84NuJUO", Valid: true},
+		LastSubmittedTime: sql.NullString{String: "2023-06-06T10:00:00Z", Valid: true},
+	}
+	s.mock.ExpectExec(`.*UPDATE participants SET response_id = \$1, last_submitted_time = \$2, custom_answers = COALESCE\(custom_answers, \$3\) WHERE id = \$4`).
+		WithArgs(participant.ResponseID, participant.LastSubmittedTime, participant.CustomAnswers, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.Nil(s.repo.BackfillParticipantResponse(context.TODO(), participant))
+	s.mock.ExpectExec(`.*UPDATE participants SET response_id.*`).WillReturnError(errors.New("lorem"))
+	s.NotNil(s.repo.BackfillParticipantResponse(context.TODO(), participant))
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantTicketSentAt_ShouldSuccess() {
+	query := `
+		UPDATE participants SET ticket_sent_at = $1
+		WHERE id = $2 RETURNING id;`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 1).
+		WillReturnRows(s.mock.NewRows([]string{"id"}).AddRow(1))
+	err := s.repo.UpdateParticipantTicketSentAt(context.TODO(), 1, 2)
+	s.Nil(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_UpdateParticipantTicketSentAt_ShouldError() {
+	s.mock.ExpectQuery(`.*UPDATE participants SET ticket_sent_at.*`).WillReturnError(errors.New("lorem"))
+	err := s.repo.UpdateParticipantTicketSentAt(context.TODO(), 1, 2)
+	s.NotNil(err)
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_InsertTicketDeliveries_ShouldSuccess() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectPrepare(`.*INSERT INTO ticket_deliveries \(job_id, participant_id, status, updated_at\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(job_id, participant_id\) DO NOTHING.*`)
+	s.mock.ExpectExec(`.*INSERT INTO ticket_deliveries.*`).
+		WithArgs(7, 1, "queued", 2).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`.*INSERT INTO ticket_deliveries.*`).
+		WithArgs(7, 2, "queued", 2).WillReturnResult(sqlmock.NewResult(2, 1))
+	s.mock.ExpectCommit()
+	err := s.repo.InsertTicketDeliveries(context.TODO(), 7, []int32{1, 2}, 2)
+	s.Nil(err)
+}
+func (s *tixSQLRepositoryTestSuite) Test_InsertTicketDeliveries_ShouldError() {
+	s.T().Run("ERROR BEGIN TX", func(t *testing.T) {
+		s.mock.ExpectBegin().WillReturnError(errors.New("lorem"))
+		err := s.repo.InsertTicketDeliveries(context.TODO(), 7, []int32{1}, 2)
+		s.NotNil(err)
+	})
+	s.T().Run("ERROR PREPARE TX", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectPrepare(`.*INSERT INTO ticket_deliveries.*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectRollback()
+		err := s.repo.InsertTicketDeliveries(context.TODO(), 7, []int32{1}, 2)
+		s.NotNil(err)
+	})
+	s.T().Run("ERROR EXEC TX", func(t *testing.T) {
+		s.mock.ExpectBegin()
+		s.mock.ExpectPrepare(`.*INSERT INTO ticket_deliveries.*`)
+		s.mock.ExpectExec(`.*INSERT INTO ticket_deliveries.*`).WillReturnError(errors.New("lorem"))
+		s.mock.ExpectRollback()
+		err := s.repo.InsertTicketDeliveries(context.TODO(), 7, []int32{1}, 2)
+		s.NotNil(err)
+	})
+}
+
+var ticketDeliveryColumns = []string{"id", "job_id", "participant_id", "name",
+	"email", "status", "error", "message_id", "attempts", "attempted_at", "updated_at"}
+
+func (s *tixSQLRepositoryTestSuite) Test_GetTicketDeliveriesByJobID_ShouldSuccess() {
+	dataMock := s.mock.NewRows(ticketDeliveryColumns).
+		AddRow(1, 7, 1, "lorem", "lorem@tix.id", "sent", nil, "<a1b2@tix.id>", 1, 2, 2).
+		AddRow(2, 7, 2, "ipsum", "ipsum@tix.id", "failed", "lorem", nil, 3, 2, 2)
+	query := `
+	SELECT ticket_deliveries.id, ticket_deliveries.job_id, ticket_deliveries.participant_id,
+	participants.name, participants.email, ticket_deliveries.status,
+	ticket_deliveries.error, ticket_deliveries.message_id, ticket_deliveries.attempts,
+	ticket_deliveries.attempted_at, ticket_deliveries.updated_at
+	FROM ticket_deliveries
+	JOIN participants ON participants.id = ticket_deliveries.participant_id
+	WHERE ticket_deliveries.job_id = $1 ORDER BY ticket_deliveries.id ASC`
+	s.mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(7).WillReturnRows(dataMock)
+	res, err := s.repo.GetTicketDeliveriesByJobID(context.TODO(), 7)
+	s.Nil(err)
+	s.Equal(2, len(res))
+	s.Equal("lorem@tix.id", res[0].ParticipantEmail)
+	s.Equal("<a1b2@tix.id>", res[0].MessageID.String)
+	s.Equal("lorem", res[1].Error.String)
+	s.Equal(int32(3), res[1].Attempts)
+}
+func (s *tixSQLRepositoryTestSuite) Test_GetTicketDeliveriesByJobID_ShouldError() {
+	s.T().Run("ERROR QUERY", func(t *testing.T) {
+		s.mock.ExpectQuery(`.*FROM ticket_deliveries.*`).WillReturnError(errors.New("lorem"))
+		res, err := s.repo.GetTicketDeliveriesByJobID(context.TODO(), 7)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+	s.T().Run("ERROR SCAN", func(t *testing.T) {
+		dataMock := s.mock.NewRows(ticketDeliveryColumns).
+			AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		s.mock.ExpectQuery(`.*FROM ticket_deliveries.*`).WillReturnRows(dataMock)
+		res, err := s.repo.GetTicketDeliveriesByJobID(context.TODO(), 7)
+		s.NotNil(err)
+		s.Nil(res)
+	})
+}
+
+func (s *tixSQLRepositoryTestSuite) Test_SaveTicketDelivery_ShouldSuccess() {
+	query := `
+		INSERT INTO ticket_deliveries (job_id, participant_id, status, error,
+		message_id, attempts, attempted_at, updated_at) VALUES ($1, $2, $3, $4, $5, 1, $6, $7)
+		ON CONFLICT (job_id, participant_id) DO UPDATE SET status = EXCLUDED.status,
+		error = EXCLUDED.error, message_id = EXCLUDED.message_id,
+		attempts = ticket_deliveries.attempts + 1,
+		attempted_at = EXCLUDED.attempted_at, updated_at = EXCLUDED.updated_at
+		RETURNING id, attempts;`
+	s.T().Run("JOB DELIVERY", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(7, 1, "sent", nil, "<a1b2@tix.id>", 2, 2).
+			WillReturnRows(s.mock.NewRows([]string{"id", "attempts"}).AddRow(3, 2))
+		delivery := &entity.TicketDelivery{
+			JobID:         sql.NullInt32{Int32: 7, Valid: true},
+			ParticipantID: 1,
+			Status:        "sent",
+			MessageID:     sql.NullString{String: "<a1b2@tix.id>", Valid: true},
+			AttemptedAt:   sql.NullInt32{Int32: 2, Valid: true},
+		}
+		err := s.repo.SaveTicketDelivery(context.TODO(), delivery, 2)
+		s.Nil(err)
+		s.Equal(int32(3), delivery.ID)
+		// the retried job count the earlier attempt
+		s.Equal(int32(2), delivery.Attempts)
+	})
+	s.T().Run("SINGLE DELIVERY", func(t *testing.T) {
+		s.mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(nil, 1, "failed", "lorem", nil, 2, 2).
+			WillReturnRows(s.mock.NewRows([]string{"id", "attempts"}).AddRow(4, 1))
+		err := s.repo.SaveTicketDelivery(context.TODO(), &entity.TicketDelivery{
+			ParticipantID: 1,
+			Status:        "failed",
+			Error:         sql.NullString{String: "lorem", Valid: true},
+			AttemptedAt:   sql.NullInt32{Int32: 2, Valid: true},
+		}, 2)
+		s.Nil(err)
+	})
+}
+func (s *tixSQLRepositoryTestSuite) Test_SaveTicketDelivery_ShouldError() {
+	s.mock.ExpectQuery(`.*INSERT INTO ticket_deliveries.*`).WillReturnError(errors.New("lorem"))
+	err := s.repo.SaveTicketDelivery(context.TODO(), &entity.TicketDelivery{ParticipantID: 1}, 2)
+	s.NotNil(err)
+}
+
 func TestTixSQLRepository(t *testing.T) {
 	suite.Run(t, new(tixSQLRepositoryTestSuite))
 }
@@ -0,0 +1,106 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+)
+
+// InsertTicketDeliveries queue the ticket of every participant for the job,
+// a participant already queued for the job (e.g. a retried job) is skipped.
+func (repository *tixPostgreSQLRepository) InsertTicketDeliveries(
+	ctx context.Context,
+	jobID int32,
+	participantIDs []int32,
+	createdAt int64,
+) (err error) {
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO ticket_deliveries (job_id, participant_id, status, updated_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT (job_id, participant_id) DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, participantID := range participantIDs {
+		if _, err = stmt.ExecContext(
+			ctx, jobID, participantID,
+			string(common.TicketDeliveryQueued), createdAt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repository *tixPostgreSQLRepository) GetTicketDeliveriesByJobID(
+	ctx context.Context,
+	jobID int32,
+) (
+	deliveries []*entity.TicketDelivery,
+	err error,
+) {
+	query := `
+	SELECT ticket_deliveries.id, ticket_deliveries.job_id, ticket_deliveries.participant_id,
+	participants.name, participants.email, ticket_deliveries.status,
+	ticket_deliveries.error, ticket_deliveries.message_id, ticket_deliveries.attempts,
+	ticket_deliveries.attempted_at, ticket_deliveries.updated_at
+	FROM ticket_deliveries
+	JOIN participants ON participants.id = ticket_deliveries.participant_id
+	WHERE ticket_deliveries.job_id = $1 ORDER BY ticket_deliveries.id ASC
+	`
+	rows, err := repository.db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var delivery entity.TicketDelivery
+		if err := rows.Scan(
+			&delivery.ID, &delivery.JobID, &delivery.ParticipantID,
+			&delivery.ParticipantName, &delivery.ParticipantEmail,
+			&delivery.Status, &delivery.Error, &delivery.MessageID,
+			&delivery.Attempts, &delivery.AttemptedAt, &delivery.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
+
+// SaveTicketDelivery record the attempt to send the ticket, the delivery
+// queued by the job for the participant is updated with the attempt. Only
+// the last attempt of the job is kept, the earlier ones are counted in the
+// attempts. The ticket sent without a job is recorded as its own delivery.
+func (repository *tixPostgreSQLRepository) SaveTicketDelivery(
+	ctx context.Context,
+	delivery *entity.TicketDelivery,
+	updatedAt int64,
+) error {
+	query := `
+		INSERT INTO ticket_deliveries (job_id, participant_id, status, error,
+		message_id, attempts, attempted_at, updated_at) VALUES ($1, $2, $3, $4, $5, 1, $6, $7)
+		ON CONFLICT (job_id, participant_id) DO UPDATE SET status = EXCLUDED.status,
+		error = EXCLUDED.error, message_id = EXCLUDED.message_id,
+		attempts = ticket_deliveries.attempts + 1,
+		attempted_at = EXCLUDED.attempted_at, updated_at = EXCLUDED.updated_at
+		RETURNING id, attempts;
+	`
+	row := repository.db.QueryRowContext(
+		ctx, query, delivery.JobID, delivery.ParticipantID, delivery.Status,
+		delivery.Error, delivery.MessageID, delivery.AttemptedAt, updatedAt)
+	return row.Scan(&delivery.ID, &delivery.Attempts)
+}
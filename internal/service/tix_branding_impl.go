@@ -0,0 +1,303 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"github.com/aasumitro/tix/pkg/i18n"
+	"github.com/aasumitro/tix/pkg/mailer"
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"image"
+	_ "image/jpeg" // register the jpeg logo decoder
+	_ "image/png"  // register the png logo decoder
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+func (service *tixService) FetchEventBranding(
+	ctx context.Context,
+	googleFormID string,
+) (item *response.EventBrandingResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBrandingResponse(googleFormID, event.Branding), nil
+}
+
+func (service *tixService) UpdateEventBranding(
+	ctx context.Context,
+	googleFormID string,
+	form *request.EventRequestBranding,
+) (item *response.EventBrandingResponse, err error) {
+	primaryColor, err := normalizeHexColor(form.PrimaryColor)
+	if err != nil {
+		return nil, err
+	}
+	secondaryColor, err := normalizeHexColor(form.SecondaryColor)
+	if err != nil {
+		return nil, err
+	}
+
+	branding := entity.Branding{
+		LogoURL:        strings.TrimSpace(form.LogoURL),
+		PrimaryColor:   primaryColor,
+		SecondaryColor: secondaryColor,
+		HeaderTitle:    strings.TrimSpace(form.HeaderTitle),
+		HeaderSubtitle: strings.TrimSpace(form.HeaderSubtitle),
+		FooterText:     strings.TrimSpace(form.FooterText),
+		SenderName:     strings.TrimSpace(form.SenderName),
+		SenderEmail:    strings.TrimSpace(form.SenderEmail),
+		WebsiteURL:     strings.TrimSpace(form.WebsiteURL),
+		Locale:         strings.ToLower(strings.TrimSpace(form.Locale)),
+	}
+	if err := service.postgreSQLRepository.UpdateEventBranding(
+		ctx, googleFormID, branding,
+	); err != nil {
+		return nil, err
+	}
+
+	return newBrandingResponse(googleFormID, branding), nil
+}
+
+func newBrandingResponse(
+	googleFormID string,
+	branding entity.Branding,
+) *response.EventBrandingResponse {
+	applied := eventBranding(branding)
+	return &response.EventBrandingResponse{
+		GoogleFormID:   googleFormID,
+		IsDefault:      branding == (entity.Branding{}),
+		LogoURL:        applied.LogoURL,
+		PrimaryColor:   applied.PrimaryColor,
+		SecondaryColor: applied.SecondaryColor,
+		HeaderTitle:    applied.HeaderTitle,
+		HeaderSubtitle: applied.HeaderSubtitle,
+		FooterText:     applied.FooterText,
+		SenderName:     applied.SenderName,
+		SenderEmail:    applied.SenderEmail,
+		WebsiteURL:     applied.WebsiteURL,
+		Locale:         applied.Locale,
+	}
+}
+
+// eventBranding fill the field the event does not customise
+// with the default tix branding.
+func eventBranding(branding entity.Branding) entity.Branding {
+	withDefault := func(value, defaultValue string) string {
+		if value == "" {
+			return defaultValue
+		}
+		return value
+	}
+	return entity.Branding{
+		LogoURL:        withDefault(branding.LogoURL, common.DefaultBrandingLogoURL),
+		PrimaryColor:   withDefault(branding.PrimaryColor, common.DefaultBrandingPrimaryColor),
+		SecondaryColor: withDefault(branding.SecondaryColor, common.DefaultBrandingSecondaryColor),
+		HeaderTitle:    withDefault(branding.HeaderTitle, common.DefaultBrandingHeaderTitle),
+		HeaderSubtitle: withDefault(branding.HeaderSubtitle, common.DefaultBrandingHeaderSubtitle),
+		FooterText:     withDefault(branding.FooterText, common.DefaultBrandingFooterText),
+		SenderName:     withDefault(branding.SenderName, common.DefaultBrandingSenderName),
+		SenderEmail:    withDefault(branding.SenderEmail, common.DefaultBrandingSenderEmail),
+		WebsiteURL:     withDefault(branding.WebsiteURL, common.DefaultBrandingWebsiteURL),
+		Locale:         string(i18n.Of(branding.Locale)),
+	}
+}
+
+// brandingProduct is the product shown on the header and the footer of the emails.
+func brandingProduct(branding entity.Branding) mailer.Product {
+	return mailer.Product{
+		Name:        branding.HeaderTitle,
+		Link:        branding.WebsiteURL,
+		Logo:        branding.LogoURL,
+		TroubleText: i18n.Of(branding.Locale).T("email.trouble"),
+	}
+}
+
+// brandingEmailBody is the email body greeting the recipient
+// in the locale of the branding.
+func brandingEmailBody(branding entity.Branding, name string) mailer.Body {
+	locale := i18n.Of(branding.Locale)
+	return mailer.Body{
+		Name:      name,
+		Greeting:  locale.T("email.greeting"),
+		Signature: locale.T("email.signature"),
+	}
+}
+
+// normalizeHexColor return the color as #RRGGBB, the short #RGB form is expanded.
+func normalizeHexColor(value string) (string, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if digits == "" {
+		return "", nil
+	}
+	if len(digits) == len("RGB") {
+		var expanded strings.Builder
+		for _, digit := range digits {
+			expanded.WriteString(string(digit) + string(digit))
+		}
+		digits = expanded.String()
+	}
+	if rgb, err := hex.DecodeString(digits); err != nil || len(rgb) != len("RGB") {
+		return "", common.ErrInvalidBrandingColor
+	}
+	return "#" + strings.ToUpper(digits), nil
+}
+
+// brandingColor convert the #RRGGBB color into the pdf color,
+// an invalid color is drawn black.
+func brandingColor(value string) color.Color {
+	rgb, err := hex.DecodeString(strings.TrimPrefix(value, "#"))
+	if err != nil || len(rgb) != len("RGB") {
+		return color.NewBlack()
+	}
+	return color.Color{Red: int(rgb[0]), Green: int(rgb[1]), Blue: int(rgb[2])}
+}
+
+// brandingPDFHeader draw the header title and subtitle of the pdf files,
+// the logo is drawn on the left of them.
+func brandingPDFHeader(m pdf.Maroto, branding entity.Branding, logo *brandingLogo) {
+	m.Row(common.PdfHeaderRowHeight, func() {
+		titleColWidth := uint(common.PdfHeaderColWidth)
+		if logo != nil {
+			m.Col(common.PdfHeaderLogoColWidth, func() {
+				if err := m.Base64Image(
+					base64.StdEncoding.EncodeToString(logo.data), logo.extension,
+					props.Rect{Percent: common.PdfHeaderLogoPercent, Center: true},
+				); err != nil {
+					fmt.Println("⚠️ could not draw branding logo:", err)
+				}
+			})
+			titleColWidth = common.PdfHeaderLogoTitleColWidth
+		}
+		m.Col(titleColWidth, func() {
+			m.Text(branding.HeaderTitle, props.Text{
+				Size:  common.PdfTitleSize,
+				Style: consts.Bold,
+				Align: consts.Center,
+				Color: brandingColor(branding.PrimaryColor),
+			})
+			m.Text(branding.HeaderSubtitle, props.Text{
+				Top:   common.PdfSubtitleMarginTop,
+				Size:  common.PdfSubtitleSize,
+				Style: consts.Italic,
+				Align: consts.Center,
+				Color: brandingColor(branding.PrimaryColor),
+			})
+		})
+		if logo != nil {
+			// keep the title in the middle of the page
+			m.ColSpace(common.PdfHeaderLogoColWidth)
+		}
+	})
+}
+
+type brandingLogo struct {
+	data      []byte
+	extension consts.Extension
+	height    int
+}
+
+type cachedBrandingLogo struct {
+	logo      *brandingLogo
+	expiredAt time.Time
+}
+
+// brandingLogoCache keep the logo by its url, see common.BrandingLogoCacheTTL.
+var brandingLogoCache = struct {
+	sync.Mutex
+	logos map[string]*cachedBrandingLogo
+}{logos: make(map[string]*cachedBrandingLogo)}
+
+// fetchBrandingLogo return the logo customised by the event to embed it
+// into the pdf and the excel files. The default logo is only used by the
+// emails, so nothing is downloaded for the event that does not customise it.
+// The logo is downloaded once per common.BrandingLogoCacheTTL, the logo that
+// can not be used is remembered too so a broken url is not fetched again.
+func fetchBrandingLogo(branding entity.Branding) *brandingLogo {
+	if branding.LogoURL == "" {
+		return nil
+	}
+
+	now := time.Now()
+	brandingLogoCache.Lock()
+	cached, ok := brandingLogoCache.logos[branding.LogoURL]
+	brandingLogoCache.Unlock()
+	if ok && now.Before(cached.expiredAt) {
+		return cached.logo
+	}
+
+	logo := downloadBrandingLogo(branding.LogoURL)
+	brandingLogoCache.Lock()
+	for logoURL, cached := range brandingLogoCache.logos {
+		if !now.Before(cached.expiredAt) {
+			delete(brandingLogoCache.logos, logoURL)
+		}
+	}
+	brandingLogoCache.logos[branding.LogoURL] = &cachedBrandingLogo{
+		logo:      logo,
+		expiredAt: now.Add(common.BrandingLogoCacheTTL),
+	}
+	brandingLogoCache.Unlock()
+	return logo
+}
+
+// downloadBrandingLogo download the logo, it is skipped (nil) when it
+// can not be downloaded or is not a png or jpeg.
+func downloadBrandingLogo(logoURL string) *brandingLogo {
+	ctx, cancel := context.WithTimeout(context.Background(), common.BrandingLogoFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logoURL, http.NoBody)
+	if err != nil {
+		fmt.Println("⚠️ could not fetch branding logo:", err)
+		return nil
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Println("⚠️ could not fetch branding logo:", err)
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Println("⚠️ could not fetch branding logo:", resp.Status)
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, common.BrandingLogoMaxSize+1))
+	if err != nil || len(data) > common.BrandingLogoMaxSize {
+		fmt.Println("⚠️ could not fetch branding logo: it is missing or too large")
+		return nil
+	}
+
+	logo := &brandingLogo{data: data}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		logo.extension = consts.Png
+	case "image/jpeg":
+		logo.extension = consts.Jpg
+	default:
+		fmt.Println("⚠️ could not use branding logo: only png and jpeg are supported")
+		return nil
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Height == 0 {
+		fmt.Println("⚠️ could not use branding logo: the image is not valid")
+		return nil
+	}
+	logo.height = config.Height
+
+	return logo
+}
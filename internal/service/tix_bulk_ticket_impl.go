@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+func (service *tixService) PublishGenerateEventTicketsQueue(
+	ctx context.Context,
+	googleFormID, filter string,
+) (jobID int32, err error) {
+	cacheKey := fmt.Sprintf("%s-%s",
+		common.ReqGenEventBulkTixQueueKey, googleFormID)
+	cache, err := service.redisCache.Get(ctx, cacheKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	if cache != "" {
+		return 0, common.ErrRateLimitingPushQueue
+	}
+
+	if filter == "" {
+		filter = string(common.TicketRecipientApproved)
+	}
+	payload := map[string]any{
+		"google_form_id": googleFormID,
+		"filter":         filter,
+	}
+
+	if jobID, err = service.publishJob(
+		ctx, common.ReqGenEventBulkTixQueueKey, googleFormID, payload,
+	); err != nil {
+		return 0, err
+	}
+
+	return jobID, service.redisCache.Set(
+		ctx, cacheKey, jobID, time.Minute*1,
+	).Err()
+}
+
+// GenerateEventTickets send the ticket to every participant chosen by the filter,
+// the progress of each participant is tracked under the job. The participants
+// are chosen once, so a retried job only send the tickets that are not sent yet.
+func (service *tixService) GenerateEventTickets(
+	ctx context.Context,
+	googleFormID string,
+	jobID int32,
+	filter string,
+) (item *response.TicketDeliveryReportResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := service.postgreSQLRepository.GetTicketDeliveriesByJobID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		participantIDs, err := service.postgreSQLRepository.GetTicketRecipientIDs(
+			ctx, event.ID, filter == string(common.TicketRecipientUnsent))
+		if err != nil {
+			return nil, err
+		}
+		if len(participantIDs) > 0 {
+			if err := service.postgreSQLRepository.InsertTicketDeliveries(
+				ctx, jobID, participantIDs, time.Now().Unix(),
+			); err != nil {
+				return nil, err
+			}
+			if deliveries, err = service.postgreSQLRepository.
+				GetTicketDeliveriesByJobID(ctx, jobID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Status == string(common.TicketDeliverySent) {
+			continue
+		}
+		service.deliverTicket(ctx, event, delivery)
+	}
+
+	item = newTicketDeliveryReport(jobID, deliveries)
+	if item.Failed > 0 {
+		return item, fmt.Errorf("%w: %s", common.ErrTicketDeliveryFailed, ticketDeliverySummary(item))
+	}
+
+	return item, nil
+}
+
+// deliverTicket send the ticket of a single participant and keep the result
+// for the job report, a failed ticket does not stop the other participants
+// from receiving theirs.
+func (service *tixService) deliverTicket(
+	ctx context.Context,
+	event *entity.Event,
+	delivery *entity.TicketDelivery,
+) {
+	service.mu.Lock()
+	err := service.sendParticipantTicket(ctx, event, delivery.ParticipantID, delivery.JobID.Int32)
+	service.mu.Unlock()
+
+	now := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	delivery.Status = string(common.TicketDeliverySent)
+	delivery.Error = sql.NullString{}
+	delivery.AttemptedAt = now
+	delivery.UpdatedAt = now
+	if err != nil {
+		delivery.Status = string(common.TicketDeliveryFailed)
+		delivery.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+}
+
+func (service *tixService) FetchTicketDeliveries(
+	ctx context.Context,
+	googleFormID string,
+	jobID int32,
+) (item *response.TicketDeliveryReportResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := service.postgreSQLRepository.GetJobByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrJobNotFound
+		}
+		return nil, err
+	}
+	if job.EventID != event.ID || job.Queue != common.ReqGenEventBulkTixQueueKey {
+		return nil, common.ErrJobNotFound
+	}
+
+	deliveries, err := service.postgreSQLRepository.GetTicketDeliveriesByJobID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTicketDeliveryReport(jobID, deliveries), nil
+}
+
+// ticketDeliverySummary is the result of the bulk ticket job,
+// e.g. tickets sent to 8 of 10 participants, 2 failed.
+func ticketDeliverySummary(report *response.TicketDeliveryReportResponse) string {
+	summary := fmt.Sprintf("tickets sent to %d of %d participants", report.Sent, report.Total)
+	if report.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", report.Failed)
+	}
+	return summary
+}
+
+func newTicketDeliveryReport(
+	jobID int32,
+	deliveries []*entity.TicketDelivery,
+) *response.TicketDeliveryReportResponse {
+	report := &response.TicketDeliveryReportResponse{
+		JobID:      jobID,
+		Total:      len(deliveries),
+		Deliveries: []*response.TicketDeliveryResponse{},
+	}
+	for _, delivery := range deliveries {
+		switch common.TicketDeliveryStatus(delivery.Status) {
+		case common.TicketDeliverySent:
+			report.Sent++
+		case common.TicketDeliveryFailed:
+			report.Failed++
+		case common.TicketDeliveryQueued:
+			report.Pending++
+		}
+		report.Deliveries = append(report.Deliveries, newTicketDeliveryResponse(delivery))
+	}
+	return report
+}
+
+func newTicketDeliveryResponse(delivery *entity.TicketDelivery) *response.TicketDeliveryResponse {
+	return &response.TicketDeliveryResponse{
+		ParticipantID:    delivery.ParticipantID,
+		ParticipantName:  delivery.ParticipantName,
+		ParticipantEmail: delivery.ParticipantEmail,
+		Status:           delivery.Status,
+		Error:            delivery.Error.String,
+		MessageID:        delivery.MessageID.String,
+		Attempts:         delivery.Attempts,
+		AttemptedAt:      delivery.AttemptedAt.Int32,
+		UpdatedAt:        delivery.UpdatedAt.Int32,
+	}
+}
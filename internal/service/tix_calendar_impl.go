@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/pkg/calendar"
+	"github.com/aasumitro/tix/pkg/i18n"
+	"os"
+	"time"
+)
+
+// FetchEventCalendar return the event as an ics calendar,
+// so it can be added to the calendar apps directly.
+func (service *tixService) FetchEventCalendar(
+	ctx context.Context,
+	googleFormID string,
+) ([]byte, error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventCalendar(event, "").ICS(), nil
+}
+
+// generateCalendarTicket save the calendar event of the ticket,
+// it is attached to the ticket email along with the pdf ticket.
+func (service *tixService) generateCalendarTicket(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode string,
+) error {
+	description := i18n.Of(event.Branding.Locale).T("calendar.description", participant.Name, ticketCode)
+	attachment := fmt.Sprintf("./%s/%s", common.ExportTempDir,
+		calendarAttachmentName(event.ID, participant.ID))
+	service.saveTempFile(attachment)
+	if err := os.WriteFile(attachment, eventCalendar(event, description).ICS(),
+		common.ExportFileMode); err != nil {
+		return fmt.Errorf("⚠️ could not save calendar event: %s", err.Error())
+	}
+
+	return nil
+}
+
+// eventCalendar return the all-day calendar event, the date is read
+// in the same location as the date printed on the pdf ticket. The
+// organizer is the sender of the ticket email.
+func eventCalendar(event *entity.Event, description string) *calendar.Event {
+	branding := eventBranding(event.Branding)
+	return &calendar.Event{
+		UID:            fmt.Sprintf("event-%d@%s", event.ID, common.CalendarUIDDomain),
+		Summary:        event.Name,
+		Description:    description,
+		Location:       event.Location,
+		Date:           time.Unix(int64(event.EventDate), 0),
+		OrganizerName:  branding.SenderName,
+		OrganizerEmail: branding.SenderEmail,
+	}
+}
+
+func calendarAttachmentName(eventID, participantID int32) string {
+	return fmt.Sprintf("gen%d%dtix.ics", eventID, participantID)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CheckInTicket redeem the scanned ticket (the ticket code or the ticket token
+// from the QR code) at the event gate, a ticket can only be checked in once
+// by an approved participant.
+func (service *tixService) CheckInTicket(
+	ctx context.Context,
+	googleFormID, ticketCode, checkedInBy string,
+) (
+	item *response.CheckInResponse,
+	err error,
+) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := service.scannedParticipant(ctx, event, strings.TrimSpace(ticketCode))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkInRejection(event, participant); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if err := service.postgreSQLRepository.CheckInParticipant(
+		ctx, participant.ID, now, checkedInBy,
+	); err != nil {
+		// checked in by another gate right after the ticket is read
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketAlreadyCheckedIn
+		}
+		return nil, err
+	}
+
+	service.checkedIn(ctx, googleFormID, participant.ID, now)
+
+	return &response.CheckInResponse{
+		ParticipantID: participant.ID,
+		Name:          participant.Name,
+		Email:         participant.Email,
+		TicketCode:    participant.TicketCode.String,
+		CheckedInAt:   int32(now),
+		CheckedInBy:   checkedInBy,
+	}, nil
+}
+
+// ReconcileCheckIns record the check-ins scanned by an offline gate, the
+// earliest scan of a ticket is kept and the following ones (in the batch
+// or already recorded by another gate) are reported as duplicate.
+func (service *tixService) ReconcileCheckIns(
+	ctx context.Context,
+	googleFormID string,
+	checkIns []*request.EventRequestOfflineCheckIn,
+	checkedInBy string,
+) (
+	item *response.CheckInBatchResponse,
+	err error,
+) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(checkIns))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return checkIns[order[i]].CheckedInAt < checkIns[order[j]].CheckedInAt
+	})
+
+	now := time.Now()
+	item = &response.CheckInBatchResponse{
+		Total: len(checkIns),
+		Items: make([]*response.OfflineCheckInResponse, len(checkIns)),
+	}
+	recorded := make(map[int32]int32)
+	for _, idx := range order {
+		scannedAt := time.Unix(checkIns[idx].CheckedInAt, 0)
+		if scannedAt.After(now) {
+			scannedAt = now
+		}
+		result := &response.OfflineCheckInResponse{
+			Index:       idx,
+			CheckedInAt: int32(scannedAt.Unix()),
+		}
+		item.Items[idx] = result
+
+		participant, err := service.offlineScannedParticipant(
+			ctx, event, checkIns[idx].Token, scannedAt, result, recorded)
+		if err != nil {
+			return nil, err
+		}
+		if participant == nil {
+			continue
+		}
+
+		if err := service.postgreSQLRepository.CheckInParticipant(
+			ctx, participant.ID, scannedAt.Unix(), checkedInBy,
+		); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			result.Status = string(common.CheckInDuplicate)
+			continue
+		}
+		result.Status = string(common.CheckInRecorded)
+		recorded[participant.ID] = result.CheckedInAt
+		service.checkedIn(ctx, googleFormID, participant.ID, scannedAt.Unix())
+	}
+
+	for _, result := range item.Items {
+		switch common.CheckInStatus(result.Status) {
+		case common.CheckInRecorded:
+			item.CheckedIn++
+		case common.CheckInDuplicate:
+			item.Duplicates++
+		case common.CheckInRejected:
+			item.Rejected++
+		}
+	}
+
+	return item, nil
+}
+
+// offlineScannedParticipant return the participant to be checked in,
+// or nil when the scan is rejected or duplicate (reported in the result).
+func (service *tixService) offlineScannedParticipant(
+	ctx context.Context,
+	event *entity.Event,
+	ticketToken string,
+	scannedAt time.Time,
+	result *response.OfflineCheckInResponse,
+	recorded map[int32]int32,
+) (*entity.Participant, error) {
+	claim, err := service.verifyTicketToken(event, ticketToken, scannedAt)
+	if err != nil {
+		return nil, rejectCheckIn(result, err)
+	}
+	result.ParticipantID = claim.ParticipantID
+
+	if checkedInAt, ok := recorded[claim.ParticipantID]; ok {
+		result.Status = string(common.CheckInDuplicate)
+		result.CheckedInAt = checkedInAt
+		return nil, nil
+	}
+
+	participant, err := service.ticketHolder(ctx, event, claim.ParticipantID)
+	if err != nil {
+		return nil, rejectCheckIn(result, err)
+	}
+
+	err = checkInRejection(event, participant)
+	if errors.Is(err, common.ErrTicketAlreadyCheckedIn) {
+		result.Status = string(common.CheckInDuplicate)
+		result.CheckedInAt = participant.CheckedInAt.Int32
+		recorded[participant.ID] = participant.CheckedInAt.Int32
+		return nil, nil
+	}
+	if err != nil {
+		return nil, rejectCheckIn(result, err)
+	}
+
+	return participant, nil
+}
+
+// rejectCheckIn report the check-in error in the result, any other error is returned.
+func rejectCheckIn(result *response.OfflineCheckInResponse, err error) error {
+	var checkInErr *common.CheckInError
+	if !errors.As(err, &checkInErr) {
+		return err
+	}
+	result.Status = string(common.CheckInRejected)
+	result.Code = checkInErr.Code
+	return nil
+}
+
+func (service *tixService) scannedParticipant(
+	ctx context.Context,
+	event *entity.Event,
+	scanned string,
+) (*entity.Participant, error) {
+	if isTicketToken(scanned) {
+		claim, err := service.verifyTicketToken(event, scanned, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return service.ticketHolder(ctx, event, claim.ParticipantID)
+	}
+
+	participant, err := service.postgreSQLRepository.GetParticipantByTicketCode(
+		ctx, strings.ToUpper(scanned))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketNotFound
+		}
+		return nil, err
+	}
+	return participant, nil
+}
+
+// checkInRejection tell why the participant ticket can not be checked in.
+func checkInRejection(event *entity.Event, participant *entity.Participant) error {
+	switch {
+	case participant.EventID != event.ID:
+		return common.ErrTicketWrongEvent
+	case participant.DeclinedAt.Valid:
+		return common.ErrTicketDeclined
+	case !participant.ApprovedAt.Valid:
+		return common.ErrTicketNotApproved
+	case participant.CheckedInAt.Valid:
+		return common.ErrTicketAlreadyCheckedIn
+	}
+	return nil
+}
+
+func (service *tixService) checkedIn(
+	ctx context.Context,
+	googleFormID string,
+	participantID int32,
+	checkedInAt int64,
+) {
+	service.redisCache.Del(ctx,
+		fmt.Sprintf("overview-%s", googleFormID),
+		fmt.Sprintf("participants-%s", googleFormID))
+
+	service.notify(ctx, googleFormID, common.NotificationParticipantCheckedIn, map[string]any{
+		"participant_id": participantID,
+		"checked_in_at":  checkedInAt,
+	})
+}
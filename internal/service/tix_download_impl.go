@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/pkg/token"
+	"net/url"
+	"time"
+)
+
+// DownloadTicket draw the ticket of the participant, so the admin can
+// hand it over without sending the ticket email.
+func (service *tixService) DownloadTicket(
+	ctx context.Context,
+	googleFormID string,
+	participantID int32,
+) ([]byte, error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := service.ticketHolder(ctx, event, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.drawParticipantTicket(ctx, event, participant)
+}
+
+// DownloadTicketByToken draw the ticket of the signed download link
+// sent to the participant along with the ticket.
+func (service *tixService) DownloadTicketByToken(
+	ctx context.Context,
+	googleFormID, downloadToken string,
+) ([]byte, error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketNotFound
+		}
+		return nil, err
+	}
+
+	key, err := service.ticketKey(event.GoogleFormID)
+	if err != nil {
+		return nil, err
+	}
+	claim, err := token.VerifyTicketDownload(
+		key.Public().(ed25519.PublicKey), downloadToken, time.Now())
+	switch {
+	case errors.Is(err, token.ErrExpiredTicket):
+		return nil, common.ErrTicketExpired
+	case err != nil, claim.EventID != event.ID:
+		return nil, common.ErrTicketInvalid
+	}
+
+	participant, err := service.ticketHolder(ctx, event, claim.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.drawParticipantTicket(ctx, event, participant)
+}
+
+// drawParticipantTicket draw the same pdf ticket as the one sent by email,
+// it is kept in memory instead of the export directory.
+func (service *tixService) drawParticipantTicket(
+	ctx context.Context,
+	event *entity.Event,
+	participant *entity.Participant,
+) ([]byte, error) {
+	if !participant.ApprovedAt.Valid || participant.DeclinedAt.Valid {
+		return nil, common.ErrParticipantNotApproved
+	}
+
+	ticketCode, err := service.participantTicketCode(ctx, participant)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := service.ticketQRCode(event, participant.ID, ticketCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return drawPDFTicket(event, participant, ticketCode, qrCode, event.TicketTemplate.String)
+}
+
+// ticketDownloadLink return the signed link to download the ticket, it expires
+// with the ticket token. There is no link when the public url or the ticket
+// signing is not configured.
+func (service *tixService) ticketDownloadLink(event *entity.Event, participantID int32) string {
+	if service.publicURL == "" {
+		return ""
+	}
+	key, err := service.ticketKey(event.GoogleFormID)
+	if err != nil {
+		return ""
+	}
+	downloadToken, err := token.SignTicketDownload(
+		key, event.ID, participantID, ticketTokenExpiry(event))
+	if err != nil {
+		fmt.Println("⚠️ could not sign ticket download link:", err)
+		return ""
+	}
+	return fmt.Sprintf("%s/api/v1/tickets/%s/ticket.pdf?token=%s", service.publicURL,
+		url.PathEscape(event.GoogleFormID), url.QueryEscape(downloadToken))
+}
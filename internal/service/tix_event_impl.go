@@ -11,6 +11,7 @@ import (
 	"github.com/aasumitro/tix/internal/domain/response"
 	"github.com/aasumitro/tix/pkg/dt"
 	"github.com/redis/go-redis/v9"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -171,13 +172,14 @@ func (service *tixService) FetchOverview(
 							}
 							return ""
 						}(),
+						CustomAnswers: newParticipantCustomAnswers(participant.CustomAnswers),
 					})
 				}(participant)
 			}
 			data.LatestRespondents = respondentsToday
 		}()
 
-		wg.Add(3)
+		wg.Add(4)
 		go func() {
 			defer wg.Done()
 			data.TotalApprovedParticipant = service.postgreSQLRepository.CountParticipants(
@@ -193,6 +195,11 @@ func (service *tixService) FetchOverview(
 			data.TotalDeclinedParticipant = service.postgreSQLRepository.CountParticipants(
 				ctx, event.ID, common.ParticipantRequestDeclined, 0, 0)
 		}()
+		go func() {
+			defer wg.Done()
+			data.TotalCheckedInParticipant = service.postgreSQLRepository.CountParticipants(
+				ctx, event.ID, common.ParticipantCheckedIn, 0, 0)
+		}()
 
 		var weeklyOverview []*response.WeeklyOverviewResponse
 		for _, week := range dt.WeekDayStartToEnd(now) {
@@ -300,9 +307,12 @@ func (service *tixService) updateAutoSyncEvent(
 	}
 }
 
+// FetchParticipants return the participants of the event, the filter
+// narrow them down, e.g. the approved participant whose ticket failed.
 func (service *tixService) FetchParticipants(
 	ctx context.Context,
 	googleFormID string,
+	filter *request.EventRequestParticipantFilter,
 ) (
 	items []*response.ParticipantResponse,
 	err error,
@@ -365,6 +375,32 @@ func (service *tixService) FetchParticipants(
 					}
 					return "waiting approval"
 				}(),
+				TicketCode: func() string {
+					if participant.TicketCode.Valid {
+						return participant.TicketCode.String
+					}
+					return ""
+				}(),
+				CheckedInAt: func() *int32 {
+					if participant.CheckedInAt.Valid {
+						return &participant.CheckedInAt.Int32
+					}
+					return nil
+				}(),
+				CheckedInBy: func() string {
+					if participant.CheckedInBy.Valid {
+						return participant.CheckedInBy.String
+					}
+					return ""
+				}(),
+				TicketSentAt: func() *int32 {
+					if participant.TicketSentAt.Valid {
+						return &participant.TicketSentAt.Int32
+					}
+					return nil
+				}(),
+				TicketDelivery: newParticipantTicketDelivery(participant.LastTicketDelivery),
+				CustomAnswers:  newParticipantCustomAnswers(participant.CustomAnswers),
 			})
 		}
 
@@ -391,40 +427,124 @@ func (service *tixService) FetchParticipants(
 		items = participants
 	}
 
+	return filterParticipants(items, filter), nil
+}
+
+// filterParticipants keep the approved participants whose latest ticket
+// delivery has the given status, all participants are kept without filter.
+func filterParticipants(
+	participants []*response.ParticipantResponse,
+	filter *request.EventRequestParticipantFilter,
+) []*response.ParticipantResponse {
+	if filter == nil || filter.TicketDelivery == "" {
+		return participants
+	}
+
+	var items []*response.ParticipantResponse
+	for _, participant := range participants {
+		if participant.ApprovedAt == nil || participant.TicketDelivery == nil {
+			continue
+		}
+		if participant.TicketDelivery.Status == filter.TicketDelivery {
+			items = append(items, participant)
+		}
+	}
+	return items
+}
+
+func newParticipantTicketDelivery(
+	delivery entity.LastTicketDelivery,
+) *response.ParticipantTicketDelivery {
+	if !delivery.Status.Valid {
+		return nil
+	}
+	return &response.ParticipantTicketDelivery{
+		Status:      delivery.Status.String,
+		Error:       delivery.Error.String,
+		MessageID:   delivery.MessageID.String,
+		AttemptedAt: delivery.AttemptedAt.Int32,
+	}
+}
+
+func (service *tixService) FetchParticipantChanges(
+	ctx context.Context,
+	googleFormID string,
+	participantID int32,
+) (
+	items []*response.ParticipantChangeResponse,
+	err error,
+) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := service.postgreSQLRepository.GetParticipantChanges(
+		ctx, event.ID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		items = append(items, &response.ParticipantChangeResponse{
+			ID:            change.ID,
+			ParticipantID: change.ParticipantID,
+			Field:         change.Field,
+			OldValue:      change.OldValue,
+			NewValue:      change.NewValue,
+			CreatedAt:     change.CreatedAt.Int32,
+		})
+	}
+
 	return items, nil
 }
 
+func newParticipantCustomAnswers(
+	answers entity.CustomAnswers,
+) []*response.ParticipantCustomAnswer {
+	var items []*response.ParticipantCustomAnswer
+	for questionID, answer := range answers {
+		items = append(items, &response.ParticipantCustomAnswer{
+			QuestionID: questionID,
+			Question:   answer.Question,
+			Answer:     answer.Answer,
+			Position:   answer.Position,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+	return items
+}
+
 func (service *tixService) PublishSyncEventDataQueue(
 	ctx context.Context,
 	googleFormID string,
-) error {
+) (jobID int32, err error) {
 	cacheKey := fmt.Sprintf("%s-%s",
 		common.ReqSyncEventQueueKey, googleFormID)
 
 	cache, err := service.redisCache.Get(ctx, cacheKey).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
-		return err
+		return 0, err
 	}
 
 	if cache != "" {
-		return common.ErrRateLimitingPushQueue
+		return 0, common.ErrRateLimitingPushQueue
 	}
 
-	payload, err := json.Marshal(map[string]string{
+	payload := map[string]any{
 		"google_form_id": googleFormID,
-	})
-	if err != nil {
-		return err
 	}
 
-	if err := service.redisCache.Publish(
-		ctx, common.ReqSyncEventQueueKey, payload,
-	).Err(); err != nil {
-		return err
+	if jobID, err = service.publishJob(
+		ctx, common.ReqSyncEventQueueKey, googleFormID, payload,
+	); err != nil {
+		return 0, err
 	}
 
-	return service.redisCache.Set(
-		ctx, cacheKey, payload, time.Minute*1,
+	return jobID, service.redisCache.Set(
+		ctx, cacheKey, jobID, time.Minute*1,
 	).Err()
 }
 
@@ -463,48 +583,60 @@ func (service *tixService) UpdateParticipantStatus(
 	cacheKey := fmt.Sprintf("participants-%s", googleFormID)
 	service.redisCache.Del(ctx, cacheKey)
 
+	service.notify(ctx, googleFormID, common.NotificationParticipantStatusUpdated, map[string]any{
+		"participant_id": participantID,
+		"status":         strings.ToLower(form.Status),
+	})
+
 	if strings.EqualFold(strings.ToLower(form.Status), string(common.ParticipantRequestDeclined)) {
 		return nil
 	}
 
-	return service.PublishGenerateEventTicketQueue(
+	_, err := service.PublishGenerateEventTicketQueue(
 		ctx, googleFormID, participantID,
 	)
+	return err
 }
 
 func (service *tixService) PublishExportEventDataQueue(
 	ctx context.Context,
 	googleFormID, exportType, email string,
-) error {
+	form *request.EventRequestExport,
+) (jobID int32, err error) {
 	cacheKey := fmt.Sprintf("%s-%s-%s",
 		common.ReqExpEventDataQueueKey, googleFormID, email)
 
 	cache, err := service.redisCache.Get(ctx, cacheKey).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
-		return err
+		return 0, err
 	}
 
 	if cache != "" {
-		return common.ErrRateLimitingPushQueue
+		return 0, common.ErrRateLimitingPushQueue
 	}
 
-	payload, err := json.Marshal(map[string]string{
-		"google_form_id": googleFormID,
-		"export_type":    exportType,
-		"email":          email,
-	})
-	if err != nil {
-		return err
+	payload := map[string]any{
+		"google_form_id":  googleFormID,
+		"export_type":     exportType,
+		"email":           email,
+		"bom":             form.BOM,
+		"locale":          form.Locale,
+		"status":          form.Status,
+		"checked_in":      form.CheckedIn,
+		"registered_from": form.RegisteredFrom,
+		"registered_to":   form.RegisteredTo,
+		"search":          form.Search,
+		"columns":         form.Columns,
 	}
 
-	if err := service.redisCache.Publish(
-		ctx, common.ReqExpEventDataQueueKey, payload,
-	).Err(); err != nil {
-		return err
+	if jobID, err = service.publishJob(
+		ctx, common.ReqExpEventDataQueueKey, googleFormID, payload,
+	); err != nil {
+		return 0, err
 	}
 
-	return service.redisCache.Set(
-		ctx, cacheKey, payload, time.Minute*1,
+	return jobID, service.redisCache.Set(
+		ctx, cacheKey, jobID, time.Minute*1,
 	).Err()
 }
 
@@ -512,33 +644,30 @@ func (service *tixService) PublishGenerateEventTicketQueue(
 	ctx context.Context,
 	googleFormID string,
 	participantID int32,
-) error {
+) (jobID int32, err error) {
 	cacheKey := fmt.Sprintf("%s-%s-%d",
 		common.ReqGenEventTixQueueKey, googleFormID, participantID)
 	cache, err := service.redisCache.Get(ctx, cacheKey).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
-		return err
+		return 0, err
 	}
 
 	if cache != "" {
-		return common.ErrRateLimitingPushQueue
+		return 0, common.ErrRateLimitingPushQueue
 	}
 
-	payload, err := json.Marshal(map[string]any{
+	payload := map[string]any{
 		"google_form_id": googleFormID,
 		"participant_id": participantID,
-	})
-	if err != nil {
-		return err
 	}
 
-	if err := service.redisCache.Publish(
-		ctx, common.ReqGenEventTixQueueKey, payload,
-	).Err(); err != nil {
-		return err
+	if jobID, err = service.publishJob(
+		ctx, common.ReqGenEventTixQueueKey, googleFormID, payload,
+	); err != nil {
+		return 0, err
 	}
 
-	return service.redisCache.Set(
-		ctx, cacheKey, payload, time.Minute*1,
+	return jobID, service.redisCache.Set(
+		ctx, cacheKey, jobID, time.Minute*1,
 	).Err()
 }
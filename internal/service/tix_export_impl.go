@@ -2,27 +2,48 @@ package service
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/pkg/i18n"
 	"github.com/aasumitro/tix/pkg/mailer"
 	"github.com/aasumitro/tix/pkg/mailer/template"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/aasumitro/tix/pkg/token"
 	"github.com/johnfercher/maroto/pkg/color"
 	"github.com/johnfercher/maroto/pkg/consts"
 	"github.com/johnfercher/maroto/pkg/pdf"
 	"github.com/johnfercher/maroto/pkg/props"
 	"github.com/xuri/excelize/v2"
 	"gopkg.in/gomail.v2"
+	"io"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 func (service *tixService) ExportEvent(
 	ctx context.Context,
 	googleFormID, exportFileType, targetEmail string,
+	form *request.EventRequestExport,
 ) error {
+	exporter, ok := eventExporters[common.EventExportType(strings.ToLower(exportFileType))]
+	if !ok {
+		return common.ErrUnsupportedExportType
+	}
+	columns, err := parseExportColumns(form.Columns)
+	if err != nil {
+		return err
+	}
+
 	service.mu.Lock()
 	defer service.mu.Unlock()
 
@@ -31,55 +52,289 @@ func (service *tixService) ExportEvent(
 		return err
 	}
 
-	participants, err := service.postgreSQLRepository.GetAllParticipants(
-		ctx, event.ID, "", 0, 0, 0, "name", "ASC")
+	data, err := service.eventExportData(ctx, event, form, columns)
 	if err != nil {
 		return err
 	}
 
-	totalApproved := service.postgreSQLRepository.CountParticipants(
-		ctx, event.ID, common.ParticipantRequestApproved, 0, 0)
-	totalDeclined := service.postgreSQLRepository.CountParticipants(
-		ctx, event.ID, common.ParticipantRequestDeclined, 0, 0)
-	totalWaitingApproval := service.postgreSQLRepository.CountParticipants(
-		ctx, event.ID, common.ParticipantRequestWaiting, 0, 0)
+	filePath := fmt.Sprintf("./temps/exports/%s.%s", event.GoogleFormID, exporter.extension())
+	service.saveTempFile(filePath)
+	if err := saveEventExport(exporter, data, filePath); err != nil {
+		return fmt.Errorf("could not save export: %w", err)
+	}
 
-	if strings.EqualFold(string(common.ExportTypeXLS), strings.ToLower(exportFileType)) {
-		service.exportEventToExcel(
-			event, participants, totalApproved,
-			totalDeclined, totalWaitingApproval, targetEmail)
+	link, err := service.archiveEventExport(ctx, event, exporter, filePath)
+	if err != nil {
+		return err
 	}
 
-	if strings.EqualFold(string(common.ExportTypePDF), strings.ToLower(exportFileType)) {
-		service.exportEventToPDF(
-			event, participants, totalApproved,
-			totalDeclined, totalWaitingApproval, targetEmail)
+	return service.sendViaEmail(event, exporter.extension(), targetEmail, data.locale, link)
+}
+
+// archiveEventExport keep the export file in the file store, the download
+// link is returned when the file is too large to be attached to the email.
+// Nothing is archived when the file store is not configured.
+func (service *tixService) archiveEventExport(
+	ctx context.Context,
+	event *entity.Event,
+	exporter eventExporter,
+	filePath string,
+) (link string, err error) {
+	if service.fileStore == nil {
+		return "", nil
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	// every export is kept, e.g. exports/<form>/<time>/<form>.pdf
+	fileKey := path.Join(common.ExportArchivePrefix, event.GoogleFormID,
+		strconv.FormatInt(time.Now().UnixNano(), 10),
+		fmt.Sprintf("%s.%s", event.GoogleFormID, exporter.extension()))
+	if err := service.fileStore.Put(
+		ctx, fileKey, file, info.Size(), exporter.contentType(),
+	); err != nil {
+		return "", fmt.Errorf("could not archive export: %w", err)
+	}
+
+	if info.Size() <= service.exportAttachmentMaxSize {
+		return "", nil
+	}
+	link, err = service.exportDownloadLink(ctx, fileKey)
+	if err != nil {
+		// better a large attachment than no export at all
+		fmt.Println("⚠️ could not sign export download link:", err)
+		return "", nil
+	}
+	return link, nil
 }
 
-func (service *tixService) exportEventToExcel(
+// exportDownloadLink return the link to download the archived export until
+// it expires, the file store sign it (e.g. S3) or the app serve the file
+// behind a signed token, see OpenEventExport.
+func (service *tixService) exportDownloadLink(ctx context.Context, fileKey string) (string, error) {
+	link, err := service.fileStore.SignedURL(ctx, fileKey, common.ExportLinkExpiry)
+	if !errors.Is(err, storage.ErrSignedURLNotSupported) {
+		return link, err
+	}
+	if service.publicURL == "" {
+		return "", errors.New("public url is not configured")
+	}
+	key, err := service.ticketKey(common.ExportDownloadKeyID)
+	if err != nil {
+		return "", err
+	}
+	downloadToken, err := token.SignExportDownload(
+		key, fileKey, time.Now().Add(common.ExportLinkExpiry))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/api/v1/exports/download?token=%s",
+		service.publicURL, url.QueryEscape(downloadToken)), nil
+}
+
+// OpenEventExport open the archived export of the signed download link
+// sent by email, the caller must close the file.
+func (service *tixService) OpenEventExport(
+	ctx context.Context,
+	downloadToken string,
+) (file io.ReadCloser, fileName, contentType string, err error) {
+	if service.fileStore == nil {
+		return nil, "", "", common.ErrExportNotFound
+	}
+	key, err := service.ticketKey(common.ExportDownloadKeyID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	fileKey, err := token.VerifyExportDownload(
+		key.Public().(ed25519.PublicKey), downloadToken, time.Now())
+	switch {
+	case errors.Is(err, token.ErrExpiredExportDownload):
+		return nil, "", "", common.ErrExportLinkExpired
+	case err != nil:
+		return nil, "", "", common.ErrExportLinkInvalid
+	}
+
+	file, err = service.fileStore.Open(ctx, fileKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", "", common.ErrExportNotFound
+		}
+		return nil, "", "", err
+	}
+
+	fileName = path.Base(fileKey)
+	contentType = "application/octet-stream"
+	for _, exporter := range eventExporters {
+		if path.Ext(fileName) == "."+exporter.extension() {
+			contentType = exporter.contentType()
+		}
+	}
+	return file, fileName, contentType, nil
+}
+
+// StreamEventExport write the export straight into w, nothing is written
+// into the export directory. The export with too many participants is
+// only sent by email, see ExportEvent.
+func (service *tixService) StreamEventExport(
+	ctx context.Context,
+	googleFormID, exportFileType string,
+	form *request.EventRequestExport,
+	w io.Writer,
+) error {
+	exporter, ok := eventExporters[common.EventExportType(strings.ToLower(exportFileType))]
+	if !ok {
+		return common.ErrUnsupportedExportType
+	}
+	columns, err := parseExportColumns(form.Columns)
+	if err != nil {
+		return err
+	}
+
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return err
+	}
+
+	data, err := service.eventExportData(ctx, event, form, columns)
+	if err != nil {
+		return err
+	}
+	// the filtered export of the large event can still be downloaded
+	if len(data.participants) > common.ExportDownloadMaxParticipants {
+		return common.ErrExportTooLarge
+	}
+
+	return exporter.export(data, w)
+}
+
+func (service *tixService) eventExportData(
+	ctx context.Context,
 	event *entity.Event,
+	form *request.EventRequestExport,
+	columns []common.EventExportColumn,
+) (*eventExportData, error) {
+	participants, err := service.postgreSQLRepository.GetAllParticipants(
+		ctx, event.ID, "", 0, 0, 0, "name", "ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventExportData{
+		event:        event,
+		participants: filterExportParticipants(participants, form),
+		totalApproved: service.postgreSQLRepository.CountParticipants(
+			ctx, event.ID, common.ParticipantRequestApproved, 0, 0),
+		totalDeclined: service.postgreSQLRepository.CountParticipants(
+			ctx, event.ID, common.ParticipantRequestDeclined, 0, 0),
+		totalWaiting: service.postgreSQLRepository.CountParticipants(
+			ctx, event.ID, common.ParticipantRequestWaiting, 0, 0),
+		totalCheckedIn: service.postgreSQLRepository.CountParticipants(
+			ctx, event.ID, common.ParticipantCheckedIn, 0, 0),
+		withBOM: form.BOM,
+		columns: columns,
+		locale:  exportLocale(event, form),
+	}, nil
+}
+
+// exportLocale is the locale asked by the export request,
+// or the locale of the event branding.
+func exportLocale(event *entity.Event, form *request.EventRequestExport) i18n.Locale {
+	if locale, ok := i18n.Parse(form.Locale); ok {
+		return locale
+	}
+	return i18n.Of(event.Branding.Locale)
+}
+
+// filterExportParticipants keep the participants matching every filter of
+// the export request, the status follow the same rule as CountParticipants.
+func filterExportParticipants(
 	participants []*entity.Participant,
-	totalApproved, totalDeclined, totalWaiting int,
-	targetEmail string,
-) {
+	form *request.EventRequestExport,
+) []*entity.Participant {
+	search := strings.ToLower(strings.TrimSpace(form.Search))
+	items := make([]*entity.Participant, 0, len(participants))
+	for _, participant := range participants {
+		switch common.EventParticipantStatus(form.Status) {
+		case common.ParticipantRequestApproved:
+			if !participant.ApprovedAt.Valid {
+				continue
+			}
+		case common.ParticipantRequestDeclined:
+			if participant.ApprovedAt.Valid || !participant.DeclinedAt.Valid {
+				continue
+			}
+		case common.ParticipantRequestWaiting:
+			if participant.ApprovedAt.Valid || participant.DeclinedAt.Valid {
+				continue
+			}
+		}
+		if form.CheckedIn != nil && participant.CheckedInAt.Valid != *form.CheckedIn {
+			continue
+		}
+		registeredAt := int64(participant.CreatedAt.Int32)
+		if (form.RegisteredFrom != 0 && registeredAt < form.RegisteredFrom) ||
+			(form.RegisteredTo != 0 && registeredAt > form.RegisteredTo) {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(participant.Name), search) &&
+			!strings.Contains(strings.ToLower(participant.Email), search) &&
+			!strings.Contains(strings.ToLower(participant.Phone), search) {
+			continue
+		}
+		items = append(items, participant)
+	}
+	return items
+}
+
+// saveEventExport write the export file sent by email.
+func saveEventExport(exporter eventExporter, data *eventExportData, filePath string) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, common.ExportFileMode)
+	if err != nil {
+		return err
+	}
+	if err := exporter.export(data, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// exportEventToExcel write the spreadsheet row by row, the sheet of the large
+// event is streamed (see common.ExcelStreamThreshold) so it is not kept in
+// memory, but the branding logo can not be drawn on it.
+func exportEventToExcel(data *eventExportData, w io.Writer) error {
+	event, participants := data.event, data.participants
 	f := excelize.NewFile()
 	defer func() { _ = f.Close() }()
 
-	// HEADER
-	_ = f.MergeCell("Sheet1", "A1", "I1")
-	_ = f.MergeCell("Sheet1", "A2", "I2")
-	_ = f.SetCellValue("Sheet1", "A1", "TIX")
-	_ = f.SetCellValue("Sheet1", "A2", "Manage Events Participants and Tickets")
+	var sheet excelSheetWriter = &excelFileSheet{file: f, sheet: "Sheet1"}
+	streamed := len(participants) > common.ExcelStreamThreshold
+	if streamed {
+		sw, err := f.NewStreamWriter("Sheet1")
+		if err != nil {
+			return err
+		}
+		sheet = sw
+	}
+
+	// STYLES
+	branding := eventBranding(event.Branding)
 	titleStyle, _ := f.NewStyle(&excelize.Style{
 		Alignment: &excelize.Alignment{
 			Horizontal: "center",
 		},
 		Font: &excelize.Font{
-			Bold: true,
-			Size: common.ExcelTitleSize,
+			Bold:  true,
+			Size:  common.ExcelTitleSize,
+			Color: branding.PrimaryColor,
 		},
 	})
 	subtitleStyle, _ := f.NewStyle(&excelize.Style{
@@ -90,154 +345,245 @@ func (service *tixService) exportEventToExcel(
 			Bold:   false,
 			Italic: true,
 			Size:   common.ExcelSubtitleSize,
+			Color:  branding.PrimaryColor,
+		},
+	})
+	tableBorder := []excelize.Border{
+		{
+			Type:  "left",
+			Color: "000000",
+			Style: 1,
+		},
+		{
+			Type:  "top",
+			Color: "000000",
+			Style: 1,
+		},
+		{
+			Type:  "right",
+			Color: "000000",
+			Style: 1,
+		},
+		{
+			Type:  "bottom",
+			Color: "000000",
+			Style: 1,
+		},
+	}
+	borderStyle, _ := f.NewStyle(&excelize.Style{
+		Border: tableBorder,
+	})
+	tableHeaderStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold:  true,
+			Color: branding.SecondaryColor,
 		},
+		Border: tableBorder,
 	})
-	_ = f.SetCellStyle("Sheet1", "A1", "A1", titleStyle)
-	_ = f.SetCellStyle("Sheet1", "A2", "A2", subtitleStyle)
-	_ = f.SetRowHeight("Sheet1", common.ExcelTitleRow, common.ExcelTitleHeight)
-	_ = f.SetRowHeight("Sheet1", common.ExcelSubtitleRow, common.ExcelSubtitleHeight)
+
+	// PARTICIPANT DATA
+	// the streamed sheet need the column width before any row, it is sized
+	// from the table header and the first rows (see common.ExcelColWidthSampleSize)
+	columns := newExportTableColumns(data.columnsOr(excelExportColumns), participants, data.locale)
+	tableHeader := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		tableHeader = append(tableHeader, column.header)
+	}
+	participantRow := func(idx int) []interface{} {
+		row := make([]interface{}, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.cell(idx+1, participants[idx]))
+		}
+		return row
+	}
+	sampleRows := [][]interface{}{tableHeader}
+	for idx := 0; idx < len(participants) && idx < common.ExcelColWidthSampleSize; idx++ {
+		sampleRows = append(sampleRows, participantRow(idx))
+	}
+	for idx, width := range excelColWidths(sampleRows) {
+		_ = sheet.SetColWidth(idx+1, idx+1, width)
+	}
+	// the header and the event data span the table, the event data has
+	// a label and a value, so at least two columns are merged
+	lastCol, _ := excelize.ColumnNumberToName(len(columns))
+	if len(columns) < 2 {
+		lastCol = "B"
+	}
+
+	// HEADER
+	_ = sheet.SetRow(fmt.Sprintf("A%d", common.ExcelTitleRow), []interface{}{
+		excelize.Cell{StyleID: titleStyle, Value: branding.HeaderTitle},
+	}, excelize.RowOpts{Height: common.ExcelTitleHeight})
+	_ = sheet.SetRow(fmt.Sprintf("A%d", common.ExcelSubtitleRow), []interface{}{
+		excelize.Cell{StyleID: subtitleStyle, Value: branding.HeaderSubtitle},
+	}, excelize.RowOpts{Height: common.ExcelSubtitleHeight})
+	_ = sheet.MergeCell(fmt.Sprintf("A%d", common.ExcelTitleRow),
+		fmt.Sprintf("%s%d", lastCol, common.ExcelTitleRow))
+	_ = sheet.MergeCell(fmt.Sprintf("A%d", common.ExcelSubtitleRow),
+		fmt.Sprintf("%s%d", lastCol, common.ExcelSubtitleRow))
 
 	// EVENT DATA
-	_ = f.SetCellValue("Sheet1", "A5", "Nama:")
-	_ = f.SetCellValue("Sheet1", "B5", event.Name)
-	_ = f.SetCellValue("Sheet1", "A6", "Lokasi:")
-	_ = f.SetCellValue("Sheet1", "B6", event.Location)
-	_ = f.SetCellValue("Sheet1", "A7", "Tanggal:")
-	_ = f.SetCellValue("Sheet1", "B7", func() string {
-		ts := time.Unix(int64(event.EventDate), 0)
-		return fmt.Sprintf("%d %s %d", ts.Day(), ts.Month().String(), ts.Year())
-	}())
-	_ = f.SetCellValue("Sheet1", "A8", "Total Peserta:")
-	_ = f.SetCellValue("Sheet1", "B8", fmt.Sprintf(
-		"%d –– %d diterima | %d menunggu | %d ditolak ––",
-		event.TotalParticipants, totalApproved, totalWaiting, totalDeclined))
-	_ = f.MergeCell("Sheet1", "B5", "I5")
-	_ = f.MergeCell("Sheet1", "B6", "I6")
-	_ = f.MergeCell("Sheet1", "B7", "I7")
-	_ = f.MergeCell("Sheet1", "B8", "I8")
+	locale := data.locale
+	for idx, item := range [][]interface{}{
+		{locale.T("export.name") + ":", event.Name},
+		{locale.T("export.location") + ":", event.Location},
+		{locale.T("export.date") + ":", locale.Date(time.Unix(int64(event.EventDate), 0))},
+		{locale.T("export.total_participants") + ":", locale.T("export.totals",
+			event.TotalParticipants, data.totalApproved, data.totalWaiting,
+			data.totalDeclined, data.totalCheckedIn)},
+	} {
+		rowIdx := idx + common.ExcelEventDataStartIndex
+		cellStart, _ := excelize.CoordinatesToCellName(1, rowIdx)
+		_ = sheet.SetRow(cellStart, item)
+		_ = sheet.MergeCell(fmt.Sprintf("B%d", rowIdx), fmt.Sprintf("%s%d", lastCol, rowIdx))
+	}
 
-	// PARTICIPANT DATA
+	// the rows past the sample are written as they are made
+	totalRows := len(participants) + 1
+	for idx := 0; idx < totalRows; idx++ {
+		var row []interface{}
+		style := borderStyle
+		switch {
+		case idx == 0:
+			style, row = tableHeaderStyle, tableHeader
+		case idx < len(sampleRows):
+			row = sampleRows[idx]
+		default:
+			row = participantRow(idx - 1)
+		}
+		cells := make([]interface{}, len(row))
+		for colIdx, value := range row {
+			cells[colIdx] = excelize.Cell{StyleID: style, Value: value}
+		}
+		cellStart, _ := excelize.CoordinatesToCellName(1, idx+common.ExcelTableStartIndex)
+		_ = sheet.SetRow(cellStart, cells)
+	}
 
-	rowsData := [][]interface{}{{
-		"No", "Nama", "Email", "No Telp.", "Pekerjaan",
-		"Tanggal Lahir", "Diterima", "Ditolak", "Alasan Ditolak"}}
-	for i, participant := range participants {
-		rowsData = append(rowsData, []interface{}{
-			i + 1, participant.Name, participant.Email,
-			participant.Phone, participant.Job, participant.DoB,
-			func() string {
-				if participant.ApprovedAt.Valid {
-					return common.SymCheck
-				}
-				return common.SymDash
-			}(),
-			func() string {
-				if participant.DeclinedAt.Valid {
-					return common.SymCheck
-				}
-				return common.SymDash
-			}(),
-			func() string {
-				if participant.DeclinedReason.Valid {
-					return participant.DeclinedReason.String
-				}
-				return common.SymDash
-			}(),
-		})
+	// FOOTER
+	footerCell, _ := excelize.CoordinatesToCellName(1,
+		totalRows+common.ExcelTableStartIndex+common.ExcelFooterRowOffset)
+	_ = sheet.SetRow(footerCell, []interface{}{
+		locale.T("export.generated", locale.DateTime(time.Now()), branding.FooterText),
+	})
+
+	if err := sheet.Flush(); err != nil {
+		return err
 	}
-	borderStyle, _ := f.NewStyle(&excelize.Style{
-		Border: []excelize.Border{
-			{
-				Type:  "left",
-				Color: "000000",
-				Style: 1,
-			},
-			{
-				Type:  "top",
-				Color: "000000",
-				Style: 1,
-			},
-			{
-				Type:  "right",
-				Color: "000000",
-				Style: 1,
-			},
-			{
-				Type:  "bottom",
-				Color: "000000",
-				Style: 1,
+
+	if logo := fetchBrandingLogo(event.Branding); logo != nil && !streamed {
+		scale := float64(common.ExcelLogoHeight) / float64(logo.height)
+		if err := f.AddPictureFromBytes("Sheet1", "A1", &excelize.Picture{
+			Extension: "." + string(logo.extension),
+			File:      logo.data,
+			Format: &excelize.GraphicOptions{
+				AltText: branding.HeaderTitle,
+				ScaleX:  scale,
+				ScaleY:  scale,
 			},
-		},
-	})
-	for idx, row := range rowsData {
-		rowData := row
-		cellStart, _ := excelize.CoordinatesToCellName(1, idx+common.ExcelTableStartIndex)
-		cellEnd, _ := excelize.CoordinatesToCellName(len(rowData), idx+common.ExcelTableStartIndex)
-		_ = f.SetSheetRow("Sheet1", cellStart, &rowData)
-		_ = f.SetCellStyle("Sheet1", cellStart, cellEnd, borderStyle)
-		// Expand the width of cells in each row based on content
-		for colIdx, cellValue := range rowData {
-			cell, _ := excelize.CoordinatesToCellName(colIdx+1, idx+common.ExcelTableStartIndex)
-			var valueStr string
-			// Convert cell value to string
-			switch val := cellValue.(type) {
-			case string:
-				valueStr = val
-			case int:
-				valueStr = strconv.Itoa(val)
-			default:
-				// Handle other data types if needed
-				valueStr = ""
+		}); err != nil {
+			fmt.Println("⚠️ could not draw branding logo:", err)
+		}
+	}
+
+	return f.Write(w)
+}
+
+// excelSheetWriter write the sheet row by row, it is implemented by
+// the excelize stream writer and the excelFileSheet.
+type excelSheetWriter interface {
+	SetColWidth(min, max int, width float64) error
+	SetRow(cell string, values []interface{}, opts ...excelize.RowOpts) error
+	MergeCell(hCell, vCell string) error
+	Flush() error
+}
+
+// excelFileSheet write the rows into the sheet of the file, unlike the
+// stream writer the sheet can still be edited, e.g. to draw a picture.
+type excelFileSheet struct {
+	file  *excelize.File
+	sheet string
+}
+
+func (s *excelFileSheet) SetColWidth(min, max int, width float64) error {
+	minCol, err := excelize.ColumnNumberToName(min)
+	if err != nil {
+		return err
+	}
+	maxCol, err := excelize.ColumnNumberToName(max)
+	if err != nil {
+		return err
+	}
+	return s.file.SetColWidth(s.sheet, minCol, maxCol, width)
+}
+
+func (s *excelFileSheet) SetRow(cell string, values []interface{}, opts ...excelize.RowOpts) error {
+	col, row, err := excelize.CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	for idx, value := range values {
+		cellName, _ := excelize.CoordinatesToCellName(col+idx, row)
+		if styled, ok := value.(excelize.Cell); ok {
+			if err := s.file.SetCellStyle(s.sheet, cellName, cellName, styled.StyleID); err != nil {
+				return err
 			}
-			cellWidth := len(valueStr) + 2 // Add some padding
-			// Get current column width
-			currentWidth, _ := f.GetColWidth("Sheet1", cell[:1])
-			if float64(cellWidth) > currentWidth {
-				_ = f.SetColWidth("Sheet1", cell[:1], cell[:1], float64(cellWidth))
+			value = styled.Value
+		}
+		if err := s.file.SetCellValue(s.sheet, cellName, value); err != nil {
+			return err
+		}
+	}
+	for _, opt := range opts {
+		if opt.Height > 0 {
+			if err := s.file.SetRowHeight(s.sheet, row, opt.Height); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	// Save spreadsheet by the given path.
-	if err := f.SaveAs(fmt.Sprintf("./temps/exports/%s.xlsx", event.GoogleFormID)); err != nil {
-		fmt.Println(err)
-		return
-	}
+func (s *excelFileSheet) MergeCell(hCell, vCell string) error {
+	return s.file.MergeCell(s.sheet, hCell, vCell)
+}
+
+func (s *excelFileSheet) Flush() error {
+	return nil
+}
 
-	service.sendViaEmail(event.Name, event.GoogleFormID, "xlsx", targetEmail)
+// excelColWidths fit the width of the columns to their longest cell.
+func excelColWidths(rows [][]interface{}) []float64 {
+	var widths []float64
+	for _, row := range rows {
+		for idx, value := range row {
+			if idx == len(widths) {
+				widths = append(widths, common.ExcelMinColWidth)
+			}
+			// add some padding
+			width := float64(utf8.RuneCountInString(fmt.Sprint(value)) + 2)
+			if width > widths[idx] {
+				widths[idx] = width
+			}
+		}
+	}
+	return widths
 }
 
-func (service *tixService) exportEventToPDF(
-	event *entity.Event,
-	participants []*entity.Participant,
-	totalApproved, totalDeclined, totalWaiting int,
-	targetEmail string,
-) {
+func exportEventToPDF(data *eventExportData, w io.Writer) error {
+	event, participants := data.event, data.participants
 	m := pdf.NewMaroto(consts.Landscape, consts.A4)
 	m.SetPageMargins(common.PdfMarginLeft, common.PdfMarginTop, common.PdfMarginRight)
 	m.RegisterHeader(func() {})
 	m.RegisterFooter(func() {})
 
 	// HEADER
-	m.Row(common.PdfHeaderRowHeight, func() {
-		m.Col(common.PdfHeaderColWidth, func() {
-			m.Text("TIX", props.Text{
-				Size:  common.PdfTitleSize,
-				Style: consts.Bold,
-				Align: consts.Center,
-			})
-			m.Text("Manage Events Participants and Tickets", props.Text{
-				Top:   common.PdfSubtitleMarginTop,
-				Size:  common.PdfSubtitleSize,
-				Style: consts.Italic,
-				Align: consts.Center,
-			})
-		})
-	})
+	locale := data.locale
+	branding := eventBranding(event.Branding)
+	brandingPDFHeader(m, branding, fetchBrandingLogo(event.Branding))
 	m.Line(common.PdfLineSpaceHeight, props.Line{Width: common.PdfLineWidth})
 	m.Row(common.PdfEventDataRowHeight, func() {
 		m.Col(common.PdfEventDataTitleColWidth, func() {
-			m.Text("Nama", props.Text{
+			m.Text(locale.T("export.name"), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Bold,
 				Align: consts.Left,
@@ -253,7 +599,7 @@ func (service *tixService) exportEventToPDF(
 	})
 	m.Row(common.PdfEventDataRowHeight, func() {
 		m.Col(common.PdfEventDataTitleColWidth, func() {
-			m.Text("Lokasi", props.Text{
+			m.Text(locale.T("export.location"), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Bold,
 				Align: consts.Left,
@@ -269,17 +615,14 @@ func (service *tixService) exportEventToPDF(
 	})
 	m.Row(common.PdfEventDataRowHeight, func() {
 		m.Col(common.PdfEventDataTitleColWidth, func() {
-			m.Text("Tanggal", props.Text{
+			m.Text(locale.T("export.date"), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Bold,
 				Align: consts.Left,
 			})
 		})
 		m.Col(common.PdfEventDataItemColWidth, func() {
-			m.Text(": "+func() string {
-				ts := time.Unix(int64(event.EventDate), 0)
-				return fmt.Sprintf("%d %s %d", ts.Day(), ts.Month().String(), ts.Year())
-			}(), props.Text{
+			m.Text(": "+locale.Date(time.Unix(int64(event.EventDate), 0)), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Normal,
 				Align: consts.Left,
@@ -288,16 +631,16 @@ func (service *tixService) exportEventToPDF(
 	})
 	m.Row(common.PdfEventDataRowHeight, func() {
 		m.Col(common.PdfEventDataTitleColWidth, func() {
-			m.Text("Total Peserta", props.Text{
+			m.Text(locale.T("export.total_participants"), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Bold,
 				Align: consts.Left,
 			})
 		})
 		m.Col(common.PdfEventDataItemColWidth, func() {
-			m.Text(": "+fmt.Sprintf(
-				"%d –– %d diterima | %d menunggu | %d ditolak ––",
-				event.TotalParticipants, totalApproved, totalWaiting, totalDeclined,
+			m.Text(": "+locale.T("export.totals",
+				event.TotalParticipants, data.totalApproved, data.totalWaiting,
+				data.totalDeclined, data.totalCheckedIn,
 			), props.Text{
 				Size:  common.PdfEventDataSize,
 				Style: consts.Normal,
@@ -310,7 +653,7 @@ func (service *tixService) exportEventToPDF(
 	// CONTENT
 	m.Row(common.PdfTableTitleRowHeight, func() {
 		m.Col(common.PdfTableTitleRowWidth, func() {
-			m.Text("Daftar Peserta", props.Text{
+			m.Text(locale.T("export.participants"), props.Text{
 				Size:  common.PdfTableTitleSize,
 				Style: consts.Bold,
 				Align: consts.Left,
@@ -318,30 +661,45 @@ func (service *tixService) exportEventToPDF(
 			})
 		})
 	})
-	tableHeader := []string{"Nama", "Tanggal Lahir", "Email", "No Telp.", "Pekerjaan", "Status"}
+	// pdf table only have 12 grid columns, the remaining columns are merged into one column
+	columns := newExportTableColumns(data.columnsOr(pdfExportColumns), participants, locale)
+	var otherColumns []*exportTableColumn
+	if len(columns) > common.PdfTableMaxColumns {
+		otherColumns = columns[common.PdfTableMaxColumns-1:]
+		columns = columns[:common.PdfTableMaxColumns-1]
+	}
+	tableHeader := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		tableHeader = append(tableHeader, column.header)
+	}
+	if len(otherColumns) > 0 {
+		tableHeader = append(tableHeader, locale.T("export.others"))
+	}
 	var tableContents [][]string
-	for _, participant := range participants {
-		tableContents = append(tableContents, []string{
-			participant.Name, participant.DoB, participant.Email,
-			participant.Phone, participant.Job, func() string {
-				if participant.ApprovedAt.Valid {
-					return "diterima"
-				}
-				if participant.DeclinedAt.Valid {
-					return "ditolak"
-				}
-				return "menunggu"
-			}(),
-		})
+	for i, participant := range participants {
+		row := make([]string, 0, len(tableHeader))
+		for _, column := range columns {
+			row = append(row, fmt.Sprint(column.cell(i+1, participant)))
+		}
+		if len(otherColumns) > 0 {
+			var others []string
+			for _, column := range otherColumns {
+				others = append(others, fmt.Sprintf("%s: %v",
+					column.header, column.cell(i+1, participant)))
+			}
+			row = append(row, strings.Join(others, "; "))
+		}
+		tableContents = append(tableContents, row)
 	}
+	gridSizes := newPdfTableGridSizes(len(tableHeader))
 	m.TableList(tableHeader, tableContents, props.TableList{
 		ContentProp: props.TableListContent{
-			GridSizes: []uint{2, 2, 2, 2, 2, 2},
+			GridSizes: gridSizes,
 			Size:      8,
 		},
 		HeaderProp: props.TableListContent{
-			GridSizes: []uint{2, 2, 2, 2, 2, 2},
-			Color:     color.Color{Red: 100},
+			GridSizes: gridSizes,
+			Color:     brandingColor(branding.SecondaryColor),
 		},
 		Align: consts.Left,
 		Line:  true,
@@ -358,7 +716,7 @@ func (service *tixService) exportEventToPDF(
 	// FOOTER
 	m.Row(common.PdfTableTitleRowHeight, func() {
 		m.Col(common.PdfTableTitleRowWidth, func() {
-			m.Text("Generated "+time.Now().String()+" via tix.bakode.xyz", props.Text{
+			m.Text(locale.T("export.generated", locale.DateTime(time.Now()), branding.FooterText), props.Text{
 				Style: consts.Italic,
 				Size:  common.PdfFooterTitleSize,
 				Align: consts.Center,
@@ -367,59 +725,123 @@ func (service *tixService) exportEventToPDF(
 		})
 	})
 
-	if err := m.OutputFileAndClose(fmt.Sprintf("./temps/exports/%s.pdf", event.GoogleFormID)); err != nil {
-		fmt.Println("⚠️  Could not save PDF:", err)
-		return
+	output, err := m.Output()
+	if err != nil {
+		return fmt.Errorf("⚠️ could not draw pdf: %s", err.Error())
 	}
-
-	service.sendViaEmail(event.Name, event.GoogleFormID, "pdf", targetEmail)
+	_, err = output.WriteTo(w)
+	return err
 }
 
+// sendViaEmail send the export file as the email attachment, or the
+// download link of the archived export when the link is given.
 func (service *tixService) sendViaEmail(
-	eventName, eventFormID, exportType, targetEmail string,
-) {
+	event *entity.Event,
+	exportType, targetEmail string,
+	locale i18n.Locale,
+	link string,
+) error {
 	filePath := "temps/exports"
-	attachmentName := fmt.Sprintf("%s.%s", eventFormID, exportType)
-	title := fmt.Sprintf("Export Data for %s with type %s", eventName, exportType)
+	attachmentName := fmt.Sprintf("%s.%s", event.GoogleFormID, exportType)
+	title := locale.T("email.export.subject", event.Name, exportType)
+	// the email follow the locale of the export
+	branding := eventBranding(event.Branding)
+	branding.Locale = string(locale)
 
 	// EMAIL FROM TEMPLATE
 	m := mailer.Mailer{
-		Theme: new(template.Default),
-		Product: mailer.Product{
-			Name: "TIX",
-			Link: "https://tix.bakode.xyz/",
-			Logo: "https://avatars.githubusercontent.com/u/105574217?s=400&u=81ba732eec2ca291da7654906168eb38a391ea22&v=4",
-		},
+		Theme:   new(template.Default),
+		Product: brandingProduct(branding),
 	}
-	e := mailer.Email{
-		Body: mailer.Body{
-			Name:   "Tix User",
-			Intros: []string{"Please find attached the requested export of event data. Thank you for using tix app.!"},
-		},
+	e := mailer.Email{Body: brandingEmailBody(branding, locale.T("email.export.name"))}
+	e.Body.Intros = []string{locale.T("email.export.intro")}
+	if link != "" {
+		e.Body.Intros = []string{locale.T("email.export.link_intro")}
+		e.Body.Actions = []mailer.Action{{
+			Instructions: locale.T("email.export.link",
+				locale.DateTime(time.Now().Add(common.ExportLinkExpiry))),
+			Button: mailer.Button{
+				Color: branding.PrimaryColor,
+				Text:  locale.T("email.export.download_button"),
+				Link:  link,
+			},
+		}}
 	}
 	txtBody, err := m.GenerateHTML(&e)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	// BUILD EMAIL
 	mail := gomail.NewMessage()
-	mail.SetHeader("From", "BAKODE SUPPORT <support@bakode.xyz>")
+	mail.SetAddressHeader("From", branding.SenderEmail, branding.SenderName)
 	mail.SetHeader("To", targetEmail)
 	mail.SetHeader("Subject", title)
 	mail.SetBody("text/html", txtBody)
-	mail.Attach(fmt.Sprintf("%s/%s", filePath, attachmentName), gomail.Rename(attachmentName))
+	if link == "" {
+		mail.Attach(fmt.Sprintf("%s/%s", filePath, attachmentName), gomail.Rename(attachmentName))
+	}
 
 	// SEND EMAIL
 	if err := service.mailer.DialAndSend(mail); err != nil {
-		fmt.Println(err)
-		return
+		return fmt.Errorf("⚠️ could not send export email: %s", err.Error())
 	}
 
-	// REMOVE FILE
-	if err := os.Remove(fmt.Sprintf("%s/%s", filePath, attachmentName)); err != nil {
+	// REMOVE FILE, the export is already sent so it is not retried
+	if err := service.removeTempFile(attachmentName); err != nil {
 		fmt.Println("Error removing file:", err)
-		return
 	}
+	return nil
+}
+
+type customAnswerColumn struct {
+	ID       string
+	Question string
+	Position int
+}
+
+func (column *customAnswerColumn) answerOf(participant *entity.Participant) string {
+	if answer, ok := participant.CustomAnswers[column.ID]; ok && answer.Answer != "" {
+		return answer.Answer
+	}
+	return common.SymDash
+}
+
+// newCustomAnswerColumns collect every custom question answered
+// by the participants ordered as it appears on the google form.
+func newCustomAnswerColumns(participants []*entity.Participant) []*customAnswerColumn {
+	columns := make(map[string]*customAnswerColumn)
+	for _, participant := range participants {
+		for questionID, answer := range participant.CustomAnswers {
+			if _, ok := columns[questionID]; !ok {
+				columns[questionID] = &customAnswerColumn{
+					ID:       questionID,
+					Question: answer.Question,
+					Position: answer.Position,
+				}
+			}
+		}
+	}
+	items := make([]*customAnswerColumn, 0, len(columns))
+	for _, column := range columns {
+		items = append(items, column)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Position == items[j].Position {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].Position < items[j].Position
+	})
+	return items
+}
+
+func newPdfTableGridSizes(totalColumns int) []uint {
+	gridSizes := make([]uint, totalColumns)
+	for idx := range gridSizes {
+		gridSizes[idx] = uint(common.PdfTableMaxColumns / totalColumns)
+		if idx < common.PdfTableMaxColumns%totalColumns {
+			gridSizes[idx]++
+		}
+	}
+	return gridSizes
 }
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/pkg/i18n"
+	"io"
+	"strings"
+)
+
+// utf8BOM is the UTF-8 byte order mark.
+const utf8BOM = "\uFEFF"
+
+// eventExportData is the event data written into the export file.
+type eventExportData struct {
+	event          *entity.Event
+	participants   []*entity.Participant
+	totalApproved  int
+	totalDeclined  int
+	totalWaiting   int
+	totalCheckedIn int
+	// withBOM prepend the UTF-8 byte order mark to the csv export
+	withBOM bool
+	// columns is the participant columns chosen by the admin,
+	// the exporter write its own columns when none is chosen
+	columns []common.EventExportColumn
+	// locale is the language of the export, see i18n.Locales
+	locale i18n.Locale
+}
+
+// columnsOr return the chosen columns, or the given columns when none is chosen.
+func (data *eventExportData) columnsOr(columns []common.EventExportColumn) []common.EventExportColumn {
+	if len(data.columns) > 0 {
+		return data.columns
+	}
+	return columns
+}
+
+// eventExporter write the event data into the export file of its type.
+type eventExporter interface {
+	extension() string
+	// contentType is the media type of the archived export file
+	contentType() string
+	export(data *eventExportData, w io.Writer) error
+}
+
+// eventExporters register the exporter of every export type, see
+// common.EventExportTypes, a new export type only need its exporter here.
+var eventExporters = map[common.EventExportType]eventExporter{
+	common.ExportTypeXLS:    excelEventExporter{},
+	common.ExportTypePDF:    pdfEventExporter{},
+	common.ExportTypeCSV:    csvEventExporter{},
+	common.ExportTypeJSON:   jsonEventExporter{},
+	common.ExportTypeNDJSON: jsonEventExporter{lines: true},
+}
+
+// exportColumn is a participant column of the export, the custom answers
+// are not listed here since each google form question is its own column.
+type exportColumn struct {
+	// header is the i18n message of the column name
+	header string
+	// cell is the value of the spreadsheet cell
+	cell func(locale i18n.Locale, no int, participant *entity.Participant) interface{}
+	// value is the value of the json field, the cell is written when it is nil
+	value func(locale i18n.Locale, no int, participant *entity.Participant) interface{}
+}
+
+var exportColumns = map[common.EventExportColumn]*exportColumn{
+	common.ExportColumnNo: {
+		header: "column.no",
+		cell:   func(_ i18n.Locale, no int, _ *entity.Participant) interface{} { return no },
+	},
+	common.ExportColumnName: {
+		header: "column.name",
+		cell:   func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} { return participant.Name },
+	},
+	common.ExportColumnEmail: {
+		header: "column.email",
+		cell:   func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} { return participant.Email },
+	},
+	common.ExportColumnPhone: {
+		header: "column.phone",
+		cell:   func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} { return participant.Phone },
+	},
+	common.ExportColumnJob: {
+		header: "column.job",
+		cell:   func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} { return participant.Job },
+	},
+	common.ExportColumnDoB: {
+		header: "column.dob",
+		cell:   func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} { return participant.DoB },
+	},
+	common.ExportColumnApproved: {
+		header: "column.approved",
+		cell: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return exportSymbol(participant.ApprovedAt.Valid)
+		},
+		value: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return participant.ApprovedAt.Valid
+		},
+	},
+	common.ExportColumnDeclined: {
+		header: "column.declined",
+		cell: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return exportSymbol(participant.DeclinedAt.Valid)
+		},
+		value: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return participant.DeclinedAt.Valid
+		},
+	},
+	common.ExportColumnDeclinedReason: {
+		header: "column.declined_reason",
+		cell: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			if participant.DeclinedReason.Valid {
+				return participant.DeclinedReason.String
+			}
+			return common.SymDash
+		},
+		value: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return participant.DeclinedReason.String
+		},
+	},
+	common.ExportColumnCheckedIn: {
+		header: "column.checked_in",
+		cell: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return exportSymbol(participant.CheckedInAt.Valid)
+		},
+		value: func(_ i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return participant.CheckedInAt.Valid
+		},
+	},
+	common.ExportColumnStatus: {
+		header: "column.status",
+		cell: func(locale i18n.Locale, _ int, participant *entity.Participant) interface{} {
+			return exportParticipantStatus(locale, participant)
+		},
+	},
+}
+
+// the columns written by each exporter when the admin does not choose them
+var (
+	excelExportColumns = []common.EventExportColumn{
+		common.ExportColumnNo, common.ExportColumnName, common.ExportColumnEmail,
+		common.ExportColumnPhone, common.ExportColumnJob, common.ExportColumnDoB,
+		common.ExportColumnApproved, common.ExportColumnDeclined,
+		common.ExportColumnDeclinedReason, common.ExportColumnCheckedIn,
+		common.ExportColumnAnswers,
+	}
+	csvExportColumns = []common.EventExportColumn{
+		common.ExportColumnNo, common.ExportColumnName, common.ExportColumnEmail,
+		common.ExportColumnPhone, common.ExportColumnJob, common.ExportColumnDoB,
+		common.ExportColumnApproved, common.ExportColumnDeclined,
+		common.ExportColumnDeclinedReason, common.ExportColumnCheckedIn,
+		common.ExportColumnStatus, common.ExportColumnAnswers,
+	}
+	pdfExportColumns = []common.EventExportColumn{
+		common.ExportColumnName, common.ExportColumnDoB, common.ExportColumnEmail,
+		common.ExportColumnPhone, common.ExportColumnJob, common.ExportColumnStatus,
+		common.ExportColumnAnswers,
+	}
+)
+
+// parseExportColumns parse the comma separated columns chosen by the admin,
+// nil is returned when none is chosen. A repeated column is written once.
+func parseExportColumns(value string) ([]common.EventExportColumn, error) {
+	var columns []common.EventExportColumn
+	chosen := make(map[common.EventExportColumn]bool)
+	for _, item := range strings.Split(value, ",") {
+		column := common.EventExportColumn(strings.ToLower(strings.TrimSpace(item)))
+		if column == "" || chosen[column] {
+			continue
+		}
+		if _, ok := exportColumns[column]; !ok && column != common.ExportColumnAnswers {
+			return nil, common.ErrUnsupportedExportColumn
+		}
+		chosen[column] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// exportTableColumn is a column of the spreadsheet exports,
+// the header and the cells are written in the locale of the export.
+type exportTableColumn struct {
+	header string
+	cell   func(no int, participant *entity.Participant) interface{}
+}
+
+// newExportTableColumns expand the custom answers column of the
+// spreadsheet exports into a column per google form question.
+func newExportTableColumns(
+	columns []common.EventExportColumn,
+	participants []*entity.Participant,
+	locale i18n.Locale,
+) []*exportTableColumn {
+	var tableColumns []*exportTableColumn
+	for _, column := range columns {
+		if column != common.ExportColumnAnswers {
+			column := exportColumns[column]
+			tableColumns = append(tableColumns, &exportTableColumn{
+				header: locale.T(column.header),
+				cell: func(no int, participant *entity.Participant) interface{} {
+					return column.cell(locale, no, participant)
+				},
+			})
+			continue
+		}
+		for _, customColumn := range newCustomAnswerColumns(participants) {
+			customColumn := customColumn
+			tableColumns = append(tableColumns, &exportTableColumn{
+				header: customColumn.Question,
+				cell: func(_ int, participant *entity.Participant) interface{} {
+					return customColumn.answerOf(participant)
+				},
+			})
+		}
+	}
+	return tableColumns
+}
+
+type excelEventExporter struct{}
+
+func (excelEventExporter) extension() string { return "xlsx" }
+
+func (excelEventExporter) contentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (excelEventExporter) export(data *eventExportData, w io.Writer) error {
+	return exportEventToExcel(data, w)
+}
+
+type pdfEventExporter struct{}
+
+func (pdfEventExporter) extension() string { return "pdf" }
+
+func (pdfEventExporter) contentType() string { return "application/pdf" }
+
+func (pdfEventExporter) export(data *eventExportData, w io.Writer) error {
+	return exportEventToPDF(data, w)
+}
+
+// csvFormulaPrefixes is the first character of the cell read as a formula
+// by the spreadsheet apps opening the csv export.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvCell escape the cell starting as a formula with a quote, so the
+// answer of the participant is never run as a formula (csv injection).
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvEventExporter write the participant table of the excel export with the
+// status of the pdf export, so the file can be imported by another tool.
+type csvEventExporter struct{}
+
+func (csvEventExporter) extension() string { return "csv" }
+
+func (csvEventExporter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (csvEventExporter) export(data *eventExportData, w io.Writer) error {
+	return writeTextExport(w, data.withBOM, func(w *bufio.Writer) error {
+		columns := newExportTableColumns(
+			data.columnsOr(csvExportColumns), data.participants, data.locale)
+		tableHeader := make([]string, 0, len(columns))
+		for _, column := range columns {
+			tableHeader = append(tableHeader, column.header)
+		}
+
+		writer := csv.NewWriter(w)
+		if err := writer.Write(tableHeader); err != nil {
+			return err
+		}
+		for i, participant := range data.participants {
+			row := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row = append(row, csvCell(fmt.Sprint(column.cell(i+1, participant))))
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+}
+
+type (
+	// jsonEventExporter write the event and its participants as a json
+	// document, or a participant per line when lines is set (ndjson).
+	jsonEventExporter struct {
+		lines bool
+	}
+
+	eventExport struct {
+		GoogleFormID      string              `json:"google_form_id"`
+		Name              string              `json:"name"`
+		Location          string              `json:"location"`
+		EventDate         int32               `json:"event_date"`
+		TotalParticipants int32               `json:"total_participants"`
+		TotalApproved     int                 `json:"total_approved"`
+		TotalWaiting      int                 `json:"total_waiting"`
+		TotalDeclined     int                 `json:"total_declined"`
+		TotalCheckedIn    int                 `json:"total_checked_in"`
+		Participants      []participantExport `json:"participants"`
+	}
+
+	// participantExport keep the participant fields in the order of the columns
+	participantExport []participantExportField
+
+	// answerExport is the custom answer of the participant, the question id
+	// tell apart the google form questions sharing the same title.
+	answerExport struct {
+		QuestionID string `json:"question_id"`
+		Question   string `json:"question"`
+		Answer     string `json:"answer"`
+	}
+
+	participantExportField struct {
+		key   common.EventExportColumn
+		value interface{}
+	}
+)
+
+func (participant participantExport) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range participant {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (exporter jsonEventExporter) extension() string {
+	if exporter.lines {
+		return "ndjson"
+	}
+	return "json"
+}
+
+func (exporter jsonEventExporter) contentType() string {
+	if exporter.lines {
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+func (exporter jsonEventExporter) export(data *eventExportData, w io.Writer) error {
+	columns := data.columnsOr(common.EventExportColumns)
+	customColumns := newCustomAnswerColumns(data.participants)
+	participants := make([]participantExport, 0, len(data.participants))
+	for i, participant := range data.participants {
+		item := make(participantExport, 0, len(columns))
+		for _, column := range columns {
+			field := participantExportField{key: column}
+			switch {
+			case column == common.ExportColumnAnswers:
+				answers := make([]answerExport, 0, len(customColumns))
+				for _, customColumn := range customColumns {
+					answer := answerExport{QuestionID: customColumn.ID, Question: customColumn.Question}
+					if customAnswer, ok := participant.CustomAnswers[customColumn.ID]; ok {
+						answer.Answer = customAnswer.Answer
+					}
+					answers = append(answers, answer)
+				}
+				field.value = answers
+			case exportColumns[column].value != nil:
+				field.value = exportColumns[column].value(data.locale, i+1, participant)
+			default:
+				field.value = exportColumns[column].cell(data.locale, i+1, participant)
+			}
+			item = append(item, field)
+		}
+		participants = append(participants, item)
+	}
+
+	// json has no byte order mark, the bom is only for the csv export
+	return writeTextExport(w, false, func(w *bufio.Writer) error {
+		encoder := json.NewEncoder(w)
+		if exporter.lines {
+			for _, participant := range participants {
+				if err := encoder.Encode(participant); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return encoder.Encode(&eventExport{
+			GoogleFormID:      data.event.GoogleFormID,
+			Name:              data.event.Name,
+			Location:          data.event.Location,
+			EventDate:         data.event.EventDate,
+			TotalParticipants: data.event.TotalParticipants,
+			TotalApproved:     data.totalApproved,
+			TotalWaiting:      data.totalWaiting,
+			TotalDeclined:     data.totalDeclined,
+			TotalCheckedIn:    data.totalCheckedIn,
+			Participants:      participants,
+		})
+	})
+}
+
+// writeTextExport buffer the text export, the UTF-8 byte order mark
+// is written first when it is asked (excel need it to read UTF-8 csv).
+func writeTextExport(w io.Writer, withBOM bool, write func(w *bufio.Writer) error) error {
+	buffered := bufio.NewWriter(w)
+	if withBOM {
+		if _, err := buffered.WriteString(utf8BOM); err != nil {
+			return err
+		}
+	}
+	if err := write(buffered); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// exportSymbol is the cell of the yes or no column.
+func exportSymbol(valid bool) string {
+	if valid {
+		return common.SymCheck
+	}
+	return common.SymDash
+}
+
+// exportParticipantStatus is the status of the participant written by the exports.
+func exportParticipantStatus(locale i18n.Locale, participant *entity.Participant) string {
+	if participant.CheckedInAt.Valid {
+		return locale.T("status.checked_in")
+	}
+	if participant.ApprovedAt.Valid {
+		return locale.T("status.approved")
+	}
+	if participant.DeclinedAt.Valid {
+		return locale.T("status.declined")
+	}
+	return locale.T("status.waiting")
+}
@@ -2,16 +2,21 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
 	"fmt"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/pkg/i18n"
 	"github.com/aasumitro/tix/pkg/mailer"
 	"github.com/aasumitro/tix/pkg/mailer/template"
 	"github.com/johnfercher/maroto/pkg/consts"
 	"github.com/johnfercher/maroto/pkg/pdf"
 	"github.com/johnfercher/maroto/pkg/props"
 	"gopkg.in/gomail.v2"
-	"os"
+	"strings"
+	"time"
 )
 
 func (service *tixService) GenerateTicket(
@@ -28,102 +33,377 @@ func (service *tixService) GenerateTicket(
 		return err
 	}
 
+	return service.sendParticipantTicket(ctx, event, participantID, 0)
+}
+
+// sendParticipantTicket generate the ticket files of the participant and send
+// them by email, every attempt is recorded as a ticket delivery of the job (if any).
+// The participant is marked as sent once the ticket is delivered. Only a failed
+// send fail the delivery, the ticket already sent is never sent again because
+// the record can not be stored.
+func (service *tixService) sendParticipantTicket(
+	ctx context.Context,
+	event *entity.Event,
+	participantID, jobID int32,
+) error {
 	participant, err := service.postgreSQLRepository.GetParticipantByIDAndEventID(
 		ctx, participantID, event.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := service.generatePDFTicket(event, participant); err != nil {
+	messageID, err := service.sendTicket(ctx, event, participant)
+	service.recordTicketDelivery(ctx, event, participant, jobID, messageID, err)
+	if err != nil {
 		return err
 	}
 
-	service.sendTicketViaEmail(event.ID, participant.ID, event.Name, participant.Name, participant.Email)
+	if err := service.postgreSQLRepository.UpdateParticipantTicketSentAt(
+		ctx, participant.ID, time.Now().Unix()); err != nil {
+		fmt.Println("⚠️ could not mark participant ticket as sent:", err)
+	}
 
 	return nil
 }
 
+func (service *tixService) sendTicket(
+	ctx context.Context,
+	event *entity.Event,
+	participant *entity.Participant,
+) (messageID string, err error) {
+	if !participant.ApprovedAt.Valid {
+		return "", common.ErrParticipantNotApproved
+	}
+
+	ticketCode, err := service.participantTicketCode(ctx, participant)
+	if err != nil {
+		return "", err
+	}
+
+	qrCode, err := service.ticketQRCode(event, participant.ID, ticketCode)
+	if err != nil {
+		return "", err
+	}
+
+	if err := service.generatePDFTicket(event, participant, ticketCode, qrCode); err != nil {
+		return "", err
+	}
+	attachments := []string{fmt.Sprintf("gen%d%dtix.pdf", event.ID, participant.ID)}
+
+	if err := service.generateCalendarTicket(event, participant, ticketCode); err != nil {
+		return "", err
+	}
+	attachments = append(attachments, calendarAttachmentName(event.ID, participant.ID))
+
+	if service.walletPass != nil {
+		if err := service.generateWalletPassTicket(event, participant, ticketCode, qrCode); err != nil {
+			return "", err
+		}
+		attachments = append(attachments, walletPassAttachmentName(event.ID, participant.ID))
+	}
+
+	return service.sendTicketViaEmail(event, participant, ticketCode, attachments)
+}
+
+// recordTicketDelivery store the result of the attempt to send the ticket,
+// the listeners are notified of the attempt even when it can not be stored.
+func (service *tixService) recordTicketDelivery(
+	ctx context.Context,
+	event *entity.Event,
+	participant *entity.Participant,
+	jobID int32,
+	messageID string,
+	sendErr error,
+) {
+	now := time.Now().Unix()
+	delivery := &entity.TicketDelivery{
+		JobID:            sql.NullInt32{Int32: jobID, Valid: jobID != 0},
+		ParticipantID:    participant.ID,
+		ParticipantName:  participant.Name,
+		ParticipantEmail: participant.Email,
+		Status:           string(common.TicketDeliverySent),
+		MessageID:        sql.NullString{String: messageID, Valid: messageID != ""},
+		AttemptedAt:      sql.NullInt32{Int32: int32(now), Valid: true},
+		UpdatedAt:        sql.NullInt32{Int32: int32(now), Valid: true},
+	}
+	if sendErr != nil {
+		delivery.Status = string(common.TicketDeliveryFailed)
+		delivery.Error = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	if err := service.postgreSQLRepository.SaveTicketDelivery(ctx, delivery, now); err != nil {
+		fmt.Println("⚠️ could not save ticket delivery:", err)
+	}
+
+	service.redisCache.Del(ctx, fmt.Sprintf("participants-%s", event.GoogleFormID))
+	service.notify(ctx, event.GoogleFormID, common.NotificationTicketDelivered,
+		newTicketDeliveryResponse(delivery))
+}
+
+// participantTicketCode return the persisted ticket code of the participant,
+// a new one is generated and stored for the first ticket.
+func (service *tixService) participantTicketCode(
+	ctx context.Context,
+	participant *entity.Participant,
+) (string, error) {
+	if participant.TicketCode.Valid && participant.TicketCode.String != "" {
+		return participant.TicketCode.String, nil
+	}
+
+	ticketCode, err := newTicketCode()
+	if err != nil {
+		return "", err
+	}
+
+	return service.postgreSQLRepository.UpdateParticipantTicketCode(
+		ctx, participant.ID, ticketCode)
+}
+
+// newTicketCode generate an unguessable ticket code, e.g. 7K2M-QX4D-9PLA-ZR3T.
+func newTicketCode() (string, error) {
+	buf := make([]byte, common.TicketCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+	var parts []string
+	for len(code) > 4 {
+		parts = append(parts, code[:4])
+		code = code[4:]
+	}
+	parts = append(parts, code)
+
+	return strings.Join(parts, "-"), nil
+}
+
 func (service *tixService) generatePDFTicket(
 	event *entity.Event,
 	participant *entity.Participant,
+	ticketCode, qrCode string,
 ) error {
+	m, err := renderPDFTicket(event, participant, ticketCode, qrCode, event.TicketTemplate.String)
+	if err != nil {
+		return err
+	}
+
+	attachment := fmt.Sprintf("./temps/exports/gen%d%dtix.pdf",
+		event.ID, participant.ID)
+	service.saveTempFile(attachment)
+	if err := m.OutputFileAndClose(attachment); err != nil {
+		return fmt.Errorf("⚠️ could not save pdf: %s", err.Error())
+	}
+
+	return nil
+}
+
+// renderPDFTicket draw the ticket from the ticket template,
+// the built-in layout is drawn when there is no template.
+func renderPDFTicket(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode, qrCode, ticketTemplate string,
+) (pdf.Maroto, error) {
 	m := pdf.NewMaroto(consts.Landscape, consts.A4)
 	m.SetPageMargins(common.PdfMarginLeft, common.PdfMarginTop, common.PdfMarginRight)
 	m.RegisterHeader(func() {})
 	m.RegisterFooter(func() {})
 
-	m.Row(common.PdfHeaderRowHeight, func() {
-		m.Col(common.PdfHeaderColWidth, func() {
-			m.Text("TIX", props.Text{
-				Size:  common.PdfTitleSize,
+	branding := eventBranding(event.Branding)
+	logo := fetchBrandingLogo(event.Branding)
+	if ticketTemplate != "" {
+		layout, err := executeTicketTemplate(ticketTemplate,
+			newTicketTemplateData(event, participant, ticketCode, branding))
+		if err != nil {
+			return nil, err
+		}
+		return m, drawTicketLayout(m, layout, ticketCode, qrCode, branding, logo)
+	}
+
+	return m, drawBuiltInTicket(m, event, participant, ticketCode, qrCode, branding, logo)
+}
+
+func drawBuiltInTicket(
+	m pdf.Maroto,
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode, qrCode string,
+	branding entity.Branding,
+	logo *brandingLogo,
+) error {
+	brandingPDFHeader(m, branding, logo)
+	m.Line(common.PdfLineSpaceHeight, props.Line{Width: common.PdfLineWidth})
+
+	locale := i18n.Of(branding.Locale)
+	m.Row(common.PdfTicketRowHeight, func() {
+		m.Col(common.PdfTicketDetailColWidth, func() {
+			m.Text(event.Name, props.Text{
+				Top:   common.PdfTicketDetailLineHeight / 2,
+				Size:  common.PdfTicketEventNameSize,
 				Style: consts.Bold,
-				Align: consts.Center,
+				Align: consts.Left,
+				Color: brandingColor(branding.SecondaryColor),
+			})
+			details := []string{
+				locale.T("ticket.attendee") + " : " + participant.Name,
+				locale.T("ticket.email") + " : " + participant.Email,
+				locale.T("ticket.date") + " : " + ticketEventDate(event),
+				locale.T("ticket.location") + " : " + event.Location,
+			}
+			for i, detail := range details {
+				m.Text(detail, props.Text{
+					Top:   float64(common.PdfTicketDetailMarginTop + i*common.PdfTicketDetailLineHeight),
+					Size:  common.PdfTicketDetailSize,
+					Style: consts.Normal,
+					Align: consts.Left,
+				})
+			}
+		})
+		m.Col(common.PdfTicketQrCodeColWidth, func() {
+			m.QrCode(qrCode, props.Rect{
+				Percent: common.PdfTicketQrCodePercent,
+				Center:  true,
 			})
-			m.Text("Manage Events Participants and Tickets", props.Text{
-				Top:   common.PdfSubtitleMarginTop,
-				Size:  common.PdfSubtitleSize,
+		})
+	})
+	m.Line(common.PdfLineSpaceHeight, props.Line{Width: common.PdfLineWidth})
+
+	if err := drawTicketBarcode(m, ticketCode); err != nil {
+		return err
+	}
+	m.Row(common.PdfTicketFooterRowHeight, func() {
+		m.Col(common.PdfTicketBarcodeColWidth, func() {
+			m.Text(branding.FooterText, props.Text{
+				Top:   common.PdfTicketCodeRowHeight,
 				Style: consts.Italic,
+				Size:  common.PdfFooterTitleSize,
 				Align: consts.Center,
 			})
 		})
 	})
-	m.Line(common.PdfLineSpaceHeight, props.Line{Width: common.PdfLineWidth})
 
-	attachment := fmt.Sprintf("./temps/exports/gen%d%dtix.pdf",
-		event.ID, participant.ID)
-	if err := m.OutputFileAndClose(attachment); err != nil {
-		return fmt.Errorf("⚠️ could not save pdf: %s", err.Error())
+	return nil
+}
+
+// drawTicketBarcode draw the barcode of the ticket code with the code below it.
+func drawTicketBarcode(m pdf.Maroto, ticketCode string) error {
+	var barcodeErr error
+	m.Row(common.PdfTicketBarcodeRowHeight, func() {
+		m.Col(common.PdfTicketBarcodeColWidth, func() {
+			barcodeErr = m.Barcode(ticketCode, props.Barcode{
+				Percent: common.PdfTicketBarcodePercent,
+				Center:  true,
+			})
+		})
+	})
+	if barcodeErr != nil {
+		return fmt.Errorf("⚠️ could not generate barcode: %s", barcodeErr.Error())
 	}
+	m.Row(common.PdfTicketCodeRowHeight, func() {
+		m.Col(common.PdfTicketBarcodeColWidth, func() {
+			m.Text(ticketCode, props.Text{
+				Size:  common.PdfTicketCodeSize,
+				Style: consts.Bold,
+				Align: consts.Center,
+			})
+		})
+	})
 
 	return nil
 }
 
+// ticketEventDate is the event date printed on the ticket,
+// the month is named in the locale of the event.
+func ticketEventDate(event *entity.Event) string {
+	return i18n.Of(event.Branding.Locale).Date(time.Unix(int64(event.EventDate), 0))
+}
+
 func (service *tixService) sendTicketViaEmail(
-	eventID, participantID int32,
-	eventName, participantName, targetEmail string,
-) {
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode string,
+	attachmentNames []string,
+) (messageID string, err error) {
 	filePath := "temps/exports"
-	attachmentName := fmt.Sprintf("gen%d%dtix.pdf", eventID, participantID)
-	title := fmt.Sprintf("Ticket for %s", eventName)
+	branding := eventBranding(event.Branding)
+	locale := i18n.Of(branding.Locale)
+	title := locale.T("email.ticket.subject", event.Name)
 
 	// EMAIL FROM TEMPLATE
 	m := mailer.Mailer{
-		Theme: new(template.Default),
-		Product: mailer.Product{
-			Name: "TIX",
-			Link: "https://tix.bakode.xyz/",
-			Logo: "https://avatars.githubusercontent.com/u/105574217?s=400&u=81ba732eec2ca291da7654906168eb38a391ea22&v=4",
-		},
-	}
-	e := mailer.Email{
-		Body: mailer.Body{
-			Name:   participantName,
-			Intros: []string{"Please find attached the requested ticket of event!"},
-		},
+		Theme:   new(template.Default),
+		Product: brandingProduct(branding),
+	}
+	e := mailer.Email{Body: brandingEmailBody(branding, participant.Name)}
+	e.Body.Intros = []string{locale.T("email.ticket.intro")}
+	if link := service.walletPassLink(event.GoogleFormID, ticketCode); link != "" {
+		e.Body.Actions = append(e.Body.Actions, mailer.Action{
+			Instructions: locale.T("email.ticket.wallet"),
+			Button: mailer.Button{
+				Color: branding.PrimaryColor,
+				Text:  locale.T("email.ticket.wallet_button"),
+				Link:  link,
+			},
+		})
+	}
+	if link := service.ticketDownloadLink(event, participant.ID); link != "" {
+		e.Body.Actions = append(e.Body.Actions, mailer.Action{
+			Instructions: locale.T("email.ticket.download"),
+			Button: mailer.Button{
+				Color: branding.PrimaryColor,
+				Text:  locale.T("email.ticket.download_button"),
+				Link:  link,
+			},
+		})
 	}
 	txtBody, err := m.GenerateHTML(&e)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return "", err
 	}
 
 	// BUILD EMAIL
 	mail := gomail.NewMessage()
-	mail.SetHeader("From", "BAKODE SUPPORT <support@bakode.xyz>")
-	mail.SetHeader("To", targetEmail)
+	mail.SetAddressHeader("From", branding.SenderEmail, branding.SenderName)
+	mail.SetHeader("To", participant.Email)
 	mail.SetHeader("Subject", title)
+	messageID, err = newMessageID(branding.SenderEmail)
+	if err != nil {
+		return "", err
+	}
+	mail.SetHeader("Message-ID", messageID)
 	mail.SetBody("text/html", txtBody)
-	mail.Attach(fmt.Sprintf("%s/%s", filePath, attachmentName), gomail.Rename(attachmentName))
+	for _, attachmentName := range attachmentNames {
+		mail.Attach(fmt.Sprintf("%s/%s", filePath, attachmentName), gomail.Rename(attachmentName))
+	}
 
 	// SEND EMAIL
 	if err := service.mailer.DialAndSend(mail); err != nil {
-		fmt.Println(err)
-		return
+		return "", fmt.Errorf("⚠️ could not send ticket email: %s", err.Error())
 	}
 
 	// REMOVE FILE
-	if err := os.Remove(fmt.Sprintf("%s/%s", filePath, attachmentName)); err != nil {
-		fmt.Println("Error removing file:", err)
-		return
+	for _, attachmentName := range attachmentNames {
+		if err := service.removeTempFile(attachmentName); err != nil {
+			fmt.Println("Error removing file:", err)
+			break
+		}
 	}
+
+	return messageID, nil
+}
+
+// newMessageID generate the Message-ID header of the ticket email, so the
+// delivery can be traced in the smtp logs, e.g. <5f1c...@bakode.xyz>.
+func newMessageID(senderEmail string) (string, error) {
+	buf := make([]byte, common.TicketMessageIDLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	domain := common.CalendarUIDDomain
+	if at := strings.LastIndex(senderEmail, "@"); at != -1 && at < len(senderEmail)-1 {
+		domain = senderEmail[at+1:]
+	}
+
+	return fmt.Sprintf("<%x.%d@%s>", buf, time.Now().Unix(), domain), nil
 }
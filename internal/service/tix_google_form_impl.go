@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
+	"google.golang.org/api/forms/v1"
+	"sort"
 	"strings"
 	"time"
 )
@@ -35,6 +38,7 @@ func (service *tixService) FetchForms(
 			items = append(items, &response.GoogleFormQuestion{
 				ID:    q.QuestionItem.Question.QuestionId,
 				Title: q.Title,
+				Field: string(suggestParticipantField(q.Title)),
 			})
 		}
 
@@ -65,47 +69,58 @@ func (service *tixService) FetchForms(
 
 func (service *tixService) FetchResponds(
 	ctx context.Context,
-	formID string,
+	formID, submittedAfter string,
 ) (items []*response.GoogleFormRespond, err error) {
 	questions, err := service.FetchForms(ctx, formID)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := service.googleServiceRepository.GetResponses(ctx, formID)
+	data, err := service.googleServiceRepository.GetResponses(ctx, formID, submittedAfter)
 	if err != nil {
 		return nil, err
 	}
 
+	mapping := service.resolveFieldMapping(ctx, formID, questions)
+	positions := make(map[string]int, len(questions))
+	for idx, question := range questions {
+		positions[question.ID] = idx
+	}
+
 	for _, resp := range data.Responses {
 		var answer response.GoogleFormRespondAnswer
 		for _, responseAnswer := range resp.Answers {
-			for _, q := range questions {
-				if q.ID == responseAnswer.QuestionId {
-					key := strings.Replace(strings.ToLower(q.Title), " ", "_", 1)
-					if responseAnswer.TextAnswers != nil {
-						switch key {
-						case "pekerjaan":
-							answer.Job = responseAnswer.TextAnswers.Answers[0].Value
-						case "tanggal_lahir":
-							answer.DoB = responseAnswer.TextAnswers.Answers[0].Value
-						case "email":
-							answer.Email = responseAnswer.TextAnswers.Answers[0].Value
-						case "nama":
-							answer.Name = responseAnswer.TextAnswers.Answers[0].Value
-						case "nomor_telepon":
-							answer.Phone = responseAnswer.TextAnswers.Answers[0].Value
-						}
-					}
-					if responseAnswer.FileUploadAnswers != nil && key == "bukti_transfer" {
-						answer.PoP = fmt.Sprintf(
-							"https://drive.google.com/uc?export=view&id=%s",
-							responseAnswer.FileUploadAnswers.Answers[0].FileId)
-					}
-					break
+			value := formAnswerValue(&responseAnswer)
+			field, ok := mapping[responseAnswer.QuestionId]
+			if !ok {
+				if idx, found := positions[responseAnswer.QuestionId]; found && value != "" {
+					answer.Custom = append(answer.Custom, &response.ParticipantCustomAnswer{
+						QuestionID: responseAnswer.QuestionId,
+						Question:   questions[idx].Title,
+						Answer:     value,
+						Position:   idx,
+					})
 				}
+				continue
+			}
+			switch common.ParticipantField(field) {
+			case common.ParticipantFieldJob:
+				answer.Job = value
+			case common.ParticipantFieldDoB:
+				answer.DoB = value
+			case common.ParticipantFieldEmail:
+				answer.Email = value
+			case common.ParticipantFieldName:
+				answer.Name = value
+			case common.ParticipantFieldPhone:
+				answer.Phone = value
+			case common.ParticipantFieldPoP:
+				answer.PoP = value
 			}
 		}
+		sort.SliceStable(answer.Custom, func(i, j int) bool {
+			return answer.Custom[i].Position < answer.Custom[j].Position
+		})
 
 		items = append(items, &response.GoogleFormRespond{
 			RespondID:         resp.ResponseId,
@@ -130,30 +145,361 @@ func (service *tixService) SyncRespondData(
 		return err
 	}
 
-	respondents, err := service.FetchResponds(ctx, formID)
+	var lastSyncedAt string
+	if event.LastRespondSyncedAt.Valid {
+		lastSyncedAt = event.LastRespondSyncedAt.String
+	}
+
+	respondents, err := service.FetchResponds(ctx, formID, lastSyncedAt)
 	if err != nil {
 		return err
 	}
 
 	var newParticipant []*entity.Participant
+	var updatedParticipant int
 	for _, respond := range respondents {
-		data, err := service.postgreSQLRepository.GetParticipantByEmailAndEventID(
-			ctx, respond.Answer.Email, event.ID)
-		if (err == nil || errors.Is(err, sql.ErrNoRows)) && data == nil {
-			newParticipant = append(newParticipant, &entity.Participant{
-				EventID: event.ID,
-				Name:    respond.Answer.Name,
-				Email:   respond.Answer.Email,
-				Phone:   respond.Answer.Phone,
-				Job:     respond.Answer.Job,
-				PoP:     respond.Answer.PoP,
-				DoB:     respond.Answer.DoB,
-			})
+		participant := newRespondParticipant(event.ID, respond)
+		data, err := service.findRespondParticipant(ctx, event.ID, respond)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			// the high-water mark is not moved, so the respondent is fetched again
+			return err
 		}
+		if data == nil {
+			newParticipant = append(newParticipant, participant)
+			continue
+		}
+		participant.ID = data.ID
+		// the participant synced before the response is stored has nothing
+		// to be compared with, only backfill the response so the next edit
+		// is diffed, the approval state is kept.
+		if !data.LastSubmittedTime.Valid || data.LastSubmittedTime.String == "" {
+			if err := service.postgreSQLRepository.BackfillParticipantResponse(
+				ctx, participant,
+			); err != nil {
+				return err
+			}
+			continue
+		}
+		// the respondent edit their google form response,
+		// update the existing participant instead of ignoring it.
+		if !isNewerSubmission(data.LastSubmittedTime.String,
+			participant.LastSubmittedTime.String) {
+			continue
+		}
+		changes := diffParticipant(data, participant)
+		if err := service.postgreSQLRepository.UpdateParticipantResponse(
+			ctx, participant, changes,
+			service.resetStatusOnEdit && len(changes) > 0,
+			time.Now().Unix(),
+		); err != nil {
+			return err
+		}
+		updatedParticipant++
 	}
 
 	cacheKey := fmt.Sprintf("participants-%s", formID)
 	service.redisCache.Del(ctx, cacheKey)
-	return service.postgreSQLRepository.InsertManyParticipants(
-		ctx, newParticipant, time.Now().Unix())
+	if err := service.postgreSQLRepository.InsertManyParticipants(
+		ctx, newParticipant, time.Now().Unix(),
+	); err != nil {
+		return err
+	}
+
+	if len(newParticipant) > 0 || updatedParticipant > 0 {
+		service.notify(ctx, formID, common.NotificationParticipantsSynced, map[string]int{
+			"inserted": len(newParticipant),
+			"updated":  updatedParticipant,
+		})
+	}
+
+	// move the high-water mark forward, so the next sync
+	// only request responses submitted after this one.
+	if latest := latestSubmittedTime(respondents, lastSyncedAt); latest != lastSyncedAt {
+		return service.postgreSQLRepository.UpdateEventLastRespondSyncedAt(
+			ctx, event.ID, latest)
+	}
+
+	return nil
+}
+
+// findRespondParticipant look up the participant by google form response id,
+// falling back to the email for participants synced before the response id is stored.
+func (service *tixService) findRespondParticipant(
+	ctx context.Context,
+	eventID int32,
+	respond *response.GoogleFormRespond,
+) (*entity.Participant, error) {
+	if respond.RespondID != "" {
+		data, err := service.postgreSQLRepository.
+			GetParticipantByResponseIDAndEventID(ctx, respond.RespondID, eventID)
+		if err == nil || !errors.Is(err, sql.ErrNoRows) {
+			return data, err
+		}
+	}
+	return service.postgreSQLRepository.
+		GetParticipantByEmailAndEventID(ctx, respond.Answer.Email, eventID)
+}
+
+func newRespondParticipant(
+	eventID int32,
+	respond *response.GoogleFormRespond,
+) *entity.Participant {
+	participant := &entity.Participant{
+		EventID: eventID,
+		Name:    respond.Answer.Name,
+		Email:   respond.Answer.Email,
+		Phone:   respond.Answer.Phone,
+		Job:     respond.Answer.Job,
+		PoP:     respond.Answer.PoP,
+		DoB:     respond.Answer.DoB,
+		ResponseID: sql.NullString{
+			String: respond.RespondID,
+			Valid:  respond.RespondID != "",
+		},
+		LastSubmittedTime: sql.NullString{
+			String: respond.LastSubmittedTime,
+			Valid:  respond.LastSubmittedTime != "",
+		},
+	}
+	if len(respond.Answer.Custom) > 0 {
+		participant.CustomAnswers = entity.CustomAnswers{}
+		for _, custom := range respond.Answer.Custom {
+			participant.CustomAnswers[custom.QuestionID] = &entity.CustomAnswer{
+				Question: custom.Question,
+				Answer:   custom.Answer,
+				Position: custom.Position,
+			}
+		}
+	}
+	return participant
+}
+
+// diffParticipant list every field that differ between the stored
+// participant and the edited one, custom answers use the question title.
+func diffParticipant(
+	current, edited *entity.Participant,
+) (changes []*entity.ParticipantChange) {
+	addChange := func(field, oldValue, newValue string) {
+		if oldValue == newValue {
+			return
+		}
+		changes = append(changes, &entity.ParticipantChange{
+			EventID:       current.EventID,
+			ParticipantID: current.ID,
+			Field:         field,
+			OldValue:      oldValue,
+			NewValue:      newValue,
+		})
+	}
+	addChange(string(common.ParticipantFieldName), current.Name, edited.Name)
+	addChange(string(common.ParticipantFieldEmail), current.Email, edited.Email)
+	addChange(string(common.ParticipantFieldPhone), current.Phone, edited.Phone)
+	addChange(string(common.ParticipantFieldJob), current.Job, edited.Job)
+	addChange(string(common.ParticipantFieldDoB), current.DoB, edited.DoB)
+	addChange(string(common.ParticipantFieldPoP), current.PoP, edited.PoP)
+
+	questionIDs := make([]string, 0, len(current.CustomAnswers)+len(edited.CustomAnswers))
+	for questionID := range current.CustomAnswers {
+		questionIDs = append(questionIDs, questionID)
+	}
+	for questionID := range edited.CustomAnswers {
+		if _, ok := current.CustomAnswers[questionID]; !ok {
+			questionIDs = append(questionIDs, questionID)
+		}
+	}
+	sort.Strings(questionIDs)
+	for _, questionID := range questionIDs {
+		var question, oldValue, newValue string
+		if answer, ok := current.CustomAnswers[questionID]; ok {
+			question, oldValue = answer.Question, answer.Answer
+		}
+		if answer, ok := edited.CustomAnswers[questionID]; ok {
+			question, newValue = answer.Question, answer.Answer
+		}
+		addChange(question, oldValue, newValue)
+	}
+
+	return changes
+}
+
+func isNewerSubmission(stored, incoming string) bool {
+	storedTime, errStored := time.Parse(time.RFC3339Nano, stored)
+	incomingTime, errIncoming := time.Parse(time.RFC3339Nano, incoming)
+	if errStored != nil || errIncoming != nil {
+		return stored != incoming
+	}
+	return incomingTime.After(storedTime)
+}
+
+func latestSubmittedTime(
+	respondents []*response.GoogleFormRespond,
+	current string,
+) string {
+	latest := current
+	latestTime, _ := time.Parse(time.RFC3339Nano, current)
+	for _, respond := range respondents {
+		submittedAt, err := time.Parse(time.RFC3339Nano, respond.LastSubmittedTime)
+		if err != nil {
+			continue
+		}
+		if submittedAt.After(latestTime) {
+			latest, latestTime = respond.LastSubmittedTime, submittedAt
+		}
+	}
+	return latest
+}
+
+func (service *tixService) FetchFieldMapping(
+	ctx context.Context,
+	googleFormID string,
+) (item *response.EventFieldMappingResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := service.FetchForms(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFieldMappingResponse(googleFormID, questions, event.FieldMapping), nil
+}
+
+func (service *tixService) UpdateFieldMapping(
+	ctx context.Context,
+	googleFormID string,
+	form *request.EventRequestFieldMapping,
+) (item *response.EventFieldMappingResponse, err error) {
+	questions, err := service.FetchForms(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := entity.FieldMapping{}
+	boundFields := make(map[string]bool)
+	for questionID, field := range form.Mapping {
+		if field == "" {
+			continue
+		}
+		if !isParticipantField(field) {
+			return nil, common.ErrUnknownParticipantField
+		}
+		if boundFields[field] {
+			return nil, common.ErrDuplicateFieldMapping
+		}
+		if !hasFormQuestion(questions, questionID) {
+			return nil, common.ErrUnknownFormQuestion
+		}
+		boundFields[field] = true
+		mapping[questionID] = field
+	}
+
+	if err := service.postgreSQLRepository.UpdateEventFieldMapping(
+		ctx, googleFormID, mapping,
+	); err != nil {
+		return nil, err
+	}
+
+	return newFieldMappingResponse(googleFormID, questions, mapping), nil
+}
+
+// resolveFieldMapping returns the mapping stored for the event,
+// falling back to the question title heuristics when the event
+// is not stored yet or has no mapping configured.
+func (service *tixService) resolveFieldMapping(
+	ctx context.Context,
+	formID string,
+	questions []*response.GoogleFormQuestion,
+) entity.FieldMapping {
+	if event, err := service.postgreSQLRepository.GetEventByGoogleFormID(
+		ctx, formID,
+	); err == nil && event != nil && len(event.FieldMapping) > 0 {
+		return event.FieldMapping
+	}
+
+	return suggestFieldMapping(questions)
+}
+
+// formAnswerValue flatten the google form answer into a single string,
+// file uploads are converted into google drive links.
+func formAnswerValue(answer *forms.Answer) string {
+	var values []string
+	if answer.FileUploadAnswers != nil {
+		for _, file := range answer.FileUploadAnswers.Answers {
+			values = append(values, fmt.Sprintf(
+				"https://drive.google.com/uc?export=view&id=%s", file.FileId))
+		}
+	}
+	if answer.TextAnswers != nil {
+		for _, text := range answer.TextAnswers.Answers {
+			values = append(values, text.Value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func newFieldMappingResponse(
+	googleFormID string,
+	questions []*response.GoogleFormQuestion,
+	mapping entity.FieldMapping,
+) *response.EventFieldMappingResponse {
+	isSuggested := len(mapping) == 0
+	if isSuggested {
+		mapping = suggestFieldMapping(questions)
+	}
+
+	items := make([]*response.GoogleFormQuestion, 0, len(questions))
+	for _, question := range questions {
+		items = append(items, &response.GoogleFormQuestion{
+			ID:    question.ID,
+			Title: question.Title,
+			Field: mapping[question.ID],
+		})
+	}
+
+	return &response.EventFieldMappingResponse{
+		GoogleFormID: googleFormID,
+		IsSuggested:  isSuggested,
+		Questions:    items,
+	}
+}
+
+func suggestFieldMapping(
+	questions []*response.GoogleFormQuestion,
+) entity.FieldMapping {
+	mapping := entity.FieldMapping{}
+	for _, question := range questions {
+		field := question.Field
+		if field == "" {
+			field = string(suggestParticipantField(question.Title))
+		}
+		if field != "" {
+			mapping[question.ID] = field
+		}
+	}
+	return mapping
+}
+
+func suggestParticipantField(title string) common.ParticipantField {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(title)), " ", "_")
+	return common.ParticipantFieldTitleHints[key]
+}
+
+func isParticipantField(field string) bool {
+	for _, participantField := range common.ParticipantFields {
+		if string(participantField) == field {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFormQuestion(questions []*response.GoogleFormQuestion, questionID string) bool {
+	for _, question := range questions {
+		if question.ID == questionID {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"time"
+)
+
+// publishJob record the job as queued then publish it with the job id
+// in its payload, so the consumer can report the job lifecycle.
+func (service *tixService) publishJob(
+	ctx context.Context,
+	queue, googleFormID string,
+	payload map[string]any,
+) (int32, error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	job := &entity.Job{
+		EventID:  event.ID,
+		Queue:    queue,
+		Status:   string(common.JobStatusQueued),
+		Payload:  string(data),
+		QueuedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+	}
+	if job.ID, err = service.postgreSQLRepository.InsertJob(ctx, job); err != nil {
+		return 0, err
+	}
+
+	payload["job_id"] = job.ID
+	if data, err = json.Marshal(payload); err == nil {
+		_, err = service.jobQueue.Publish(ctx, queue, data)
+	}
+	if err != nil {
+		job.Status = string(common.JobStatusFailed)
+		job.Error = sql.NullString{String: err.Error(), Valid: true}
+		job.FinishedAt = sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+		_ = service.postgreSQLRepository.UpdateJob(ctx, job, time.Now().Unix())
+		return 0, err
+	}
+
+	return job.ID, nil
+}
+
+func (service *tixService) FetchJob(
+	ctx context.Context,
+	id int32,
+) (item *response.JobResponse, err error) {
+	job, err := service.postgreSQLRepository.GetJobByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return newJobResponse(job), nil
+}
+
+func (service *tixService) FetchEventJobs(
+	ctx context.Context,
+	googleFormID string,
+) (
+	items []*response.JobResponse,
+	err error,
+) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := service.postgreSQLRepository.GetJobsByEventID(
+		ctx, event.ID, common.JobHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		items = append(items, newJobResponse(job))
+	}
+
+	return items, nil
+}
+
+// UpdateJobStatus move the job to the given status, a job that failed
+// but will be retried goes back to queued and keep the error text.
+func (service *tixService) UpdateJobStatus(
+	ctx context.Context,
+	id int32,
+	status common.JobStatus,
+	attempt int,
+	result, errorText string,
+) error {
+	job, err := service.postgreSQLRepository.GetJobByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	job.Status = string(status)
+	if attempt > 0 {
+		job.Attempts = int32(attempt)
+	}
+	job.Error = sql.NullString{String: errorText, Valid: errorText != ""}
+	job.Result = sql.NullString{String: result, Valid: result != ""}
+	switch status {
+	case common.JobStatusRunning:
+		job.StartedAt = now
+		job.FinishedAt = sql.NullInt32{}
+	case common.JobStatusSucceeded, common.JobStatusFailed:
+		job.FinishedAt = now
+	case common.JobStatusQueued:
+		job.FinishedAt = sql.NullInt32{}
+	}
+
+	if err := service.postgreSQLRepository.UpdateJob(
+		ctx, job, time.Now().Unix(),
+	); err != nil {
+		return err
+	}
+
+	if status == common.JobStatusSucceeded || status == common.JobStatusFailed {
+		var payload struct {
+			GoogleFormID string `json:"google_form_id"`
+		}
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err == nil {
+			service.notify(ctx, payload.GoogleFormID,
+				common.NotificationJobFinished, newJobResponse(job))
+		}
+	}
+
+	return nil
+}
+
+func (service *tixService) FetchDeadLetterJobs(
+	ctx context.Context,
+	form *request.JobRequestDeadLetters,
+) (
+	item *response.DeadLetterJobPageResponse,
+	err error,
+) {
+	limit := form.Limit
+	if limit <= 0 || limit > common.DeadLetterMaxPageSize {
+		limit = common.DeadLetterPageSize
+	}
+	jobs, err := service.jobQueue.ListDeadLetters(ctx, form.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	item = &response.DeadLetterJobPageResponse{
+		Items: make([]*response.DeadLetterJobResponse, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		item.Items = append(item.Items, newDeadLetterJobResponse(job))
+	}
+	// a full page may be followed by the older jobs
+	if int64(len(jobs)) == limit {
+		item.NextCursor = jobs[len(jobs)-1].ID
+	}
+
+	return item, nil
+}
+
+func (service *tixService) FetchDeadLetterJob(
+	ctx context.Context,
+	id string,
+) (item *response.DeadLetterJobResponse, err error) {
+	job, err := service.jobQueue.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDeadLetterJobResponse(job), nil
+}
+
+func (service *tixService) RetryDeadLetterJob(
+	ctx context.Context,
+	id string,
+) (jobID string, err error) {
+	job, err := service.jobQueue.GetDeadLetter(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	// the tracked job (if any) is queued again before it is published,
+	// so the status reported by the consumer is never overwritten
+	var payload struct {
+		JobID int32 `json:"job_id"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.JobID == 0 {
+		return service.jobQueue.RetryDeadLetter(ctx, id)
+	}
+	if err := service.UpdateJobStatus(ctx, payload.JobID, common.JobStatusQueued, 0, "", ""); err != nil {
+		return "", err
+	}
+
+	if jobID, err = service.jobQueue.RetryDeadLetter(ctx, id); err != nil {
+		_ = service.UpdateJobStatus(ctx, payload.JobID, common.JobStatusFailed, 0, "", err.Error())
+		return "", err
+	}
+
+	return jobID, nil
+}
+
+func (service *tixService) DiscardDeadLetterJob(
+	ctx context.Context,
+	id string,
+) error {
+	return service.jobQueue.DiscardDeadLetter(ctx, id)
+}
+
+func newDeadLetterJobResponse(
+	job *entity.DeadLetterJob,
+) *response.DeadLetterJobResponse {
+	return &response.DeadLetterJobResponse{
+		ID:       job.ID,
+		Queue:    job.Queue,
+		Payload:  string(job.Payload),
+		Attempts: job.Attempts,
+		Error:    job.Error,
+		FailedAt: job.FailedAt,
+	}
+}
+
+func newJobResponse(job *entity.Job) *response.JobResponse {
+	return &response.JobResponse{
+		ID:       job.ID,
+		EventID:  job.EventID,
+		Queue:    job.Queue,
+		Status:   job.Status,
+		Attempts: job.Attempts,
+		Error:    job.Error.String,
+		Result:   job.Result.String,
+		QueuedAt: job.QueuedAt.Int32,
+		StartedAt: func() *int32 {
+			if job.StartedAt.Valid {
+				return &job.StartedAt.Int32
+			}
+			return nil
+		}(),
+		FinishedAt: func() *int32 {
+			if job.FinishedAt.Valid {
+				return &job.FinishedAt.Int32
+			}
+			return nil
+		}(),
+	}
+}
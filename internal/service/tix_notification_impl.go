@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"time"
+)
+
+// SubscribeEventNotifications listen to the event notifications published
+// through redis by every app instance, the returned channel is closed
+// once the context is done.
+func (service *tixService) SubscribeEventNotifications(
+	ctx context.Context,
+	googleFormID string,
+) (<-chan *response.EventNotificationResponse, error) {
+	if _, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID); err != nil {
+		return nil, err
+	}
+
+	pubSub := service.redisCache.Subscribe(ctx, eventNotificationChannel(googleFormID))
+	// wait for the subscription to be confirmed, so nothing published
+	// after this function return is missed.
+	if _, err := pubSub.Receive(ctx); err != nil {
+		_ = pubSub.Close()
+		return nil, err
+	}
+
+	notifications := make(chan *response.EventNotificationResponse, common.EventStreamBufferSize)
+	go func() {
+		defer close(notifications)
+		defer func() { _ = pubSub.Close() }()
+		messages := pubSub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var notification response.EventNotificationResponse
+				if err := json.Unmarshal([]byte(message.Payload), &notification); err != nil {
+					continue
+				}
+				select {
+				case notifications <- &notification:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return notifications, nil
+}
+
+// notify publish the event notification, it is best effort
+// since the admin can always refresh the data manually.
+func (service *tixService) notify(
+	ctx context.Context,
+	googleFormID string,
+	notificationType common.EventNotificationType,
+	data any,
+) {
+	payload, err := json.Marshal(&response.EventNotificationResponse{
+		Type:         string(notificationType),
+		GoogleFormID: googleFormID,
+		Data:         data,
+		CreatedAt:    time.Now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+	_ = service.redisCache.Publish(ctx, eventNotificationChannel(googleFormID), payload).Err()
+}
+
+func eventNotificationChannel(googleFormID string) string {
+	return fmt.Sprintf("%s-%s", common.EventNotificationChannelKey, googleFormID)
+}
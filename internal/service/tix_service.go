@@ -1,9 +1,16 @@
 package service
 
 import (
+	"context"
+	"github.com/aasumitro/tix/common"
 	"github.com/aasumitro/tix/internal/domain"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/aasumitro/tix/pkg/wallet"
 	"github.com/redis/go-redis/v9"
 	"gopkg.in/gomail.v2"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -14,6 +21,16 @@ type tixService struct {
 	authRESTRepository      domain.IAuthRESTRepository
 	postgreSQLRepository    domain.IPostgreSQLRepository
 	mailer                  *gomail.Dialer
+	jobQueue                domain.IJobQueue
+	resetStatusOnEdit       bool
+	ticketSigningSecret     string
+	walletPass              *wallet.Signer
+	publicURL               string
+	fileStore               storage.FileStore
+	exportAttachmentMaxSize int64
+	// tempFiles is the name of the files this process saved in the
+	// export directory and has not removed yet, see Shutdown.
+	tempFiles sync.Map
 }
 
 type TixOptions func(*tixService)
@@ -56,12 +73,115 @@ func WithMailer(mailer *gomail.Dialer) TixOptions {
 	}
 }
 
+func WithJobQueue(jobQueue domain.IJobQueue) TixOptions {
+	return func(service *tixService) {
+		service.jobQueue = jobQueue
+	}
+}
+
+// WithResetStatusOnEdit clear the approval state of a participant
+// when their google form response is edited after being reviewed.
+func WithResetStatusOnEdit(reset bool) TixOptions {
+	return func(service *tixService) {
+		service.resetStatusOnEdit = reset
+	}
+}
+
+// WithTicketSigningSecret enable the signed ticket token in the ticket
+// QR code, the per event signing key is derived from the secret.
+func WithTicketSigningSecret(secret string) TixOptions {
+	return func(service *tixService) {
+		service.ticketSigningSecret = secret
+	}
+}
+
+// WithWalletPassSigner attach the wallet pass to the ticket email.
+func WithWalletPassSigner(signer *wallet.Signer) TixOptions {
+	return func(service *tixService) {
+		service.walletPass = signer
+	}
+}
+
+// WithPublicURL set the base url of the links sent to the participants.
+func WithPublicURL(publicURL string) TixOptions {
+	return func(service *tixService) {
+		service.publicURL = strings.TrimSuffix(publicURL, "/")
+	}
+}
+
+// WithFileStore archive the exports in the file store, the export too
+// large to be attached to the email is sent as its download link.
+func WithFileStore(store storage.FileStore) TixOptions {
+	return func(service *tixService) {
+		service.fileStore = store
+	}
+}
+
+// WithExportAttachmentMaxSize set the largest export (in bytes) attached
+// to the email, see common.ExportAttachmentMaxSize.
+func WithExportAttachmentMaxSize(size int64) TixOptions {
+	return func(service *tixService) {
+		if size > 0 {
+			service.exportAttachmentMaxSize = size
+		}
+	}
+}
+
 func NewTixService(
 	options ...TixOptions,
 ) domain.ITixService {
-	service := &tixService{}
+	service := &tixService{
+		exportAttachmentMaxSize: common.ExportAttachmentMaxSize,
+	}
 	for _, option := range options {
 		option(service)
 	}
 	return service
 }
+
+// saveTempFile record the file saved in the export directory,
+// so it is removed on shutdown when it is not sent.
+func (service *tixService) saveTempFile(name string) {
+	service.tempFiles.Store(filepath.Base(name), struct{}{})
+}
+
+// removeTempFile remove the file saved in the export directory.
+func (service *tixService) removeTempFile(name string) error {
+	name = filepath.Base(name)
+	if err := os.Remove(filepath.Join(common.ExportTempDir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	service.tempFiles.Delete(name)
+	return nil
+}
+
+// Shutdown wait for the running export or ticket generation (they hold
+// the service lock) to finish, then remove the files this process left
+// in the export directory, e.g. by an export interrupted before its email
+// is sent. The directory is shared with the other instances, so their
+// files are kept, and nothing is removed while a job is still running.
+func (service *tixService) Shutdown(ctx context.Context) error {
+	locked := make(chan struct{})
+	go func() {
+		service.mu.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		defer service.mu.Unlock()
+	case <-ctx.Done():
+		// still running past the deadline, its files may still be in use.
+		return ctx.Err()
+	}
+
+	var err error
+	service.tempFiles.Range(func(name, _ any) bool {
+		err = service.removeTempFile(name.(string))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
@@ -1,27 +1,56 @@
 package service_test
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain"
 	"github.com/aasumitro/tix/internal/domain/entity"
 	"github.com/aasumitro/tix/internal/domain/request"
 	"github.com/aasumitro/tix/internal/domain/response"
 	"github.com/aasumitro/tix/internal/service"
 	"github.com/aasumitro/tix/mocks"
+	"github.com/aasumitro/tix/pkg/i18n"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/aasumitro/tix/pkg/token"
+	"github.com/aasumitro/tix/pkg/wallet"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
 	"google.golang.org/api/forms/v1"
 	"gopkg.in/gomail.v2"
+	"image"
+	"image/png"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -367,7 +396,7 @@ func (s *tixServiceTestSuite) Test_FetchResponds_ShouldSuccess() {
 			},
 		},
 	}, nil).Once()
-	gsRepo.On("GetResponses", mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
+	gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
 		Responses: []*forms.FormResponse{{
 			Answers: map[string]forms.Answer{
 				"pekerjaan": {
@@ -433,9 +462,46 @@ func (s *tixServiceTestSuite) Test_FetchResponds_ShouldSuccess() {
 			},
 		}},
 	}, nil).Once()
-	items, err := svc.FetchResponds(context.TODO(), "asd")
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+	items, err := svc.FetchResponds(context.TODO(), "asd", "")
 	s.NotNil(items)
 	s.Nil(err)
+	s.Equal("hello", items[0].Answer.Name)
+	s.Equal("hello@hello.id", items[0].Answer.Email)
+	s.Equal("https://drive.google.com/uc?export=view&id=080888982828", items[0].Answer.PoP)
+	s.T().Run("with stored field mapping", func(t *testing.T) {
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
+			Responses: []*forms.FormResponse{{
+				Answers: map[string]forms.Answer{
+					"1": {
+						QuestionId: "1",
+						TextAnswers: &forms.TextAnswers{
+							Answers: []*forms.TextAnswer{{Value: "hello"}},
+						},
+					},
+					"2": {
+						QuestionId: "2",
+						TextAnswers: &forms.TextAnswers{
+							Answers: []*forms.TextAnswer{{Value: "lorem"}, {Value: "ipsum"}},
+						},
+					},
+				},
+			}},
+		}, nil).Once()
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
+			ID:           1,
+			FieldMapping: entity.FieldMapping{"1": string(common.ParticipantFieldName)},
+		}, nil).Once()
+		items, err := svc.FetchResponds(context.TODO(), "asd", "")
+		s.Nil(err)
+		s.Equal("hello", items[0].Answer.Name)
+		s.Empty(items[0].Answer.Job)
+		s.Len(items[0].Answer.Custom, 1)
+		s.Equal("tanggal_lahir", items[0].Answer.Custom[0].Question)
+		s.Equal("lorem, ipsum", items[0].Answer.Custom[0].Answer)
+	})
+	pqRepo.AssertExpectations(s.T())
+	gsRepo.AssertExpectations(s.T())
 }
 func (s *tixServiceTestSuite) Test_FetchResponds_ShouldError() {
 	pqRepo := new(mocks.IPostgreSQLRepository)
@@ -458,8 +524,8 @@ func (s *tixServiceTestSuite) Test_FetchResponds_ShouldError() {
 			},
 		}},
 	}, nil).Once()
-	gsRepo.On("GetResponses", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-	items, err := svc.FetchResponds(context.TODO(), "asd")
+	gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+	items, err := svc.FetchResponds(context.TODO(), "asd", "")
 	s.Nil(items)
 	s.NotNil(err)
 }
@@ -474,7 +540,13 @@ func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldSuccess() {
 		service.WithPostgreSQLRepository(pqRepo),
 		service.WithGoogleServiceRepository(gsRepo),
 		service.WithRedisCache(rc))
-	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
+		ID: 1,
+		LastRespondSyncedAt: sql.NullString{
+			String: "2023-06-05T10:00:00Z",
+			Valid:  true,
+		},
+	}, nil).Twice()
 	pqRepo.On("GetParticipantByEmailAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
 	gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{
 		FormId: "asd",
@@ -529,8 +601,9 @@ func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldSuccess() {
 			},
 		},
 	}, nil).Once()
-	gsRepo.On("GetResponses", mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
+	gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
 		Responses: []*forms.FormResponse{{
+			LastSubmittedTime: "2023-06-06T10:00:00.123Z",
 			Answers: map[string]forms.Answer{
 				"pekerjaan": {
 					QuestionId: "1",
@@ -596,8 +669,11 @@ func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldSuccess() {
 		}},
 	}, nil).Once()
 	pqRepo.On("InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	pqRepo.On("UpdateEventLastRespondSyncedAt", mock.Anything, int32(1), "2023-06-06T10:00:00.123Z").Return(nil).Once()
 	err := svc.SyncRespondData(context.TODO(), "asd")
 	s.Nil(err)
+	gsRepo.AssertCalled(s.T(), "GetResponses", mock.Anything, "asd", "2023-06-05T10:00:00Z")
+	pqRepo.AssertExpectations(s.T())
 }
 func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldError() {
 	pqRepo := new(mocks.IPostgreSQLRepository)
@@ -620,6 +696,345 @@ func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldError() {
 		err := svc.SyncRespondData(context.TODO(), "asd")
 		s.NotNil(err)
 	})
+	s.T().Run("error insert participants", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{FormId: "asd"}, nil).Once()
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
+			Responses: []*forms.FormResponse{{LastSubmittedTime: "2023-06-06T10:00:00Z"}},
+		}, nil).Once()
+		pqRepo.On("GetParticipantByEmailAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+		pqRepo.On("InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
+		err := svc.SyncRespondData(context.TODO(), "asd")
+		s.NotNil(err)
+		pqRepo.AssertNotCalled(t, "UpdateEventLastRespondSyncedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+	s.T().Run("error find participant", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		gsRepo := new(mocks.IGoogleServiceRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithGoogleServiceRepository(gsRepo),
+			service.WithRedisCache(rc))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{FormId: "asd"}, nil).Once()
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(&forms.ListFormResponsesResponse{
+			Responses: []*forms.FormResponse{{LastSubmittedTime: "2023-06-07T10:00:00Z"}},
+		}, nil).Once()
+		pqRepo.On("GetParticipantByEmailAndEventID", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("lorem")).Once()
+		err := svc.SyncRespondData(context.TODO(), "asd")
+		s.NotNil(err)
+		pqRepo.AssertNotCalled(t, "InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything)
+		pqRepo.AssertNotCalled(t, "UpdateEventLastRespondSyncedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_SyncRespondData_ShouldUpdateEditedRespond() {
+	form := &forms.Form{
+		FormId: "asd",
+		Items: []*forms.Item{
+			{Title: "email", QuestionItem: &forms.QuestionItem{Question: &forms.Question{QuestionId: "1"}}},
+			{Title: "nomor_telepon", QuestionItem: &forms.QuestionItem{Question: &forms.Question{QuestionId: "2"}}},
+			{Title: "ukuran baju", QuestionItem: &forms.QuestionItem{Question: &forms.Question{QuestionId: "3"}}},
+		},
+	}
+	responses := &forms.ListFormResponsesResponse{
+		Responses: []*forms.FormResponse{{
+			ResponseId:        "ACYDBNi84NuJUO",
+			LastSubmittedTime: "2023-06-06T10:00:00Z",
+			Answers: map[string]forms.Answer{
+				"1": {QuestionId: "1", TextAnswers: &forms.TextAnswers{Answers: []*forms.TextAnswer{{Value: "hello@hello.id"}}}},
+				"2": {QuestionId: "2", TextAnswers: &forms.TextAnswers{Answers: []*forms.TextAnswer{{Value: "08111111111"}}}},
+				"3": {QuestionId: "3", TextAnswers: &forms.TextAnswers{Answers: []*forms.TextAnswer{{Value: "XL"}}}},
+			},
+		}},
+	}
+	stored := func() *entity.Participant {
+		return &entity.Participant{
+			ID:      7,
+			EventID: 1,
+			Email:   "hello@hello.id",
+			Phone:   "080888982828",
+			CustomAnswers: entity.CustomAnswers{
+				"3": {Question: "ukuran baju", Answer: "L", Position: 2},
+			},
+			ResponseID:        sql.NullString{String: "ACYDBNi84NuJUO", Valid: true},
+			LastSubmittedTime: sql.NullString{String: "2023-06-05T10:00:00Z", Valid: true},
+		}
+	}
+	matchChanges := mock.MatchedBy(func(changes []*entity.ParticipantChange) bool {
+		return len(changes) == 2 &&
+			changes[0].Field == "phone" && changes[0].OldValue == "080888982828" &&
+			changes[0].NewValue == "08111111111" && changes[0].ParticipantID == 7 &&
+			changes[1].Field == "ukuran baju" && changes[1].OldValue == "L" && changes[1].NewValue == "XL"
+	})
+	matchParticipant := mock.MatchedBy(func(participant *entity.Participant) bool {
+		return participant.ID == 7 && participant.Phone == "08111111111" &&
+			participant.LastSubmittedTime.String == "2023-06-06T10:00:00Z"
+	})
+	for _, tc := range []struct {
+		name        string
+		resetStatus bool
+	}{
+		{name: "keep approval state", resetStatus: false},
+		{name: "reset approval state", resetStatus: true},
+	} {
+		s.T().Run(tc.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			gsRepo := new(mocks.IGoogleServiceRepository)
+			rc := redis.NewClient(&redis.Options{
+				Addr: miniredis.RunT(t).Addr(),
+			})
+			svc := service.NewTixService(
+				service.WithPostgreSQLRepository(pqRepo),
+				service.WithGoogleServiceRepository(gsRepo),
+				service.WithRedisCache(rc),
+				service.WithResetStatusOnEdit(tc.resetStatus))
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+			gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(form, nil).Once()
+			gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(responses, nil).Once()
+			pqRepo.On("GetParticipantByResponseIDAndEventID", mock.Anything, "ACYDBNi84NuJUO", int32(1)).Return(stored(), nil).Once()
+			pqRepo.On("UpdateParticipantResponse", mock.Anything, matchParticipant, matchChanges, tc.resetStatus, mock.Anything).Return(nil).Once()
+			pqRepo.On("InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			pqRepo.On("UpdateEventLastRespondSyncedAt", mock.Anything, int32(1), "2023-06-06T10:00:00Z").Return(nil).Once()
+			err := svc.SyncRespondData(context.TODO(), "asd")
+			s.Nil(err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+	s.T().Run("skip unchanged respond", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		gsRepo := new(mocks.IGoogleServiceRepository)
+		rc := redis.NewClient(&redis.Options{
+			Addr: miniredis.RunT(t).Addr(),
+		})
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithGoogleServiceRepository(gsRepo),
+			service.WithRedisCache(rc))
+		participant := stored()
+		participant.LastSubmittedTime.String = "2023-06-06T10:00:00Z"
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(form, nil).Once()
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(responses, nil).Once()
+		pqRepo.On("GetParticipantByResponseIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(participant, nil).Once()
+		pqRepo.On("InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		pqRepo.On("UpdateEventLastRespondSyncedAt", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		err := svc.SyncRespondData(context.TODO(), "asd")
+		s.Nil(err)
+		pqRepo.AssertNotCalled(t, "UpdateParticipantResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	s.T().Run("fallback to email and backfill the response", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		gsRepo := new(mocks.IGoogleServiceRepository)
+		rc := redis.NewClient(&redis.Options{
+			Addr: miniredis.RunT(t).Addr(),
+		})
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithGoogleServiceRepository(gsRepo),
+			service.WithRedisCache(rc))
+		participant := stored()
+		participant.ResponseID = sql.NullString{}
+		participant.LastSubmittedTime = sql.NullString{}
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(form, nil).Once()
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(responses, nil).Once()
+		pqRepo.On("GetParticipantByResponseIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+		pqRepo.On("GetParticipantByEmailAndEventID", mock.Anything, "hello@hello.id", int32(1)).Return(participant, nil).Once()
+		// the participant synced before the response is stored is not diffed
+		pqRepo.On("BackfillParticipantResponse", mock.Anything, mock.MatchedBy(func(participant *entity.Participant) bool {
+			return participant.ID == 7 && participant.ResponseID.String == "ACYDBNi84NuJUO" &&
+				participant.LastSubmittedTime.String == "2023-06-06T10:00:00Z" &&
+				participant.CustomAnswers["3"].Answer == "XL"
+		})).Return(nil).Once()
+		pqRepo.On("InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		pqRepo.On("UpdateEventLastRespondSyncedAt", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		err := svc.SyncRespondData(context.TODO(), "asd")
+		s.Nil(err)
+		pqRepo.AssertExpectations(t)
+		pqRepo.AssertNotCalled(t, "UpdateParticipantResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	s.T().Run("error update participant", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		gsRepo := new(mocks.IGoogleServiceRepository)
+		rc := redis.NewClient(&redis.Options{
+			Addr: miniredis.RunT(t).Addr(),
+		})
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithGoogleServiceRepository(gsRepo),
+			service.WithRedisCache(rc))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Twice()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(form, nil).Once()
+		gsRepo.On("GetResponses", mock.Anything, mock.Anything, mock.Anything).Return(responses, nil).Once()
+		pqRepo.On("GetParticipantByResponseIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(stored(), nil).Once()
+		pqRepo.On("UpdateParticipantResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
+		err := svc.SyncRespondData(context.TODO(), "asd")
+		s.NotNil(err)
+		pqRepo.AssertNotCalled(t, "InsertManyParticipants", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_FetchFieldMapping_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	gsRepo := new(mocks.IGoogleServiceRepository)
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithGoogleServiceRepository(gsRepo),
+		service.WithRedisCache(rc))
+	gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{
+		FormId: "asd",
+		Items: []*forms.Item{
+			{
+				Title: "Full Name",
+				QuestionItem: &forms.QuestionItem{
+					Question: &forms.Question{QuestionId: "1"},
+				},
+			},
+			{
+				Title: "Company",
+				QuestionItem: &forms.QuestionItem{
+					Question: &forms.Question{QuestionId: "2"},
+				},
+			},
+		},
+	}, nil).Once()
+	s.T().Run("suggested mapping", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		item, err := svc.FetchFieldMapping(context.TODO(), "asd")
+		s.Nil(err)
+		s.True(item.IsSuggested)
+		s.Equal(string(common.ParticipantFieldName), item.Questions[0].Field)
+		s.Empty(item.Questions[1].Field)
+	})
+	s.T().Run("stored mapping", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
+			ID:           1,
+			FieldMapping: entity.FieldMapping{"2": string(common.ParticipantFieldJob)},
+		}, nil).Once()
+		item, err := svc.FetchFieldMapping(context.TODO(), "asd")
+		s.Nil(err)
+		s.False(item.IsSuggested)
+		s.Empty(item.Questions[0].Field)
+		s.Equal(string(common.ParticipantFieldJob), item.Questions[1].Field)
+	})
+	pqRepo.AssertExpectations(s.T())
+	gsRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_FetchFieldMapping_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	gsRepo := new(mocks.IGoogleServiceRepository)
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithGoogleServiceRepository(gsRepo),
+		service.WithRedisCache(rc))
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		item, err := svc.FetchFieldMapping(context.TODO(), "asd")
+		s.Nil(item)
+		s.NotNil(err)
+	})
+	s.T().Run("error get form", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		item, err := svc.FetchFieldMapping(context.TODO(), "asd")
+		s.Nil(item)
+		s.NotNil(err)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_UpdateFieldMapping_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	gsRepo := new(mocks.IGoogleServiceRepository)
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithGoogleServiceRepository(gsRepo),
+		service.WithRedisCache(rc))
+	gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{
+		FormId: "asd",
+		Items: []*forms.Item{{
+			Title: "Your Name",
+			QuestionItem: &forms.QuestionItem{
+				Question: &forms.Question{QuestionId: "1"},
+			},
+		}},
+	}, nil).Once()
+	pqRepo.On("UpdateEventFieldMapping", mock.Anything, "asd", entity.FieldMapping{"1": "name"}).Return(nil).Once()
+	item, err := svc.UpdateFieldMapping(context.TODO(), "asd", &request.EventRequestFieldMapping{
+		Mapping: map[string]string{"1": "name"},
+	})
+	s.Nil(err)
+	s.False(item.IsSuggested)
+	s.Equal("name", item.Questions[0].Field)
+	pqRepo.AssertExpectations(s.T())
+	gsRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_UpdateFieldMapping_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	gsRepo := new(mocks.IGoogleServiceRepository)
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithGoogleServiceRepository(gsRepo),
+		service.WithRedisCache(rc))
+	gsRepo.On("GetEvent", mock.Anything, mock.Anything).Return(&forms.Form{
+		FormId: "asd",
+		Items: []*forms.Item{
+			{
+				Title: "Your Name",
+				QuestionItem: &forms.QuestionItem{
+					Question: &forms.Question{QuestionId: "1"},
+				},
+			},
+			{
+				Title: "Your Nickname",
+				QuestionItem: &forms.QuestionItem{
+					Question: &forms.Question{QuestionId: "2"},
+				},
+			},
+		},
+	}, nil).Once()
+	s.T().Run("error unknown field", func(t *testing.T) {
+		item, err := svc.UpdateFieldMapping(context.TODO(), "asd", &request.EventRequestFieldMapping{
+			Mapping: map[string]string{"1": "lorem"},
+		})
+		s.Nil(item)
+		s.Equal(common.ErrUnknownParticipantField, err)
+	})
+	s.T().Run("error duplicate field", func(t *testing.T) {
+		item, err := svc.UpdateFieldMapping(context.TODO(), "asd", &request.EventRequestFieldMapping{
+			Mapping: map[string]string{"1": "name", "2": "name"},
+		})
+		s.Nil(item)
+		s.Equal(common.ErrDuplicateFieldMapping, err)
+	})
+	s.T().Run("error unknown question", func(t *testing.T) {
+		item, err := svc.UpdateFieldMapping(context.TODO(), "asd", &request.EventRequestFieldMapping{
+			Mapping: map[string]string{"3": "name"},
+		})
+		s.Nil(item)
+		s.Equal(common.ErrUnknownFormQuestion, err)
+	})
+	s.T().Run("error update mapping", func(t *testing.T) {
+		pqRepo.On("UpdateEventFieldMapping", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("lorem")).Once()
+		item, err := svc.UpdateFieldMapping(context.TODO(), "asd", &request.EventRequestFieldMapping{
+			Mapping: map[string]string{"1": "name"},
+		})
+		s.Nil(item)
+		s.NotNil(err)
+	})
 }
 
 // TIX EXPORT IMPL
@@ -627,7 +1042,7 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 	pqRepo := new(mocks.IPostgreSQLRepository)
 	svc := service.NewTixService(
 		service.WithPostgreSQLRepository(pqRepo),
-		service.WithMailer(&gomail.Dialer{}))
+		service.WithMailer(newSMTPServer(s.T())))
 
 	s.T().Run("success excel", func(t *testing.T) {
 		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
@@ -668,6 +1083,10 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 				String: "asd",
 				Valid:  true,
 			},
+			CustomAnswers: entity.CustomAnswers{
+				"7": {Question: "Company", Answer: "BAKODE", Position: 7},
+				"8": {Question: "Size", Answer: "XL", Position: 8},
+			},
 			CreatedAt: sql.NullInt32{
 				Int32: int32(time.Now().Unix()),
 				Valid: true,
@@ -680,9 +1099,15 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
-
-		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeXLS), "asd")
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
+		if err := os.MkdirAll("./temps/exports/", os.ModePerm); err != nil {
+			s.T().Fatalf("Failed to create directory: %s", err)
+		}
+		defer func() { _ = os.RemoveAll("./temps") }()
+		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeXLS), "admin@tix.id", &request.EventRequestExport{})
 		s.Nil(err)
+		// the email is sent, so the export is removed
+		s.NoFileExists("./temps/exports/asd.xlsx")
 		pqRepo.AssertExpectations(t)
 	})
 
@@ -725,6 +1150,10 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 				String: "asd",
 				Valid:  true,
 			},
+			CustomAnswers: entity.CustomAnswers{
+				"7": {Question: "Company", Answer: "BAKODE", Position: 7},
+				"8": {Question: "Size", Answer: "XL", Position: 8},
+			},
 			CreatedAt: sql.NullInt32{
 				Int32: int32(time.Now().Unix()),
 				Valid: true,
@@ -737,6 +1166,7 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		dir := "./temps/exports/"
 		filename := "asd.pdf"
 		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
@@ -748,7 +1178,7 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 			s.T().Fatalf("Failed to create file: %s", err)
 		}
 		defer func() { _ = file.Close() }()
-		errSvc := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd")
+		errSvc := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "admin@tix.id", &request.EventRequestExport{})
 		s.Nil(errSvc)
 		if err = os.RemoveAll("./temps"); err != nil {
 			s.T().Fatalf("Failed to remove directory: %s", err)
@@ -756,97 +1186,477 @@ func (s *tixServiceTestSuite) Test_ExportEvent_ShouldSuccess() {
 		pqRepo.AssertExpectations(t)
 	})
 }
-func (s *tixServiceTestSuite) Test_ExportEvent_ShouldError() {
-	pqRepo := new(mocks.IPostgreSQLRepository)
-	svc := service.NewTixService(
-		service.WithPostgreSQLRepository(pqRepo))
-	s.T().Run("error get event", func(t *testing.T) {
-		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd")
-		s.NotNil(err)
-		pqRepo.AssertExpectations(t)
-	})
-	s.T().Run("error get participant", func(t *testing.T) {
-		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
-			ID:                1,
-			GoogleFormID:      "asd",
-			Name:              "asd",
-			Location:          "asd",
-			PreregisterDate:   int32(time.Now().Unix()),
-			EventDate:         int32(time.Now().Unix()),
-			TotalParticipants: 1,
-			CreatedAt: sql.NullInt32{
-				Int32: int32(time.Now().Unix()),
-				Valid: true,
-			},
-			UpdatedAt: sql.NullInt32{
-				Int32: int32(time.Now().Unix()),
-				Valid: true,
+func (s *tixServiceTestSuite) Test_ExportEvent_TextFormats() {
+	approvedAt := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	participants := []*entity.Participant{
+		{
+			ID: 1, EventID: 1, Name: "asd", Email: "asd@asd.id", Phone: "123", Job: "asd", DoB: "asd",
+			ApprovedAt:  approvedAt,
+			CheckedInAt: approvedAt,
+			CustomAnswers: entity.CustomAnswers{
+				"7": {Question: "Company", Answer: "BAKODE", Position: 7},
+				// another question with the same title
+				"9": {Question: "Company", Answer: `=HYPERLINK("https://lorem.id")`, Position: 9},
 			},
-		}, nil).Once()
-		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd")
-		s.NotNil(err)
-		pqRepo.AssertExpectations(t)
-	})
-}
-
-// TIX GENERATE IMPL
-func (s *tixServiceTestSuite) Test_GenerateTicket_ShouldSuccess() {
-	pqRepo := new(mocks.IPostgreSQLRepository)
-	svc := service.NewTixService(
-		service.WithPostgreSQLRepository(pqRepo),
-		service.WithMailer(&gomail.Dialer{}))
-	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
-		ID:                1,
-		GoogleFormID:      "asd",
-		Name:              "asd",
-		Location:          "asd",
-		PreregisterDate:   int32(time.Now().Unix()),
-		EventDate:         int32(time.Now().Unix()),
-		TotalParticipants: 1,
-		CreatedAt: sql.NullInt32{
-			Int32: int32(time.Now().Unix()),
-			Valid: true,
 		},
-		UpdatedAt: sql.NullInt32{
-			Int32: int32(time.Now().Unix()),
-			Valid: true,
+		{
+			ID: 2, EventID: 1, Name: "Dédé, Jr", Email: "dede@asd.id", Phone: "+6281",
+			DeclinedAt:     approvedAt,
+			DeclinedReason: sql.NullString{String: "full", Valid: true},
 		},
-	}, nil).Once()
-	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
-		ID:    1,
-		Name:  "lorem",
-		Email: "lorem@lorem.id",
-	}, nil).Once()
+	}
 	dir := "./temps/exports/"
-	filename := "gen11tix.pdf"
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		s.T().Fatalf("Failed to create directory: %s", err)
 	}
-	filePath := filepath.Join(dir, filename)
-	file, err := os.Create(filePath)
-	if err != nil {
-		s.T().Fatalf("Failed to create file: %s", err)
-	}
-	defer func() { _ = file.Close() }()
-	errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
-	s.Nil(errSvc)
-	if err = os.RemoveAll("./temps"); err != nil {
-		s.T().Fatalf("Failed to remove directory: %s", err)
+	defer func() { _ = os.RemoveAll("./temps") }()
+	export := func(t *testing.T, exportType string, withBOM bool) []byte {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(&gomail.Dialer{}))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", TotalParticipants: 2,
+		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+			Return(participants, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(1).Times(4)
+		s.ErrorContains(svc.ExportEvent(context.TODO(), "asd", exportType, "asd",
+			&request.EventRequestExport{BOM: withBOM}), "could not send export email")
+		pqRepo.AssertExpectations(t)
+		// the email is not sent, so the export is kept
+		data, err := os.ReadFile(filepath.Join(dir, "asd."+exportType))
+		s.Nil(err)
+		return data
 	}
-	pqRepo.AssertExpectations(s.T())
+
+	s.T().Run("csv", func(t *testing.T) {
+		data := export(t, string(common.ExportTypeCSV), false)
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		s.Nil(err)
+		s.Equal([]string{"No", "Nama", "Email", "No Telp.", "Pekerjaan", "Tanggal Lahir",
+			"Diterima", "Ditolak", "Alasan Ditolak", "Hadir", "Status", "Company", "Company"}, rows[0])
+		// the cell read as a formula is escaped
+		s.Equal([]string{"1", "asd", "asd@asd.id", "123", "asd", "asd",
+			common.SymCheck, common.SymDash, common.SymDash, common.SymCheck, "hadir", "BAKODE",
+			`'=HYPERLINK("https://lorem.id")`}, rows[1])
+		s.Equal([]string{"2", "Dédé, Jr", "dede@asd.id", "'+6281", "", "",
+			common.SymDash, common.SymCheck, "full", common.SymDash, "ditolak", common.SymDash, common.SymDash}, rows[2])
+	})
+	s.T().Run("csv with bom", func(t *testing.T) {
+		data := export(t, string(common.ExportTypeCSV), true)
+		s.True(bytes.HasPrefix(data, []byte("\xEF\xBB\xBFNo,Nama,")))
+	})
+	s.T().Run("json", func(t *testing.T) {
+		data := export(t, string(common.ExportTypeJSON), false)
+		var got struct {
+			GoogleFormID      string `json:"google_form_id"`
+			TotalParticipants int    `json:"total_participants"`
+			TotalCheckedIn    int    `json:"total_checked_in"`
+			Participants      []struct {
+				No        int    `json:"no"`
+				Name      string `json:"name"`
+				Approved  bool   `json:"approved"`
+				CheckedIn bool   `json:"checked_in"`
+				Status    string `json:"status"`
+				Answers   []struct {
+					QuestionID string `json:"question_id"`
+					Question   string `json:"question"`
+					Answer     string `json:"answer"`
+				} `json:"answers"`
+			} `json:"participants"`
+		}
+		s.Nil(json.Unmarshal(data, &got))
+		s.Equal("asd", got.GoogleFormID)
+		s.Equal(2, got.TotalParticipants)
+		s.Equal(1, got.TotalCheckedIn)
+		s.Len(got.Participants, 2)
+		s.Equal(1, got.Participants[0].No)
+		s.True(got.Participants[0].Approved)
+		s.True(got.Participants[0].CheckedIn)
+		s.Equal("hadir", got.Participants[0].Status)
+		s.Len(got.Participants[0].Answers, 2)
+		s.Equal("7", got.Participants[0].Answers[0].QuestionID)
+		s.Equal("Company", got.Participants[0].Answers[0].Question)
+		s.Equal("BAKODE", got.Participants[0].Answers[0].Answer)
+		// the question sharing the title is kept, the json is not escaped
+		s.Equal("9", got.Participants[0].Answers[1].QuestionID)
+		s.Equal("Company", got.Participants[0].Answers[1].Question)
+		s.Equal(`=HYPERLINK("https://lorem.id")`, got.Participants[0].Answers[1].Answer)
+		s.Len(got.Participants[1].Answers, 2)
+		s.Empty(got.Participants[1].Answers[0].Answer)
+		s.Empty(got.Participants[1].Answers[1].Answer)
+	})
+	s.T().Run("json without bom", func(t *testing.T) {
+		// the bom is only written into the csv export
+		data := export(t, string(common.ExportTypeJSON), true)
+		s.True(bytes.HasPrefix(data, []byte("{")))
+		data = export(t, string(common.ExportTypeNDJSON), true)
+		s.True(bytes.HasPrefix(data, []byte("{")))
+	})
+	s.T().Run("ndjson", func(t *testing.T) {
+		data := export(t, string(common.ExportTypeNDJSON), false)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		s.Len(lines, 2)
+		var got struct {
+			Name           string `json:"name"`
+			Declined       bool   `json:"declined"`
+			DeclinedReason string `json:"declined_reason"`
+			Status         string `json:"status"`
+		}
+		s.Nil(json.Unmarshal([]byte(lines[1]), &got))
+		s.Equal("Dédé, Jr", got.Name)
+		s.True(got.Declined)
+		s.Equal("full", got.DeclinedReason)
+		s.Equal("ditolak", got.Status)
+	})
 }
-func (s *tixServiceTestSuite) Test_GenerateTicket_ShouldError() {
-	pqRepo := new(mocks.IPostgreSQLRepository)
-	svc := service.NewTixService(
-		service.WithPostgreSQLRepository(pqRepo),
-		service.WithMailer(&gomail.Dialer{}))
+func (s *tixServiceTestSuite) Test_StreamEventExport_ShouldSuccess() {
+	newParticipants := func(total int) []*entity.Participant {
+		participants := make([]*entity.Participant, total)
+		for i := range participants {
+			participants[i] = &entity.Participant{
+				ID: int32(i + 1), EventID: 1, Name: fmt.Sprintf("asd %d", i+1), Email: "asd@asd.id",
+				ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+				CustomAnswers: entity.CustomAnswers{
+					"7": {Question: "Company", Answer: "BAKODE", Position: 7},
+				},
+			}
+		}
+		return participants
+	}
+	export := func(t *testing.T, exportType string, participants []*entity.Participant) []byte {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd",
+			TotalParticipants: int32(len(participants)),
+		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+			Return(participants, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(1).Times(4)
+		var w bytes.Buffer
+		s.Nil(svc.StreamEventExport(context.TODO(), "asd", exportType, &request.EventRequestExport{BOM: true}, &w))
+		pqRepo.AssertExpectations(t)
+		// nothing is written into the export directory
+		s.NoDirExists("./temps")
+		return w.Bytes()
+	}
+
+	s.T().Run("csv", func(t *testing.T) {
+		data := export(t, string(common.ExportTypeCSV), newParticipants(1))
+		s.True(bytes.HasPrefix(data, []byte("\xEF\xBB\xBFNo,Nama,")))
+		s.Contains(string(data), "1,asd 1,asd@asd.id")
+	})
+	s.T().Run("pdf", func(t *testing.T) {
+		data := export(t, string(common.ExportTypePDF), newParticipants(1))
+		s.True(bytes.HasPrefix(data, []byte("%PDF")))
+	})
+	for name, total := range map[string]int{
+		"xlsx":          1,
+		"xlsx streamed": common.ExcelStreamThreshold + 1,
+	} {
+		total := total
+		s.T().Run(name, func(t *testing.T) {
+			participants := newParticipants(total)
+			// past the rows sizing the columns, so it does not widen them
+			if total > common.ExcelColWidthSampleSize {
+				participants[total-1].Email = "lorem.ipsum.dolor.sit.amet@asd.id"
+			}
+			data := export(t, string(common.ExportTypeXLS), participants)
+			f, err := excelize.OpenReader(bytes.NewReader(data))
+			s.Nil(err)
+			title, _ := f.GetCellValue("Sheet1", "A1")
+			s.Equal(common.DefaultBrandingHeaderTitle, title)
+			name, _ := f.GetCellValue("Sheet1", "B5")
+			s.Equal("asd", name)
+			header, _ := f.GetCellValue("Sheet1", fmt.Sprintf("K%d", common.ExcelTableStartIndex))
+			s.Equal("Company", header)
+			last, _ := f.GetCellValue("Sheet1", fmt.Sprintf("B%d", common.ExcelTableStartIndex+total))
+			s.Equal(fmt.Sprintf("asd %d", total), last)
+			mergeCells, _ := f.GetMergeCells("Sheet1")
+			s.Len(mergeCells, 6)
+			width, _ := f.GetColWidth("Sheet1", "C")
+			s.Equal(float64(len("asd@asd.id")+2), width)
+		})
+	}
+}
+func (s *tixServiceTestSuite) Test_StreamEventExport_ShouldError() {
+	s.T().Run("error unsupported type", func(t *testing.T) {
+		svc := service.NewTixService()
+		s.Equal(common.ErrUnsupportedExportType,
+			svc.StreamEventExport(context.TODO(), "asd", "docx", &request.EventRequestExport{}, &bytes.Buffer{}))
+	})
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, errors.New("lorem")).Once()
+		s.Equal(errors.New("lorem"),
+			svc.StreamEventExport(context.TODO(), "asd", "csv", &request.EventRequestExport{}, &bytes.Buffer{}))
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("error unsupported column", func(t *testing.T) {
+		svc := service.NewTixService()
+		s.Equal(common.ErrUnsupportedExportColumn,
+			svc.StreamEventExport(context.TODO(), "asd", "csv",
+				&request.EventRequestExport{Columns: "name,lorem"}, &bytes.Buffer{}))
+	})
+	s.T().Run("error too large", func(t *testing.T) {
+		participants := make([]*entity.Participant, common.ExportDownloadMaxParticipants+1)
+		for i := range participants {
+			participants[i] = &entity.Participant{ID: int32(i + 1), EventID: 1}
+		}
+		participants[0].CheckedInAt = sql.NullInt32{Int32: 1, Valid: true}
+		export := func(form *request.EventRequestExport) error {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+				ID: 1, TotalParticipants: int32(len(participants)),
+			}, nil).Once()
+			pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+				Return(participants, nil).Once()
+			pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(1).Times(4)
+			return svc.StreamEventExport(context.TODO(), "asd", "csv", form, &bytes.Buffer{})
+		}
+		s.Equal(common.ErrExportTooLarge, export(&request.EventRequestExport{}))
+		// the filtered export of the large event can still be downloaded
+		checkedIn := true
+		s.Nil(export(&request.EventRequestExport{CheckedIn: &checkedIn}))
+	})
+	s.T().Run("error get participants", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		s.Equal(errors.New("lorem"),
+			svc.StreamEventExport(context.TODO(), "asd", "csv", &request.EventRequestExport{}, &bytes.Buffer{}))
+		pqRepo.AssertExpectations(t)
+	})
+}
+func (s *tixServiceTestSuite) Test_StreamEventExport_FilteredColumns() {
+	at := func(unix int32) sql.NullInt32 { return sql.NullInt32{Int32: unix, Valid: true} }
+	participants := []*entity.Participant{
+		{
+			ID: 1, EventID: 1, Name: "Andi", Email: "andi@tix.id", Phone: "0811",
+			ApprovedAt: at(1686700000), CheckedInAt: at(1686800000), CreatedAt: at(1686600000),
+			CustomAnswers: entity.CustomAnswers{
+				"7": {Question: "Company", Answer: "BAKODE", Position: 7},
+			},
+		},
+		{
+			ID: 2, EventID: 1, Name: "Budi", Email: "budi@tix.id", Phone: "0812",
+			ApprovedAt: at(1686700000), CreatedAt: at(1686650000),
+		},
+		{
+			ID: 3, EventID: 1, Name: "Citra", Email: "citra@bakode.xyz", Phone: "0813",
+			DeclinedAt: at(1686700000), CreatedAt: at(1686610000),
+		},
+		{
+			ID: 4, EventID: 1, Name: "Dewi", Email: "dewi@tix.id", Phone: "0814",
+			CreatedAt: at(1686620000),
+		},
+	}
+	export := func(t *testing.T, exportType string, form *request.EventRequestExport) []byte {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", TotalParticipants: 4,
+		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+			Return(participants, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(1).Times(4)
+		var w bytes.Buffer
+		s.Nil(svc.StreamEventExport(context.TODO(), "asd", exportType, form, &w))
+		pqRepo.AssertExpectations(t)
+		return w.Bytes()
+	}
+	csvNames := func(t *testing.T, form *request.EventRequestExport) []string {
+		form.Columns = "name"
+		rows, err := csv.NewReader(bytes.NewReader(export(t, "csv", form))).ReadAll()
+		s.Nil(err)
+		var names []string
+		for _, row := range rows[1:] {
+			names = append(names, row[0])
+		}
+		return names
+	}
+	checkedIn, notCheckedIn := true, false
+
+	for _, tt := range []struct {
+		name  string
+		form  *request.EventRequestExport
+		names []string
+	}{
+		{"no filter", &request.EventRequestExport{}, []string{"Andi", "Budi", "Citra", "Dewi"}},
+		{"approved", &request.EventRequestExport{Status: "approved"}, []string{"Andi", "Budi"}},
+		{"declined", &request.EventRequestExport{Status: "declined"}, []string{"Citra"}},
+		{"waiting", &request.EventRequestExport{Status: "waiting"}, []string{"Dewi"}},
+		{"checked in", &request.EventRequestExport{CheckedIn: &checkedIn}, []string{"Andi"}},
+		{"approved not checked in", &request.EventRequestExport{
+			Status: "approved", CheckedIn: &notCheckedIn}, []string{"Budi"}},
+		{"registered between", &request.EventRequestExport{
+			RegisteredFrom: 1686610000, RegisteredTo: 1686620000}, []string{"Citra", "Dewi"}},
+		{"registered from", &request.EventRequestExport{RegisteredFrom: 1686620000}, []string{"Budi", "Dewi"}},
+		{"search", &request.EventRequestExport{Search: " BAKODE "}, []string{"Citra"}},
+		{"search phone", &request.EventRequestExport{Search: "0814"}, []string{"Dewi"}},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.Equal(tt.names, csvNames(t, tt.form))
+		})
+	}
+
+	s.T().Run("csv columns", func(t *testing.T) {
+		rows, err := csv.NewReader(bytes.NewReader(export(t, "csv", &request.EventRequestExport{
+			Status: "approved", Columns: "answers, Email,no,email",
+		}))).ReadAll()
+		s.Nil(err)
+		s.Equal([][]string{
+			{"Company", "Email", "No"},
+			{"BAKODE", "andi@tix.id", "1"},
+			{common.SymDash, "budi@tix.id", "2"},
+		}, rows)
+	})
+	s.T().Run("json columns", func(t *testing.T) {
+		data := export(t, "ndjson", &request.EventRequestExport{
+			CheckedIn: &checkedIn, Columns: "status,name,checked_in,answers",
+		})
+		s.Equal(`{"status":"hadir","name":"Andi","checked_in":true,`+
+			`"answers":[{"question_id":"7","question":"Company","answer":"BAKODE"}]}`+"\n",
+			string(data))
+	})
+	s.T().Run("xlsx columns", func(t *testing.T) {
+		f, err := excelize.OpenReader(bytes.NewReader(export(t, "xls", &request.EventRequestExport{
+			Status: "declined", Columns: "name,declined_reason",
+		})))
+		s.Nil(err)
+		defer func() { _ = f.Close() }()
+		rows, err := f.GetRows("Sheet1")
+		s.Nil(err)
+		s.Equal([]string{"Nama", "Alasan Ditolak"}, rows[common.ExcelTableStartIndex-1])
+		s.Equal([]string{"Citra", common.SymDash}, rows[common.ExcelTableStartIndex])
+	})
+	s.T().Run("xlsx merged cells span the columns", func(t *testing.T) {
+		for columns, lastCol := range map[string]string{
+			"name":                 "B",
+			"name,declined_reason": "B",
+			"no,name,email,phone,job,dob,approved,declined,declined_reason,checked_in,status": "K",
+		} {
+			f, err := excelize.OpenReader(bytes.NewReader(export(t, "xls", &request.EventRequestExport{
+				Columns: columns,
+			})))
+			s.Nil(err)
+			mergeCells, err := f.GetMergeCells("Sheet1")
+			s.Nil(err)
+			ranges := make([]string, 0, len(mergeCells))
+			for _, mergeCell := range mergeCells {
+				ranges = append(ranges, mergeCell.GetStartAxis()+":"+mergeCell.GetEndAxis())
+			}
+			expected := []string{
+				fmt.Sprintf("A%d:%s%d", common.ExcelTitleRow, lastCol, common.ExcelTitleRow),
+				fmt.Sprintf("A%d:%s%d", common.ExcelSubtitleRow, lastCol, common.ExcelSubtitleRow),
+			}
+			for row := common.ExcelEventDataStartIndex; row < common.ExcelEventDataStartIndex+4; row++ {
+				expected = append(expected, fmt.Sprintf("B%d:%s%d", row, lastCol, row))
+			}
+			s.ElementsMatch(expected, ranges, columns)
+			_ = f.Close()
+		}
+	})
+	s.T().Run("pdf columns", func(t *testing.T) {
+		data := export(t, "pdf", &request.EventRequestExport{
+			Columns: "no,name,email,phone,job,dob,approved,declined,declined_reason,checked_in,status,answers",
+		})
+		s.True(bytes.HasPrefix(data, []byte("%PDF")))
+	})
+}
+func (s *tixServiceTestSuite) Test_StreamEventExport_Locale() {
+	participants := []*entity.Participant{
+		{
+			ID: 1, EventID: 1, Name: "Andi", Email: "andi@tix.id",
+			ApprovedAt:  sql.NullInt32{Int32: 1686700000, Valid: true},
+			CheckedInAt: sql.NullInt32{Int32: 1686800000, Valid: true},
+		},
+	}
+	eventDate := time.Date(2023, 6, 30, 0, 0, 0, 0, time.Local)
+	export := func(t *testing.T, exportType, eventLocale, requestLocale string) []byte {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", TotalParticipants: 1,
+			EventDate: int32(eventDate.Unix()), Branding: entity.Branding{Locale: eventLocale},
+		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+			Return(participants, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(1).Times(4)
+		var w bytes.Buffer
+		s.Nil(svc.StreamEventExport(context.TODO(), "asd", exportType,
+			&request.EventRequestExport{Locale: requestLocale, Columns: "name,checked_in,status"}, &w))
+		pqRepo.AssertExpectations(t)
+		return w.Bytes()
+	}
+
+	for _, tt := range []struct {
+		name          string
+		eventLocale   string
+		requestLocale string
+		rows          [][]string
+	}{
+		{"default", "", "", [][]string{{"Nama", "Hadir", "Status"}, {"Andi", common.SymCheck, "hadir"}}},
+		{"event locale", "en", "", [][]string{{"Name", "Checked In", "Status"}, {"Andi", common.SymCheck, "checked in"}}},
+		{"request locale", "en", "id", [][]string{{"Nama", "Hadir", "Status"}, {"Andi", common.SymCheck, "hadir"}}},
+	} {
+		s.T().Run("csv "+tt.name, func(t *testing.T) {
+			rows, err := csv.NewReader(bytes.NewReader(
+				export(t, "csv", tt.eventLocale, tt.requestLocale))).ReadAll()
+			s.Nil(err)
+			s.Equal(tt.rows, rows)
+		})
+	}
+	s.T().Run("xlsx", func(t *testing.T) {
+		for locale, want := range map[string][][]string{
+			"id": {{"Nama:", "asd"}, {"Lokasi:", "asd"}, {"Tanggal:", "30 Juni 2023"},
+				{"Total Peserta:", "1 –– 1 diterima | 1 menunggu | 1 ditolak | 1 hadir ––"}},
+			"en": {{"Name:", "asd"}, {"Location:", "asd"}, {"Date:", "30 June 2023"},
+				{"Total Participants:", "1 –– 1 approved | 1 waiting | 1 declined | 1 checked in ––"}},
+		} {
+			f, err := excelize.OpenReader(bytes.NewReader(export(t, "xls", "", locale)))
+			s.Nil(err)
+			rows, err := f.GetRows("Sheet1")
+			s.Nil(err)
+			s.Equal(want, rows[common.ExcelEventDataStartIndex-1:common.ExcelEventDataStartIndex+3])
+			_ = f.Close()
+		}
+	})
+	s.T().Run("json", func(t *testing.T) {
+		s.Contains(string(export(t, "json", "en", "")), `"status":"checked in"`)
+	})
+}
+func (s *tixServiceTestSuite) Test_ExportEvent_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("error unsupported type", func(t *testing.T) {
+		err := svc.ExportEvent(context.TODO(), "asd", "docx", "asd", &request.EventRequestExport{})
+		s.Equal(common.ErrUnsupportedExportType, err)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("error unsupported column", func(t *testing.T) {
+		err := svc.ExportEvent(context.TODO(), "asd", "csv", "asd",
+			&request.EventRequestExport{Columns: "lorem"})
+		s.Equal(common.ErrUnsupportedExportColumn, err)
+		pqRepo.AssertExpectations(t)
+	})
 	s.T().Run("error get event", func(t *testing.T) {
 		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
-		s.NotNil(errSvc)
-		pqRepo.AssertExpectations(s.T())
+		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd", &request.EventRequestExport{})
+		s.NotNil(err)
+		pqRepo.AssertExpectations(t)
 	})
 	s.T().Run("error get participant", func(t *testing.T) {
 		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
@@ -866,40 +1676,1719 @@ func (s *tixServiceTestSuite) Test_GenerateTicket_ShouldError() {
 				Valid: true,
 			},
 		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd", &request.EventRequestExport{})
+		s.NotNil(err)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("error save export", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd"}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]*entity.Participant{}, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0).Times(4)
+		// the export directory does not exist
+		_ = os.RemoveAll("./temps")
+		err := svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeCSV), "asd", &request.EventRequestExport{})
+		s.ErrorContains(err, "could not save export")
+		pqRepo.AssertExpectations(t)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_ExportEvent_Archive() {
+	dir := "./temps/exports/"
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		s.T().Fatalf("Failed to create directory: %s", err)
+	}
+	defer func() { _ = os.RemoveAll("./temps") }()
+	export := func(t *testing.T, svc domain.ITixService, pqRepo *mocks.IPostgreSQLRepository) error {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", TotalParticipants: 1,
+		}, nil).Once()
+		pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "name", "ASC").
+			Return([]*entity.Participant{{ID: 1, EventID: 1, Name: "asd", Email: "asd@asd.id"}}, nil).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(1).Times(4)
+		defer pqRepo.AssertExpectations(t)
+		return svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeCSV), "lorem@lorem.id",
+			&request.EventRequestExport{})
+	}
+	archived := func(t *testing.T, storeDir string) string {
+		files, err := filepath.Glob(filepath.Join(storeDir, "exports", "asd", "*", "asd.csv"))
+		s.Nil(err)
+		s.Len(files, 1)
+		data, err := os.ReadFile(files[0])
+		s.Nil(err)
+		return string(data)
+	}
+
+	s.T().Run("the large export is sent as a download link", func(t *testing.T) {
+		storeDir := t.TempDir()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		dialer, inbox := newSMTPInbox(t)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(dialer),
+			service.WithTicketSigningSecret("secret"),
+			service.WithPublicURL("https://tix.id/"),
+			service.WithFileStore(storage.NewLocalFileStore(storeDir)),
+			service.WithExportAttachmentMaxSize(1))
+		s.Nil(export(t, svc, pqRepo))
+		// the email is sent, so the export is removed but kept in the archive
+		s.NoFileExists(filepath.Join(dir, "asd.csv"))
+		data := archived(t, storeDir)
+		s.Contains(data, "asd@asd.id")
+
+		html, attachments := readEmail(t, <-inbox)
+		s.Empty(attachments)
+		s.Contains(html, "Unduh Ekspor")
+		link := regexp.MustCompile(`https://tix\.id/api/v1/exports/download\?token=([\w.-]+)`).
+			FindStringSubmatch(html)
+		s.Len(link, 2)
+		file, fileName, contentType, err := svc.OpenEventExport(context.TODO(), link[1])
+		s.Nil(err)
+		got, _ := io.ReadAll(file)
+		_ = file.Close()
+		s.Equal(data, string(got))
+		s.Equal("asd.csv", fileName)
+		s.Equal("text/csv; charset=utf-8", contentType)
+	})
+
+	s.T().Run("the small export is attached", func(t *testing.T) {
+		storeDir := t.TempDir()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		dialer, inbox := newSMTPInbox(t)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(dialer),
+			service.WithTicketSigningSecret("secret"),
+			service.WithPublicURL("https://tix.id/"),
+			service.WithFileStore(storage.NewLocalFileStore(storeDir)))
+		s.Nil(export(t, svc, pqRepo))
+		s.Contains(archived(t, storeDir), "asd@asd.id")
+		html, attachments := readEmail(t, <-inbox)
+		s.Equal([]string{"asd.csv"}, attachments)
+		s.NotContains(html, "/api/v1/exports/download")
+	})
+
+	s.T().Run("the large export is attached when the link can not be signed", func(t *testing.T) {
+		storeDir := t.TempDir()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		dialer, inbox := newSMTPInbox(t)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(dialer),
+			service.WithFileStore(storage.NewLocalFileStore(storeDir)),
+			service.WithExportAttachmentMaxSize(1))
+		s.Nil(export(t, svc, pqRepo))
+		s.Contains(archived(t, storeDir), "asd@asd.id")
+		_, attachments := readEmail(t, <-inbox)
+		s.Equal([]string{"asd.csv"}, attachments)
+	})
+
+	s.T().Run("the export is not sent when it can not be archived", func(t *testing.T) {
+		// the store directory is a file, so nothing can be written into it
+		storeDir := filepath.Join(t.TempDir(), "archives")
+		s.Nil(os.WriteFile(storeDir, nil, 0o600))
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(newSMTPServer(t)),
+			service.WithFileStore(storage.NewLocalFileStore(storeDir)))
+		s.ErrorContains(export(t, svc, pqRepo), "could not archive export")
+		// kept for the retry, see Shutdown
+		s.FileExists(filepath.Join(dir, "asd.csv"))
+	})
+}
+func (s *tixServiceTestSuite) Test_OpenEventExport_ShouldError() {
+	storeDir := s.T().TempDir()
+	store := storage.NewLocalFileStore(storeDir)
+	s.Nil(store.Put(context.TODO(), "exports/asd/1/asd.csv", strings.NewReader("asd"), 3, "text/csv"))
+	key := token.NewTicketKey("secret", common.ExportDownloadKeyID)
+	sign := func(fileKey string, expiredAt time.Time) string {
+		downloadToken, err := token.SignExportDownload(key, fileKey, expiredAt)
+		s.Nil(err)
+		return downloadToken
+	}
+	svc := service.NewTixService(
+		service.WithTicketSigningSecret("secret"),
+		service.WithFileStore(store))
+
+	file, fileName, _, err := svc.OpenEventExport(context.TODO(), sign("exports/asd/1/asd.csv", time.Now().Add(time.Hour)))
+	s.Nil(err)
+	s.Equal("asd.csv", fileName)
+	_ = file.Close()
+
+	for _, tt := range []struct {
+		name          string
+		svc           domain.ITixService
+		downloadToken string
+		err           error
+	}{
+		{"no file store", service.NewTixService(service.WithTicketSigningSecret("secret")),
+			sign("exports/asd/1/asd.csv", time.Now().Add(time.Hour)), common.ErrExportNotFound},
+		{"no signing secret", service.NewTixService(service.WithFileStore(store)),
+			sign("exports/asd/1/asd.csv", time.Now().Add(time.Hour)), common.ErrTicketSigningDisabled},
+		{"expired", svc, sign("exports/asd/1/asd.csv", time.Now().Add(-time.Hour)), common.ErrExportLinkExpired},
+		{"signed by another key", svc, func() string {
+			downloadToken, _ := token.SignExportDownload(token.NewTicketKey("secret", "asd"),
+				"exports/asd/1/asd.csv", time.Now().Add(time.Hour))
+			return downloadToken
+		}(), common.ErrExportLinkInvalid},
+		{"malformed", svc, "lorem", common.ErrExportLinkInvalid},
+		{"removed from the archive", svc, sign("exports/asd/2/asd.csv", time.Now().Add(time.Hour)), common.ErrExportNotFound},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			file, _, _, err := tt.svc.OpenEventExport(context.TODO(), tt.downloadToken)
+			s.Nil(file)
+			s.ErrorIs(err, tt.err)
+		})
+	}
+}
+
+// readEmail return the html body and the attachment names of the email.
+func readEmail(t *testing.T, message *mail.Message) (html string, attachments []string) {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Failed to read email: %s", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, _ := io.ReadAll(quotedprintable.NewReader(message.Body))
+		return string(body), nil
+	}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return html, attachments
+		}
+		if part.FileName() != "" {
+			attachments = append(attachments, part.FileName())
+			continue
+		}
+		// the quoted-printable part is decoded by the reader
+		body, _ := io.ReadAll(part)
+		html = string(body)
+	}
+}
+
+// TIX GENERATE IMPL
+func (s *tixServiceTestSuite) Test_GenerateTicket_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithMailer(newSMTPServer(s.T())),
+		service.WithTicketSigningSecret("secret"))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
+		ID:                1,
+		GoogleFormID:      "asd",
+		Name:              "asd",
+		Location:          "asd",
+		PreregisterDate:   int32(time.Now().Unix()),
+		EventDate:         int32(time.Now().Unix()),
+		TotalParticipants: 1,
+		CreatedAt: sql.NullInt32{
+			Int32: int32(time.Now().Unix()),
+			Valid: true,
+		},
+		UpdatedAt: sql.NullInt32{
+			Int32: int32(time.Now().Unix()),
+			Valid: true,
+		},
+	}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
+		ID:    1,
+		Name:  "lorem",
+		Email: "lorem@lorem.id",
+		ApprovedAt: sql.NullInt32{
+			Int32: int32(time.Now().Unix()),
+			Valid: true,
+		},
+	}, nil).Once()
+	ticketCode := regexp.MustCompile(`^[A-Z2-7]{4}(-[A-Z2-7]{4}){3}$`)
+	pqRepo.On("UpdateParticipantTicketCode", mock.Anything, int32(1), mock.MatchedBy(ticketCode.MatchString)).
+		Return("7K2M-QX4D-9PLA-ZR3T", nil).Once()
+	pqRepo.On("SaveTicketDelivery", mock.Anything, mock.MatchedBy(func(delivery *entity.TicketDelivery) bool {
+		return !delivery.JobID.Valid && delivery.Status == string(common.TicketDeliverySent) &&
+			strings.HasSuffix(delivery.MessageID.String, "@bakode.xyz>") && delivery.AttemptedAt.Valid
+	}), mock.Anything).Return(nil).Once()
+	pqRepo.On("UpdateParticipantTicketSentAt", mock.Anything, int32(1), mock.Anything).
+		Return(nil).Once()
+	dir := "./temps/exports/"
+	filename := "gen11tix.pdf"
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		s.T().Fatalf("Failed to create directory: %s", err)
+	}
+	errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+	s.Nil(errSvc)
+	// the email is sent, so the ticket is removed
+	s.NoFileExists(filepath.Join(dir, filename))
+	s.NoFileExists(filepath.Join(dir, "gen11tix.ics"))
+	if err := os.RemoveAll("./temps"); err != nil {
+		s.T().Fatalf("Failed to remove directory: %s", err)
+	}
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_GenerateTicket_SentTicketShouldNotFail() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	participant := &entity.Participant{
+		ID:         1,
+		Name:       "lorem",
+		Email:      "lorem@lorem.id",
+		ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+		TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+	}
+	s.Nil(os.MkdirAll("./temps/exports/", os.ModePerm))
+	defer func() { _ = os.RemoveAll("./temps") }()
+	tests := []struct {
+		name      string
+		saveErr   error
+		sentAtErr error
+	}{
+		{name: "send ok and save ticket delivery fail", saveErr: errors.New("lorem")},
+		{name: "send ok and mark ticket as sent fail", sentAtErr: errors.New("lorem")},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			svc := service.NewTixService(
+				service.WithPostgreSQLRepository(pqRepo),
+				service.WithRedisCache(redisClient),
+				service.WithMailer(newSMTPServer(t)))
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).
+				Return(&entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd"}, nil).Once()
+			pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).
+				Return(participant, nil).Once()
+			pqRepo.On("SaveTicketDelivery", mock.Anything, mock.Anything, mock.Anything).
+				Return(tt.saveErr).Once()
+			pqRepo.On("UpdateParticipantTicketSentAt", mock.Anything, int32(1), mock.Anything).
+				Return(tt.sentAtErr).Once()
+			// the ticket is already sent, so the delivery is not failed (and retried)
+			errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+			s.Nil(errSvc)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (s *tixServiceTestSuite) Test_GenerateTicket_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithMailer(&gomail.Dialer{}))
+	failedDelivery := mock.MatchedBy(func(delivery *entity.TicketDelivery) bool {
+		return delivery.Status == string(common.TicketDeliveryFailed) && delivery.Error.Valid
+	})
+	event := &entity.Event{
+		ID:                1,
+		GoogleFormID:      "asd",
+		Name:              "asd",
+		Location:          "asd",
+		PreregisterDate:   int32(time.Now().Unix()),
+		EventDate:         int32(time.Now().Unix()),
+		TotalParticipants: 1,
+		CreatedAt: sql.NullInt32{
+			Int32: int32(time.Now().Unix()),
+			Valid: true,
+		},
+		UpdatedAt: sql.NullInt32{
+			Int32: int32(time.Now().Unix()),
+			Valid: true,
+		},
+	}
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+		s.NotNil(errSvc)
+		pqRepo.AssertExpectations(s.T())
+	})
+	s.T().Run("error get participant", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(event, nil).Once()
 		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
 		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
 		s.NotNil(errSvc)
 		pqRepo.AssertExpectations(s.T())
 	})
-	s.T().Run("error generate attachment", func(t *testing.T) {
-		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
-			ID:                1,
-			GoogleFormID:      "asd",
-			Name:              "asd",
-			Location:          "asd",
-			PreregisterDate:   int32(time.Now().Unix()),
-			EventDate:         int32(time.Now().Unix()),
-			TotalParticipants: 1,
-			CreatedAt: sql.NullInt32{
-				Int32: int32(time.Now().Unix()),
-				Valid: true,
-			},
-			UpdatedAt: sql.NullInt32{
+	s.T().Run("error participant not approved", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(event, nil).Once()
+		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
+			ID:    1,
+			Name:  "lorem",
+			Email: "lorem@lorem.id",
+		}, nil).Once()
+		pqRepo.On("SaveTicketDelivery", mock.Anything, failedDelivery, mock.Anything).Return(nil).Once()
+		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+		s.ErrorIs(errSvc, common.ErrParticipantNotApproved)
+		pqRepo.AssertExpectations(s.T())
+	})
+	s.T().Run("error store ticket code", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(event, nil).Once()
+		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
+			ID:    1,
+			Name:  "lorem",
+			Email: "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{
 				Int32: int32(time.Now().Unix()),
 				Valid: true,
 			},
 		}, nil).Once()
+		pqRepo.On("UpdateParticipantTicketCode", mock.Anything, int32(1), mock.Anything).
+			Return("", errors.New("lorem")).Once()
+		pqRepo.On("SaveTicketDelivery", mock.Anything, failedDelivery, mock.Anything).Return(nil).Once()
+		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+		s.NotNil(errSvc)
+		pqRepo.AssertExpectations(s.T())
+	})
+	s.T().Run("error generate attachment", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(event, nil).Once()
 		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
 			ID:    1,
 			Name:  "lorem",
 			Email: "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{
+				Int32: int32(time.Now().Unix()),
+				Valid: true,
+			},
+			TicketCode: sql.NullString{
+				String: "7K2M-QX4D-9PLA-ZR3T",
+				Valid:  true,
+			},
 		}, nil).Once()
+		pqRepo.On("SaveTicketDelivery", mock.Anything, failedDelivery, mock.Anything).
+			Return(errors.New("lorem")).Once()
 		errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
 		s.NotNil(errSvc)
 		pqRepo.AssertExpectations(s.T())
 	})
 }
 
+// TIX BULK TICKET IMPL
+func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketsQueue_ShouldSuccess() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("InsertJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+		return strings.Contains(job.Payload, `"filter":"approved"`)
+	})).Return(int32(1), nil).Once()
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("Publish", mock.Anything, common.ReqGenEventBulkTixQueueKey, mock.Anything).Return("1-0", nil).Once()
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithJobQueue(jobQueue))
+	jobID, err := svc.PublishGenerateEventTicketsQueue(context.TODO(), "asd", "")
+	s.Nil(err)
+	s.Equal(int32(1), jobID)
+	_, err = svc.PublishGenerateEventTicketsQueue(context.TODO(), "asd", "unsent")
+	s.Equal(common.ErrRateLimitingPushQueue, err)
+	pqRepo.AssertExpectations(s.T())
+	jobQueue.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketsQueue_ShouldError() {
+	s.T().Run("error get", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		miniRedis.Close()
+		_ = redisClient.Close()
+		svc := service.NewTixService(service.WithRedisCache(redisClient))
+		_, err := svc.PublishGenerateEventTicketsQueue(context.TODO(), "asd", "")
+		s.NotNil(err)
+	})
+	s.T().Run("error insert job", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(0), errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishGenerateEventTicketsQueue(context.TODO(), "asd", "")
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+}
+func (s *tixServiceTestSuite) Test_GenerateEventTickets_ShouldSuccess() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithMailer(newSMTPServer(s.T())))
+	event := &entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", EventDate: int32(time.Now().Unix())}
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+	pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return(nil, nil).Once()
+	pqRepo.On("GetTicketRecipientIDs", mock.Anything, int32(1), true).Return([]int32{1, 2}, nil).Once()
+	pqRepo.On("InsertTicketDeliveries", mock.Anything, int32(7), []int32{1, 2}, mock.Anything).Return(nil).Once()
+	pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return([]*entity.TicketDelivery{
+		{ID: 1, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 1, Status: string(common.TicketDeliveryQueued)},
+		{ID: 2, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 2, Status: string(common.TicketDeliveryQueued)},
+	}, nil).Once()
+	for _, id := range []int32{1, 2} {
+		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, id, int32(1)).Return(&entity.Participant{
+			ID:         id,
+			EventID:    1,
+			Name:       "lorem",
+			Email:      "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+		pqRepo.On("UpdateParticipantTicketSentAt", mock.Anything, id, mock.Anything).Return(nil).Once()
+	}
+	pqRepo.On("SaveTicketDelivery", mock.Anything, mock.MatchedBy(func(delivery *entity.TicketDelivery) bool {
+		return delivery.JobID.Int32 == 7 && delivery.Status == string(common.TicketDeliverySent) && !delivery.Error.Valid
+	}), mock.Anything).Return(nil).Twice()
+	if err := os.MkdirAll("./temps/exports/", os.ModePerm); err != nil {
+		s.T().Fatalf("Failed to create directory: %s", err)
+	}
+	item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, string(common.TicketRecipientUnsent))
+	s.Nil(err)
+	s.Equal(2, item.Total)
+	s.Equal(2, item.Sent)
+	s.Equal(0, item.Failed)
+	s.Equal(0, item.Pending)
+	if err := os.RemoveAll("./temps"); err != nil {
+		s.T().Fatalf("Failed to remove directory: %s", err)
+	}
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_GenerateEventTickets_RetryFailed() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithMailer(newSMTPServer(s.T())))
+	event := &entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd", Location: "asd", EventDate: int32(time.Now().Unix())}
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+	// the retried job keep its recipients, the sent ticket is not sent again
+	pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return([]*entity.TicketDelivery{
+		{ID: 1, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 1, Status: string(common.TicketDeliverySent)},
+		{ID: 2, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 2, Status: string(common.TicketDeliveryFailed)},
+	}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(2), int32(1)).Return(&entity.Participant{
+		ID:      2,
+		EventID: 1,
+		Name:    "lorem",
+		Email:   "lorem@lorem.id",
+	}, nil).Once()
+	pqRepo.On("SaveTicketDelivery", mock.Anything, mock.MatchedBy(func(delivery *entity.TicketDelivery) bool {
+		return delivery.JobID.Int32 == 7 && delivery.Status == string(common.TicketDeliveryFailed) &&
+			delivery.Error.String == common.ErrParticipantNotApproved.Error()
+	}), mock.Anything).Return(errors.New("lorem")).Once()
+	item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, string(common.TicketRecipientApproved))
+	s.ErrorIs(err, common.ErrTicketDeliveryFailed)
+	s.ErrorContains(err, "tickets sent to 1 of 2 participants, 1 failed")
+	s.Equal(1, item.Sent)
+	s.Equal(1, item.Failed)
+	s.Equal(common.ErrParticipantNotApproved.Error(), item.Deliveries[1].Error)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_GenerateEventTickets_ShouldError() {
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, "")
+		s.NotNil(err)
+		s.Nil(item)
+	})
+	s.T().Run("error get deliveries", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, "")
+		s.NotNil(err)
+		s.Nil(item)
+	})
+	s.T().Run("error get recipients", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return(nil, nil).Once()
+		pqRepo.On("GetTicketRecipientIDs", mock.Anything, int32(1), false).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, "")
+		s.NotNil(err)
+		s.Nil(item)
+	})
+	s.T().Run("error insert deliveries", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return(nil, nil).Once()
+		pqRepo.On("GetTicketRecipientIDs", mock.Anything, int32(1), false).Return([]int32{1}, nil).Once()
+		pqRepo.On("InsertTicketDeliveries", mock.Anything, int32(7), []int32{1}, mock.Anything).
+			Return(errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.GenerateEventTickets(context.TODO(), "asd", 7, "")
+		s.NotNil(err)
+		s.Nil(item)
+	})
+}
+func (s *tixServiceTestSuite) Test_FetchTicketDeliveries_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("GetJobByID", mock.Anything, int32(7)).Return(&entity.Job{
+		ID: 7, EventID: 1, Queue: common.ReqGenEventBulkTixQueueKey,
+	}, nil).Once()
+	pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return([]*entity.TicketDelivery{
+		{ID: 1, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 1, ParticipantName: "lorem", Status: string(common.TicketDeliverySent)},
+		{ID: 2, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 2, Status: string(common.TicketDeliveryFailed),
+			Error: sql.NullString{String: "lorem", Valid: true}},
+		{ID: 3, JobID: sql.NullInt32{Int32: 7, Valid: true}, ParticipantID: 3, Status: string(common.TicketDeliveryQueued)},
+	}, nil).Once()
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	item, err := svc.FetchTicketDeliveries(context.TODO(), "asd", 7)
+	s.Nil(err)
+	s.Equal(int32(7), item.JobID)
+	s.Equal(3, item.Total)
+	s.Equal(1, item.Sent)
+	s.Equal(1, item.Failed)
+	s.Equal(1, item.Pending)
+	s.Equal("lorem", item.Deliveries[0].ParticipantName)
+	s.Equal("lorem", item.Deliveries[1].Error)
+}
+func (s *tixServiceTestSuite) Test_FetchTicketDeliveries_ShouldError() {
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		_, err := svc.FetchTicketDeliveries(context.TODO(), "asd", 7)
+		s.NotNil(err)
+	})
+	s.T().Run("job not found", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetJobByID", mock.Anything, int32(7)).Return(nil, sql.ErrNoRows).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		_, err := svc.FetchTicketDeliveries(context.TODO(), "asd", 7)
+		s.Equal(common.ErrJobNotFound, err)
+	})
+	s.T().Run("job of another event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetJobByID", mock.Anything, int32(7)).Return(&entity.Job{
+			ID: 7, EventID: 2, Queue: common.ReqGenEventBulkTixQueueKey,
+		}, nil).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		_, err := svc.FetchTicketDeliveries(context.TODO(), "asd", 7)
+		s.Equal(common.ErrJobNotFound, err)
+	})
+	s.T().Run("error get deliveries", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetJobByID", mock.Anything, int32(7)).Return(&entity.Job{
+			ID: 7, EventID: 1, Queue: common.ReqGenEventBulkTixQueueKey,
+		}, nil).Once()
+		pqRepo.On("GetTicketDeliveriesByJobID", mock.Anything, int32(7)).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		_, err := svc.FetchTicketDeliveries(context.TODO(), "asd", 7)
+		s.NotNil(err)
+	})
+}
+
+// TIX CHECK-IN IMPL
+func (s *tixServiceTestSuite) Test_CheckInTicket_ShouldSuccess() {
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(rc))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+	pqRepo.On("GetParticipantByTicketCode", mock.Anything, "7K2M-QX4D-9PLA-ZR3T").
+		Return(&entity.Participant{
+			ID:         1,
+			EventID:    1,
+			Name:       "lorem",
+			Email:      "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+	pqRepo.On("CheckInParticipant", mock.Anything, int32(1), mock.Anything, "admin@tix.id").
+		Return(nil).Once()
+	rc.Set(context.TODO(), "overview-asd", "lorem", 0)
+	// the scanned code is normalized
+	data, err := svc.CheckInTicket(context.TODO(), "asd", " 7k2m-qx4d-9pla-zr3t ", "admin@tix.id")
+	s.Nil(err)
+	s.Equal(int32(1), data.ParticipantID)
+	s.Equal("7K2M-QX4D-9PLA-ZR3T", data.TicketCode)
+	s.Equal("admin@tix.id", data.CheckedInBy)
+	s.NotZero(data.CheckedInAt)
+	s.Equal(int64(0), rc.Exists(context.TODO(), "overview-asd").Val())
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_CheckInTicket_ShouldError() {
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	approvedAt := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	tests := []struct {
+		name        string
+		eventErr    error
+		participant *entity.Participant
+		findErr     error
+		checkInErr  error
+		want        error
+	}{
+		{name: "error get event", eventErr: errors.New("lorem"), want: errors.New("lorem")},
+		{name: "error unknown ticket", findErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get participant", findErr: errors.New("lorem"), want: errors.New("lorem")},
+		{
+			name:        "error wrong event",
+			participant: &entity.Participant{ID: 1, EventID: 2, ApprovedAt: approvedAt},
+			want:        common.ErrTicketWrongEvent,
+		},
+		{
+			name: "error declined",
+			participant: &entity.Participant{ID: 1, EventID: 1,
+				DeclinedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}},
+			want: common.ErrTicketDeclined,
+		},
+		{
+			name:        "error not approved",
+			participant: &entity.Participant{ID: 1, EventID: 1},
+			want:        common.ErrTicketNotApproved,
+		},
+		{
+			name: "error already checked in",
+			participant: &entity.Participant{ID: 1, EventID: 1, ApprovedAt: approvedAt,
+				CheckedInAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}},
+			want: common.ErrTicketAlreadyCheckedIn,
+		},
+		{
+			name:        "error checked in by another gate",
+			participant: &entity.Participant{ID: 1, EventID: 1, ApprovedAt: approvedAt},
+			checkInErr:  sql.ErrNoRows,
+			want:        common.ErrTicketAlreadyCheckedIn,
+		},
+		{
+			name:        "error check in",
+			participant: &entity.Participant{ID: 1, EventID: 1, ApprovedAt: approvedAt},
+			checkInErr:  errors.New("lorem"),
+			want:        errors.New("lorem"),
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			svc := service.NewTixService(
+				service.WithPostgreSQLRepository(pqRepo),
+				service.WithRedisCache(rc))
+			if tt.eventErr != nil {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, tt.eventErr).Once()
+			} else {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+					Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+				pqRepo.On("GetParticipantByTicketCode", mock.Anything, "7K2M-QX4D-9PLA-ZR3T").
+					Return(tt.participant, tt.findErr).Once()
+			}
+			if tt.checkInErr != nil {
+				pqRepo.On("CheckInParticipant", mock.Anything, int32(1), mock.Anything, "admin@tix.id").
+					Return(tt.checkInErr).Once()
+			}
+			data, err := svc.CheckInTicket(context.TODO(), "asd", "7K2M-QX4D-9PLA-ZR3T", "admin@tix.id")
+			s.Nil(data)
+			s.Equal(tt.want, err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (s *tixServiceTestSuite) Test_CheckInTicket_WithTicketToken_ShouldSuccess() {
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(rc),
+		service.WithTicketSigningSecret("secret"))
+	ticket, _ := token.SignTicket(token.NewTicketKey("secret", "asd"),
+		1, 1, time.Now().Add(time.Hour))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+		Return(&entity.Participant{
+			ID:         1,
+			EventID:    1,
+			Name:       "lorem",
+			Email:      "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+	pqRepo.On("CheckInParticipant", mock.Anything, int32(1), mock.Anything, "admin@tix.id").
+		Return(nil).Once()
+	data, err := svc.CheckInTicket(context.TODO(), "asd", ticket, "admin@tix.id")
+	s.Nil(err)
+	s.Equal(int32(1), data.ParticipantID)
+	s.Equal("7K2M-QX4D-9PLA-ZR3T", data.TicketCode)
+	pqRepo.AssertExpectations(s.T())
+}
+
+func (s *tixServiceTestSuite) Test_ReconcileCheckIns_ShouldSuccess() {
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(rc),
+		service.WithTicketSigningSecret("secret"))
+	key := token.NewTicketKey("secret", "asd")
+	expiredAt := time.Now().Add(time.Hour)
+	first, _ := token.SignTicket(key, 1, 1, expiredAt)
+	second, _ := token.SignTicket(key, 1, 2, expiredAt)
+	third, _ := token.SignTicket(key, 1, 3, expiredAt)
+	otherEvent, _ := token.SignTicket(token.NewTicketKey("secret", "qwe"), 2, 4, expiredAt)
+	approvedAt := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	now := time.Now().Unix()
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+		Return(&entity.Participant{ID: 1, EventID: 1, ApprovedAt: approvedAt}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(2), int32(1)).
+		Return(&entity.Participant{ID: 2, EventID: 1, ApprovedAt: approvedAt,
+			CheckedInAt: sql.NullInt32{Int32: int32(now - 100), Valid: true}}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(3), int32(1)).
+		Return(&entity.Participant{ID: 3, EventID: 1, ApprovedAt: approvedAt}, nil).Once()
+	// the earliest scan of the ticket is recorded
+	pqRepo.On("CheckInParticipant", mock.Anything, int32(1), now-50, "gate@tix.id").
+		Return(nil).Once()
+	// checked in by another gate in the meantime
+	pqRepo.On("CheckInParticipant", mock.Anything, int32(3), now-40, "gate@tix.id").
+		Return(sql.ErrNoRows).Once()
+	data, err := svc.ReconcileCheckIns(context.TODO(), "asd", []*request.EventRequestOfflineCheckIn{
+		{Token: first, CheckedInAt: now - 10},
+		{Token: first, CheckedInAt: now - 50},
+		{Token: second, CheckedInAt: now - 30},
+		{Token: third, CheckedInAt: now - 40},
+		{Token: otherEvent, CheckedInAt: now - 20},
+		{Token: "lorem", CheckedInAt: now - 20},
+	}, "gate@tix.id")
+	s.Nil(err)
+	s.Equal(6, data.Total)
+	s.Equal(1, data.CheckedIn)
+	s.Equal(3, data.Duplicates)
+	s.Equal(2, data.Rejected)
+	s.Equal(string(common.CheckInDuplicate), data.Items[0].Status)
+	s.Equal(int32(now-50), data.Items[0].CheckedInAt)
+	s.Equal(string(common.CheckInRecorded), data.Items[1].Status)
+	s.Equal(string(common.CheckInDuplicate), data.Items[2].Status)
+	s.Equal(int32(now-100), data.Items[2].CheckedInAt)
+	s.Equal(string(common.CheckInDuplicate), data.Items[3].Status)
+	s.Equal(common.ErrTicketWrongEvent.Code, data.Items[4].Code)
+	s.Equal(common.ErrTicketInvalid.Code, data.Items[5].Code)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_ReconcileCheckIns_ShouldError() {
+	rc := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	ticket, _ := token.SignTicket(token.NewTicketKey("secret", "asd"),
+		1, 1, time.Now().Add(time.Hour))
+	checkIns := []*request.EventRequestOfflineCheckIn{
+		{Token: ticket, CheckedInAt: time.Now().Unix()},
+	}
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(rc),
+			service.WithTicketSigningSecret("secret"))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(nil, errors.New("lorem")).Once()
+		data, err := svc.ReconcileCheckIns(context.TODO(), "asd", checkIns, "gate@tix.id")
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+	})
+	s.T().Run("error signing disabled", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(rc))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		data, err := svc.ReconcileCheckIns(context.TODO(), "asd", checkIns, "gate@tix.id")
+		s.Nil(data)
+		s.Equal(common.ErrTicketSigningDisabled, err)
+	})
+	s.T().Run("error check in", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(rc),
+			service.WithTicketSigningSecret("secret"))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+			Return(&entity.Participant{ID: 1, EventID: 1,
+				ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}}, nil).Once()
+		pqRepo.On("CheckInParticipant", mock.Anything, int32(1), mock.Anything, "gate@tix.id").
+			Return(errors.New("lorem")).Once()
+		data, err := svc.ReconcileCheckIns(context.TODO(), "asd", checkIns, "gate@tix.id")
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+	})
+}
+
+// TIX TICKET IMPL
+func (s *tixServiceTestSuite) Test_FetchTicketKeyBundle_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithTicketSigningSecret("secret"))
+	eventDate := time.Now().Unix()
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd", EventDate: int32(eventDate)}, nil).Once()
+	data, err := svc.FetchTicketKeyBundle(context.TODO(), "asd")
+	s.Nil(err)
+	s.Equal(int32(1), data.EventID)
+	s.Equal(token.TicketAlgorithm, data.Algorithm)
+	s.Equal(eventDate+int64(common.TicketTokenGracePeriod.Seconds()), data.ExpiresAt)
+	// the scanner verify the ticket with the bundled public key
+	publicKey, _ := base64.StdEncoding.DecodeString(data.PublicKey)
+	ticket, _ := token.SignTicket(token.NewTicketKey("secret", "asd"),
+		1, 1, time.Now().Add(time.Hour))
+	_, err = token.VerifyTicket(publicKey, ticket, time.Now())
+	s.Nil(err)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_FetchTicketKeyBundle_ShouldError() {
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithTicketSigningSecret("secret"))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(nil, errors.New("lorem")).Once()
+		data, err := svc.FetchTicketKeyBundle(context.TODO(), "asd")
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+	})
+	s.T().Run("error signing disabled", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		data, err := svc.FetchTicketKeyBundle(context.TODO(), "asd")
+		s.Nil(data)
+		s.Equal(common.ErrTicketSigningDisabled, err)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_VerifyTicket_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithTicketSigningSecret("secret"))
+	ticket, _ := token.SignTicket(token.NewTicketKey("secret", "asd"),
+		1, 1, time.Now().Add(time.Hour))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+		Return(&entity.Participant{
+			ID:          1,
+			EventID:     1,
+			Name:        "lorem",
+			ApprovedAt:  sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			CheckedInAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+		}, nil).Once()
+	data, err := svc.VerifyTicket(context.TODO(), "asd", ticket)
+	s.Nil(err)
+	s.Equal(int32(1), data.ParticipantID)
+	s.Equal("approved", data.Status)
+	s.NotNil(data.CheckedInAt)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_VerifyTicket_ShouldError() {
+	key := token.NewTicketKey("secret", "asd")
+	valid, _ := token.SignTicket(key, 1, 1, time.Now().Add(time.Hour))
+	expired, _ := token.SignTicket(key, 1, 1, time.Now().Add(-time.Hour))
+	forged, _ := token.SignTicket(token.NewTicketKey("lorem", "asd"), 1, 1, time.Now().Add(time.Hour))
+	otherEvent, _ := token.SignTicket(key, 2, 1, time.Now().Add(time.Hour))
+	tests := []struct {
+		name    string
+		ticket  string
+		findErr error
+		want    error
+	}{
+		{name: "error malformed", ticket: "lorem", want: common.ErrTicketInvalid},
+		{name: "error expired", ticket: expired, want: common.ErrTicketExpired},
+		{name: "error forged", ticket: forged, want: common.ErrTicketInvalid},
+		{name: "error wrong event", ticket: otherEvent, want: common.ErrTicketWrongEvent},
+		{name: "error unknown participant", ticket: valid, findErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get participant", ticket: valid, findErr: errors.New("lorem"), want: errors.New("lorem")},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			svc := service.NewTixService(
+				service.WithPostgreSQLRepository(pqRepo),
+				service.WithTicketSigningSecret("secret"))
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+				Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+			if tt.findErr != nil {
+				pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+					Return(nil, tt.findErr).Once()
+			}
+			data, err := svc.VerifyTicket(context.TODO(), "asd", tt.ticket)
+			s.Nil(data)
+			s.Equal(tt.want, err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// newSMTPServer start a fake smtp server accepting every email.
+func newSMTPServer(t *testing.T) *gomail.Dialer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start smtp server: %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, nil)
+		}
+	}()
+	return &gomail.Dialer{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port}
+}
+
+// newSMTPInbox start a fake smtp server keeping the received emails.
+func newSMTPInbox(t *testing.T) (*gomail.Dialer, <-chan *mail.Message) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start smtp server: %s", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	inbox := make(chan *mail.Message, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, inbox)
+		}
+	}()
+	return &gomail.Dialer{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port}, inbox
+}
+
+func serveSMTP(conn net.Conn, inbox chan<- *mail.Message) {
+	defer func() { _ = conn.Close() }()
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reader := bufio.NewReader(conn)
+	reply("220 localhost ESMTP")
+	inData := false
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if inData {
+			if line == ".\r\n" {
+				inData = false
+				if message, err := mail.ReadMessage(strings.NewReader(data.String())); err == nil && inbox != nil {
+					inbox <- message
+				}
+				data.Reset()
+				reply("250 OK")
+				continue
+			}
+			data.WriteString(strings.TrimPrefix(line, "."))
+			continue
+		}
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case command == "DATA":
+			inData = true
+			reply("354 End data with <CR><LF>.<CR><LF>")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// TIX WALLET IMPL
+func newWalletSigner(t *testing.T) *wallet.Signer {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Pass Type ID: pass.id.tix"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %s", err)
+	}
+	certificate, _ := x509.ParseCertificate(der)
+	return wallet.NewSigner("pass.id.tix", "TEAM123", "TIX", certificate, privateKey, nil)
+}
+
+func (s *tixServiceTestSuite) Test_GenerateTicket_WithWalletPass_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithMailer(&gomail.Dialer{}),
+		service.WithWalletPassSigner(newWalletSigner(s.T())),
+		service.WithPublicURL("https://tix.id/"))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{
+		ID:           1,
+		GoogleFormID: "asd",
+		Name:         "asd",
+		Location:     "asd",
+		EventDate:    int32(time.Now().Unix()),
+	}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, mock.Anything, mock.Anything).Return(&entity.Participant{
+		ID:         1,
+		Name:       "lorem",
+		Email:      "lorem@lorem.id",
+		ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+		TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+	}, nil).Once()
+	pqRepo.On("SaveTicketDelivery", mock.Anything, mock.MatchedBy(func(delivery *entity.TicketDelivery) bool {
+		return delivery.Status == string(common.TicketDeliveryFailed) && !delivery.MessageID.Valid &&
+			strings.Contains(delivery.Error.String, "could not send ticket email")
+	}), mock.Anything).Return(nil).Once()
+	dir := "./temps/exports/"
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		s.T().Fatalf("Failed to create directory: %s", err)
+	}
+	errSvc := svc.GenerateTicket(context.TODO(), "asd", 1)
+	s.ErrorContains(errSvc, "could not send ticket email")
+	// the email is not sent, so the tickets are kept
+	s.FileExists(filepath.Join(dir, "gen11tix.pdf"))
+	s.FileExists(filepath.Join(dir, "gen11tix.pkpass"))
+	s.FileExists(filepath.Join(dir, "gen11tix.ics"))
+	if err := os.RemoveAll("./temps"); err != nil {
+		s.T().Fatalf("Failed to remove directory: %s", err)
+	}
+	pqRepo.AssertExpectations(s.T())
+}
+
+func (s *tixServiceTestSuite) Test_GenerateWalletPass_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithWalletPassSigner(newWalletSigner(s.T())))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd"}, nil).Once()
+	pqRepo.On("GetParticipantByTicketCode", mock.Anything, "7K2M-QX4D-9PLA-ZR3T").
+		Return(&entity.Participant{
+			ID:         1,
+			EventID:    1,
+			Name:       "lorem",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+	data, err := svc.GenerateWalletPass(context.TODO(), "asd", "7k2m-qx4d-9pla-zr3t")
+	s.Nil(err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	s.Nil(err)
+	s.Equal("pass.json", archive.File[0].Name)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_GenerateWalletPass_ShouldError() {
+	approvedAt := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	tests := []struct {
+		name        string
+		disabled    bool
+		eventErr    error
+		participant *entity.Participant
+		findErr     error
+		want        error
+	}{
+		{name: "error disabled", disabled: true, want: common.ErrWalletPassDisabled},
+		{name: "error unknown event", eventErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get event", eventErr: errors.New("lorem"), want: errors.New("lorem")},
+		{name: "error unknown ticket", findErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get participant", findErr: errors.New("lorem"), want: errors.New("lorem")},
+		{
+			name:        "error ticket of another event",
+			participant: &entity.Participant{ID: 1, EventID: 2, ApprovedAt: approvedAt},
+			want:        common.ErrTicketNotFound,
+		},
+		{
+			name:        "error not approved",
+			participant: &entity.Participant{ID: 1, EventID: 1},
+			want:        common.ErrParticipantNotApproved,
+		},
+		{
+			name: "error declined",
+			participant: &entity.Participant{ID: 1, EventID: 1, ApprovedAt: approvedAt,
+				DeclinedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}},
+			want: common.ErrParticipantNotApproved,
+		},
+	}
+	signer := newWalletSigner(s.T())
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			options := []service.TixOptions{service.WithPostgreSQLRepository(pqRepo)}
+			if !tt.disabled {
+				options = append(options, service.WithWalletPassSigner(signer))
+				if tt.eventErr != nil {
+					pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, tt.eventErr).Once()
+				} else {
+					pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+						Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+					pqRepo.On("GetParticipantByTicketCode", mock.Anything, "7K2M-QX4D-9PLA-ZR3T").
+						Return(tt.participant, tt.findErr).Once()
+				}
+			}
+			svc := service.NewTixService(options...)
+			data, err := svc.GenerateWalletPass(context.TODO(), "asd", "7K2M-QX4D-9PLA-ZR3T")
+			s.Nil(data)
+			s.Equal(tt.want, err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TIX CALENDAR IMPL
+func (s *tixServiceTestSuite) Test_FetchEventCalendar_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	eventDate := time.Date(2023, 6, 30, 0, 0, 0, 0, time.Local)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+		ID:           1,
+		GoogleFormID: "asd",
+		Name:         "tix",
+		Location:     "jalan tix",
+		EventDate:    int32(eventDate.Unix()),
+	}, nil).Once()
+	data, err := svc.FetchEventCalendar(context.TODO(), "asd")
+	s.Nil(err)
+	s.Contains(string(data), "UID:event-1@tix.bakode.xyz\r\n")
+	s.Contains(string(data), "DTSTART;VALUE=DATE:20230630\r\n")
+	s.Contains(string(data), "SUMMARY:tix\r\n")
+	s.Contains(string(data), "LOCATION:jalan tix\r\n")
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_FetchEventCalendar_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(nil, errors.New("lorem")).Once()
+	data, err := svc.FetchEventCalendar(context.TODO(), "asd")
+	s.Nil(data)
+	s.Equal(errors.New("lorem"), err)
+	pqRepo.AssertExpectations(s.T())
+}
+
+// TIX DOWNLOAD IMPL
+func (s *tixServiceTestSuite) Test_DownloadTicket_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithTicketSigningSecret("secret"))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+		ID:           1,
+		GoogleFormID: "asd",
+		Name:         "asd",
+		EventDate:    int32(time.Now().Unix()),
+	}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+		Return(&entity.Participant{
+			ID:         1,
+			EventID:    1,
+			Name:       "lorem",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+	data, err := svc.DownloadTicket(context.TODO(), "asd", 1)
+	s.Nil(err)
+	s.True(bytes.HasPrefix(data, []byte("%PDF")))
+	// the ticket is drawn in memory
+	s.NoDirExists("./temps")
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_DownloadTicket_ShouldError() {
+	tests := []struct {
+		name        string
+		eventErr    error
+		participant *entity.Participant
+		findErr     error
+		want        error
+	}{
+		{name: "error get event", eventErr: errors.New("lorem"), want: errors.New("lorem")},
+		{name: "error unknown participant", findErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get participant", findErr: errors.New("lorem"), want: errors.New("lorem")},
+		{
+			name:        "error not approved",
+			participant: &entity.Participant{ID: 1, EventID: 1},
+			want:        common.ErrParticipantNotApproved,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+			if tt.eventErr != nil {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, tt.eventErr).Once()
+			} else {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+					Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+				pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).
+					Return(tt.participant, tt.findErr).Once()
+			}
+			data, err := svc.DownloadTicket(context.TODO(), "asd", 1)
+			s.Nil(data)
+			s.Equal(tt.want, err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (s *tixServiceTestSuite) Test_DownloadTicketByToken_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithTicketSigningSecret("secret"))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+		ID:           1,
+		GoogleFormID: "asd",
+		Name:         "asd",
+		EventDate:    int32(time.Now().Unix()),
+	}, nil).Once()
+	pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(2), int32(1)).
+		Return(&entity.Participant{
+			ID:         2,
+			EventID:    1,
+			Name:       "lorem",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+	downloadToken, _ := token.SignTicketDownload(
+		token.NewTicketKey("secret", "asd"), 1, 2, time.Now().Add(time.Hour))
+	data, err := svc.DownloadTicketByToken(context.TODO(), "asd", downloadToken)
+	s.Nil(err)
+	s.True(bytes.HasPrefix(data, []byte("%PDF")))
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_DownloadTicketByToken_ShouldError() {
+	key := token.NewTicketKey("secret", "asd")
+	validToken, _ := token.SignTicketDownload(key, 1, 2, time.Now().Add(time.Hour))
+	expiredToken, _ := token.SignTicketDownload(key, 1, 2, time.Now().Add(-time.Hour))
+	anotherEventToken, _ := token.SignTicketDownload(key, 2, 2, time.Now().Add(time.Hour))
+	ticketToken, _ := token.SignTicket(key, 1, 2, time.Now().Add(time.Hour))
+	tests := []struct {
+		name          string
+		disabled      bool
+		eventErr      error
+		downloadToken string
+		findErr       error
+		want          error
+	}{
+		{name: "error unknown event", eventErr: sql.ErrNoRows, want: common.ErrTicketNotFound},
+		{name: "error get event", eventErr: errors.New("lorem"), want: errors.New("lorem")},
+		{name: "error signing disabled", disabled: true, want: common.ErrTicketSigningDisabled},
+		{name: "error malformed token", downloadToken: "lorem", want: common.ErrTicketInvalid},
+		{name: "error ticket token", downloadToken: ticketToken, want: common.ErrTicketInvalid},
+		{name: "error expired token", downloadToken: expiredToken, want: common.ErrTicketExpired},
+		{name: "error token of another event", downloadToken: anotherEventToken, want: common.ErrTicketInvalid},
+		{
+			name:          "error unknown participant",
+			downloadToken: validToken,
+			findErr:       sql.ErrNoRows,
+			want:          common.ErrTicketNotFound,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo := new(mocks.IPostgreSQLRepository)
+			options := []service.TixOptions{service.WithPostgreSQLRepository(pqRepo)}
+			if !tt.disabled {
+				options = append(options, service.WithTicketSigningSecret("secret"))
+			}
+			svc := service.NewTixService(options...)
+			if tt.eventErr != nil {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, tt.eventErr).Once()
+			} else {
+				pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+					Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+			}
+			if tt.findErr != nil {
+				pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(2), int32(1)).
+					Return(nil, tt.findErr).Once()
+			}
+			data, err := svc.DownloadTicketByToken(context.TODO(), "asd", tt.downloadToken)
+			s.Nil(data)
+			s.Equal(tt.want, err)
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TIX BRANDING IMPL
+func (s *tixServiceTestSuite) Test_FetchEventBranding_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("default branding", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		data, err := svc.FetchEventBranding(context.TODO(), "asd")
+		s.Nil(err)
+		s.True(data.IsDefault)
+		s.Equal(common.DefaultBrandingHeaderTitle, data.HeaderTitle)
+		s.Equal(common.DefaultBrandingSenderEmail, data.SenderEmail)
+		s.Equal(string(i18n.Default), data.Locale)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("custom branding", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID:           1,
+			GoogleFormID: "asd",
+			Branding:     entity.Branding{HeaderTitle: "lorem", PrimaryColor: "#1A2B3C", Locale: "en"},
+		}, nil).Once()
+		data, err := svc.FetchEventBranding(context.TODO(), "asd")
+		s.Nil(err)
+		s.False(data.IsDefault)
+		s.Equal("lorem", data.HeaderTitle)
+		s.Equal("en", data.Locale)
+		s.Equal("#1A2B3C", data.PrimaryColor)
+		s.Equal(common.DefaultBrandingSecondaryColor, data.SecondaryColor)
+		s.Equal(common.DefaultBrandingSenderName, data.SenderName)
+		pqRepo.AssertExpectations(t)
+	})
+}
+func (s *tixServiceTestSuite) Test_FetchEventBranding_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(nil, errors.New("lorem")).Once()
+	data, err := svc.FetchEventBranding(context.TODO(), "asd")
+	s.Nil(data)
+	s.Equal(errors.New("lorem"), err)
+	pqRepo.AssertExpectations(s.T())
+}
+
+func (s *tixServiceTestSuite) Test_UpdateEventBranding_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	pqRepo.On("UpdateEventBranding", mock.Anything, "asd", entity.Branding{
+		PrimaryColor:   "#AABBCC",
+		SecondaryColor: "#1A2B3C",
+		HeaderTitle:    "lorem",
+		SenderName:     "ipsum",
+		SenderEmail:    "ipsum@lorem.id",
+		Locale:         "en",
+	}).Return(nil).Once()
+	data, err := svc.UpdateEventBranding(context.TODO(), "asd", &request.EventRequestBranding{
+		PrimaryColor:   "#abc",
+		SecondaryColor: "#1a2b3c",
+		HeaderTitle:    " lorem ",
+		SenderName:     "ipsum",
+		SenderEmail:    "ipsum@lorem.id",
+		Locale:         "en",
+	})
+	s.Nil(err)
+	s.False(data.IsDefault)
+	s.Equal("#AABBCC", data.PrimaryColor)
+	s.Equal("lorem", data.HeaderTitle)
+	s.Equal(common.DefaultBrandingHeaderSubtitle, data.HeaderSubtitle)
+	s.Equal("en", data.Locale)
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_UpdateEventBranding_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("invalid color", func(t *testing.T) {
+		for _, form := range []*request.EventRequestBranding{
+			{PrimaryColor: "#abcd"},
+			{SecondaryColor: "#lorem1"},
+		} {
+			data, err := svc.UpdateEventBranding(context.TODO(), "asd", form)
+			s.Nil(data)
+			s.Equal(common.ErrInvalidBrandingColor, err)
+		}
+	})
+	s.T().Run("error update", func(t *testing.T) {
+		pqRepo.On("UpdateEventBranding", mock.Anything, "asd", mock.Anything).
+			Return(errors.New("lorem")).Once()
+		data, err := svc.UpdateEventBranding(context.TODO(), "asd", &request.EventRequestBranding{
+			HeaderTitle: "lorem",
+		})
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+		pqRepo.AssertExpectations(t)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_BrandedFiles_ShouldSuccess() {
+	logo := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	var logoPNG bytes.Buffer
+	s.Nil(png.Encode(&logoPNG, logo))
+	var fetched atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched.Add(1)
+		if r.URL.Path != "/logo.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(logoPNG.Bytes())
+	}))
+	defer server.Close()
+
+	for _, logoURL := range []string{server.URL + "/logo.png", server.URL + "/missing.png"} {
+		event := &entity.Event{
+			ID:           1,
+			GoogleFormID: "asd",
+			Name:         "asd",
+			Location:     "asd",
+			EventDate:    int32(time.Now().Unix()),
+			Branding: entity.Branding{
+				LogoURL:        logoURL,
+				PrimaryColor:   "#1A2B3C",
+				SecondaryColor: "#AABBCC",
+				HeaderTitle:    "lorem",
+				FooterText:     "ipsum",
+				SenderName:     "lorem",
+				SenderEmail:    "lorem@ipsum.id",
+			},
+		}
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniredis.RunT(s.T()).Addr(),
+		})
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient),
+			service.WithMailer(&gomail.Dialer{}))
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Times(3)
+		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]*entity.Participant{{ID: 1, EventID: 1, Name: "asd"}}, nil).Twice()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Times(8)
+		pqRepo.On("GetParticipantByIDAndEventID", mock.Anything, int32(1), int32(1)).Return(&entity.Participant{
+			ID:         1,
+			EventID:    1,
+			Name:       "lorem",
+			Email:      "lorem@lorem.id",
+			ApprovedAt: sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true},
+			TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+		}, nil).Once()
+		pqRepo.On("SaveTicketDelivery", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		if err := os.MkdirAll("./temps/exports/", os.ModePerm); err != nil {
+			s.T().Fatalf("Failed to create directory: %s", err)
+		}
+		// the email is not sent, so the files are kept
+		s.ErrorContains(svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeXLS), "asd",
+			&request.EventRequestExport{}), "could not send export email")
+		s.FileExists("./temps/exports/asd.xlsx")
+		s.ErrorContains(svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypePDF), "asd",
+			&request.EventRequestExport{}), "could not send export email")
+		s.FileExists("./temps/exports/asd.pdf")
+		s.ErrorContains(svc.GenerateTicket(context.TODO(), "asd", 1), "could not send ticket email")
+		s.FileExists("./temps/exports/gen11tix.pdf")
+		calendar, err := os.ReadFile("./temps/exports/gen11tix.ics")
+		s.Nil(err)
+		s.Contains(string(calendar), `ORGANIZER;CN="lorem":mailto:lorem@ipsum.id`)
+		if err := os.RemoveAll("./temps"); err != nil {
+			s.T().Fatalf("Failed to remove directory: %s", err)
+		}
+		pqRepo.AssertExpectations(s.T())
+	}
+	// the logo is fetched once by the files of the event, the missing one too
+	s.Equal(int32(2), fetched.Load())
+}
+
+// TIX TICKET TEMPLATE IMPL
+func (s *tixServiceTestSuite) Test_FetchTicketTemplate_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("built-in template", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		data, err := svc.FetchTicketTemplate(context.TODO(), "asd")
+		s.Nil(err)
+		s.True(data.IsDefault)
+		s.Equal(common.DefaultTicketTemplate, data.Template)
+		s.NotEmpty(data.Placeholders)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("stored template", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+			ID:             1,
+			GoogleFormID:   "asd",
+			TicketTemplate: sql.NullString{String: "# lorem", Valid: true},
+		}, nil).Once()
+		data, err := svc.FetchTicketTemplate(context.TODO(), "asd")
+		s.Nil(err)
+		s.False(data.IsDefault)
+		s.Equal("# lorem", data.Template)
+		pqRepo.AssertExpectations(t)
+	})
+}
+func (s *tixServiceTestSuite) Test_FetchTicketTemplate_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(nil, errors.New("lorem")).Once()
+	data, err := svc.FetchTicketTemplate(context.TODO(), "asd")
+	s.Nil(data)
+	s.Equal(errors.New("lorem"), err)
+	pqRepo.AssertExpectations(s.T())
+}
+
+func (s *tixServiceTestSuite) Test_UpdateTicketTemplate_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	event := &entity.Event{ID: 1, GoogleFormID: "asd", Name: "tix", EventDate: int32(time.Now().Unix())}
+	s.T().Run("store template", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+		pqRepo.On("UpdateEventTicketTemplate", mock.Anything, "asd", sql.NullString{
+			String: common.DefaultTicketTemplate, Valid: true,
+		}).Return(nil).Once()
+		data, err := svc.UpdateTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{
+			Template: common.DefaultTicketTemplate + "\n",
+		})
+		s.Nil(err)
+		s.False(data.IsDefault)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("reset to the built-in template", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+		pqRepo.On("UpdateEventTicketTemplate", mock.Anything, "asd", sql.NullString{}).Return(nil).Once()
+		data, err := svc.UpdateTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{})
+		s.Nil(err)
+		s.True(data.IsDefault)
+		s.Equal(common.DefaultTicketTemplate, data.Template)
+		pqRepo.AssertExpectations(t)
+	})
+}
+func (s *tixServiceTestSuite) Test_UpdateTicketTemplate_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	event := &entity.Event{ID: 1, GoogleFormID: "asd", Name: "tix"}
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, errors.New("lorem")).Once()
+		data, err := svc.UpdateTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{})
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("invalid template", func(t *testing.T) {
+		for _, ticketTemplate := range []string{
+			"{{ .Event.Name ",
+			"{{ .Lorem }}",
+			"@lorem",
+		} {
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+			data, err := svc.UpdateTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{
+				Template: ticketTemplate,
+			})
+			s.Nil(data)
+			s.ErrorIs(err, common.ErrInvalidTicketTemplate)
+		}
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("error update", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(event, nil).Once()
+		pqRepo.On("UpdateEventTicketTemplate", mock.Anything, "asd", mock.Anything).
+			Return(errors.New("lorem")).Once()
+		data, err := svc.UpdateTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{
+			Template: "# lorem",
+		})
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+		pqRepo.AssertExpectations(t)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_PreviewTicketTemplate_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithTicketSigningSecret("secret"))
+	for _, tt := range []struct {
+		name     string
+		stored   string
+		template string
+	}{
+		{"built-in template", "", ""},
+		{"stored template", common.DefaultTicketTemplate, ""},
+		{"given template", "", "{{ .Logo }}\n**{{ index .Participant.Answers \"Company\" }}**\n\n> {{ .TicketCode }}"},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{
+				ID:             1,
+				GoogleFormID:   "asd",
+				Name:           "tix",
+				EventDate:      int32(time.Now().Unix()),
+				TicketTemplate: sql.NullString{String: tt.stored, Valid: tt.stored != ""},
+			}, nil).Once()
+			data, err := svc.PreviewTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{
+				Template: tt.template,
+			})
+			s.Nil(err)
+			s.True(bytes.HasPrefix(data, []byte("%PDF-")))
+			pqRepo.AssertExpectations(t)
+		})
+	}
+}
+func (s *tixServiceTestSuite) Test_PreviewTicketTemplate_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(nil, errors.New("lorem")).Once()
+		data, err := svc.PreviewTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{})
+		s.Nil(data)
+		s.Equal(errors.New("lorem"), err)
+		pqRepo.AssertExpectations(t)
+	})
+	s.T().Run("invalid template", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+			Return(&entity.Event{ID: 1, GoogleFormID: "asd"}, nil).Once()
+		data, err := svc.PreviewTicketTemplate(context.TODO(), "asd", &request.EventRequestTicketTemplate{
+			Template: strings.Repeat("lorem\n", common.TicketTemplateMaxLines+1),
+		})
+		s.Nil(data)
+		s.ErrorIs(err, common.ErrInvalidTicketTemplate)
+		pqRepo.AssertExpectations(t)
+	})
+}
+
 // TIX EVENT IMPL
 func (s *tixServiceTestSuite) Test_FetchEvents_ShouldSuccess() {
 	rc := redis.NewClient(&redis.Options{
@@ -1046,6 +3535,7 @@ func (s *tixServiceTestSuite) Test_FetchOverview_ShouldSuccess() {
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		data, err := svc.FetchOverview(context.TODO(), "asd")
 		s.NotNil(data)
 		s.Nil(err)
@@ -1119,6 +3609,7 @@ func (s *tixServiceTestSuite) Test_FetchOverview_ShouldError() {
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
+		pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1).Once()
 		data, err := svc.FetchOverview(context.TODO(), "asd")
 		s.NotNil(data)
 		s.Nil(err)
@@ -1127,6 +3618,50 @@ func (s *tixServiceTestSuite) Test_FetchOverview_ShouldError() {
 	})
 }
 
+func (s *tixServiceTestSuite) Test_FetchParticipants_FilterTicketDelivery() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(s.T()).Addr(),
+	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient))
+	approvedAt := sql.NullInt32{Int32: int32(time.Now().Unix()), Valid: true}
+	failed := entity.LastTicketDelivery{
+		Status:      sql.NullString{String: string(common.TicketDeliveryFailed), Valid: true},
+		Error:       sql.NullString{String: "dial tcp: connection refused", Valid: true},
+		AttemptedAt: sql.NullInt32{Int32: 2, Valid: true},
+	}
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("GetAllParticipants", mock.Anything, int32(1), "", int64(0), int64(0), int32(0), "", "").
+		Return([]*entity.Participant{
+			{ID: 1, EventID: 1, ApprovedAt: approvedAt, LastTicketDelivery: failed},
+			{ID: 2, EventID: 1, ApprovedAt: approvedAt, TicketSentAt: sql.NullInt32{Int32: 3, Valid: true},
+				LastTicketDelivery: entity.LastTicketDelivery{
+					Status:      sql.NullString{String: string(common.TicketDeliverySent), Valid: true},
+					MessageID:   sql.NullString{String: "<a1b2@bakode.xyz>", Valid: true},
+					AttemptedAt: sql.NullInt32{Int32: 3, Valid: true},
+				}},
+			{ID: 3, EventID: 1, ApprovedAt: approvedAt},
+			// the declined participant is not waiting for the ticket anymore
+			{ID: 4, EventID: 1, DeclinedAt: approvedAt, LastTicketDelivery: failed},
+		}, nil).Once()
+	data, err := svc.FetchParticipants(context.TODO(), "asd", &request.EventRequestParticipantFilter{})
+	s.Nil(err)
+	s.Len(data, 4)
+	s.Nil(data[2].TicketDelivery)
+	s.Equal(int32(3), *data[1].TicketSentAt)
+	s.Equal("<a1b2@bakode.xyz>", data[1].TicketDelivery.MessageID)
+	// the second fetch hit the cache
+	data, err = svc.FetchParticipants(context.TODO(), "asd", &request.EventRequestParticipantFilter{
+		TicketDelivery: string(common.TicketDeliveryFailed),
+	})
+	s.Nil(err)
+	s.Len(data, 1)
+	s.Equal(int32(1), data[0].ID)
+	s.Equal("dial tcp: connection refused", data[0].TicketDelivery.Error)
+	pqRepo.AssertExpectations(s.T())
+}
 func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldSuccess() {
 	miniRedis := miniredis.RunT(s.T())
 	redisClient := redis.NewClient(&redis.Options{
@@ -1184,7 +3719,7 @@ func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldSuccess() {
 				Valid: true,
 			},
 		}}, nil).Once()
-		data, err := svc.FetchParticipants(context.TODO(), "asd")
+		data, err := svc.FetchParticipants(context.TODO(), "asd", nil)
 		s.Nil(err)
 		s.NotNil(data)
 		pqRepo.AssertExpectations(s.T())
@@ -1224,7 +3759,7 @@ func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldSuccess() {
 		if jsonData, err := json.Marshal(participants); err == nil {
 			redisClient.Set(context.TODO(), cacheKey, jsonData, common.EventParticipantCacheTimeDuration)
 		}
-		data, err := svc.FetchParticipants(context.TODO(), "asd")
+		data, err := svc.FetchParticipants(context.TODO(), "asd", nil)
 		s.Nil(err)
 		s.NotNil(data)
 		pqRepo.AssertExpectations(s.T())
@@ -1241,7 +3776,7 @@ func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldError() {
 		service.WithRedisCache(redisClient))
 	s.T().Run("error get event", func(t *testing.T) {
 		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-		data, err := svc.FetchParticipants(context.TODO(), "asd")
+		data, err := svc.FetchParticipants(context.TODO(), "asd", nil)
 		s.NotNil(err)
 		s.Nil(data)
 		pqRepo.AssertExpectations(s.T())
@@ -1265,7 +3800,7 @@ func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldError() {
 			},
 		}, nil).Once()
 		pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
-		data, err := svc.FetchParticipants(context.TODO(), "asd")
+		data, err := svc.FetchParticipants(context.TODO(), "asd", nil)
 		s.NotNil(err)
 		s.Nil(data)
 		pqRepo.AssertExpectations(s.T())
@@ -1277,22 +3812,68 @@ func (s *tixServiceTestSuite) Test_FetchParticipants_ShouldError() {
 		}); err == nil {
 			redisClient.Set(context.TODO(), cacheKey, jsonData, common.EventParticipantCacheTimeDuration)
 		}
-		data, err := svc.FetchParticipants(context.TODO(), "asd")
+		data, err := svc.FetchParticipants(context.TODO(), "asd", nil)
 		s.NotNil(err)
 		s.Nil(data)
 		pqRepo.AssertExpectations(s.T())
 	})
 }
 
+func (s *tixServiceTestSuite) Test_FetchParticipantChanges_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("GetParticipantChanges", mock.Anything, int32(1), int32(7)).Return([]*entity.ParticipantChange{{
+		ID:            1,
+		EventID:       1,
+		ParticipantID: 7,
+		Field:         "phone",
+		OldValue:      "080888982828",
+		NewValue:      "08111111111",
+		CreatedAt:     sql.NullInt32{Int32: 1686045600, Valid: true},
+	}}, nil).Once()
+	items, err := svc.FetchParticipantChanges(context.TODO(), "asd", 7)
+	s.Nil(err)
+	s.Equal(len(items), 1)
+	s.Equal(items[0].Field, "phone")
+	s.Equal(items[0].CreatedAt, int32(1686045600))
+}
+func (s *tixServiceTestSuite) Test_FetchParticipantChanges_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	s.T().Run("error get event", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		items, err := svc.FetchParticipantChanges(context.TODO(), "asd", 7)
+		s.NotNil(err)
+		s.Nil(items)
+	})
+	s.T().Run("error get changes", func(t *testing.T) {
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetParticipantChanges", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		items, err := svc.FetchParticipantChanges(context.TODO(), "asd", 7)
+		s.NotNil(err)
+		s.Nil(items)
+	})
+}
+
 func (s *tixServiceTestSuite) Test_PublishSyncEventDataQueue_ShouldSuccess() {
 	miniRedis := miniredis.RunT(s.T())
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: miniRedis.Addr(),
 	})
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("Publish", mock.Anything, common.ReqSyncEventQueueKey, mock.Anything).Return("1-0", nil).Once()
 	svc := service.NewTixService(
-		service.WithRedisCache(redisClient))
-	err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithJobQueue(jobQueue))
+	jobID, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
 	s.Nil(err)
+	s.Equal(int32(1), jobID)
+	jobQueue.AssertExpectations(s.T())
 }
 func (s *tixServiceTestSuite) Test_PublishSyncEventDataQueue_ShouldError() {
 	s.T().Run("error get", func(t *testing.T) {
@@ -1303,7 +3884,7 @@ func (s *tixServiceTestSuite) Test_PublishSyncEventDataQueue_ShouldError() {
 		miniRedis.Close()
 		_ = redisClient.Close()
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		_, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
 		s.NotNil(err)
 	})
 	s.T().Run("error rate limiter", func(t *testing.T) {
@@ -1315,13 +3896,66 @@ func (s *tixServiceTestSuite) Test_PublishSyncEventDataQueue_ShouldError() {
 			common.ReqSyncEventQueueKey, "asd")
 		redisClient.Set(context.TODO(), cacheKey, "asd", 1)
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		_, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
 		s.NotNil(err)
 		s.Equal(err, common.ErrRateLimitingPushQueue)
 		redisClient.Del(context.TODO(), cacheKey)
 		miniRedis.Close()
 		_ = redisClient.Close()
 	})
+	s.T().Run("error event", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error insert job", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(0), errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error publish", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusFailed)
+		}), mock.Anything).Return(nil).Once()
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient),
+			service.WithJobQueue(jobQueue))
+		_, err := svc.PublishSyncEventDataQueue(context.TODO(), "asd")
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
 }
 
 func (s *tixServiceTestSuite) Test_UpdateParticipantStatus_ShouldSuccess() {
@@ -1330,9 +3964,14 @@ func (s *tixServiceTestSuite) Test_UpdateParticipantStatus_ShouldSuccess() {
 		Addr: miniRedis.Addr(),
 	})
 	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil)
+	pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil)
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return("1-0", nil)
 	svc := service.NewTixService(
 		service.WithPostgreSQLRepository(pqRepo),
-		service.WithRedisCache(redisClient))
+		service.WithRedisCache(redisClient),
+		service.WithJobQueue(jobQueue))
 	s.T().Run("approved", func(t *testing.T) {
 		pqRepo.On("UpdateParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 		err := svc.UpdateParticipantStatus(context.TODO(), "asd", 1, &request.EventRequestUpdateParticipant{
@@ -1365,9 +4004,20 @@ func (s *tixServiceTestSuite) Test_PublishExportEventDataQueue_ShouldSuccess() {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: miniRedis.Addr(),
 	})
-	svc := service.NewTixService(service.WithRedisCache(redisClient))
-	err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id")
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("Publish", mock.Anything, common.ReqExpEventDataQueueKey, mock.Anything).Return("1-0", nil).Once()
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithJobQueue(jobQueue))
+	jobID, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+		&request.EventRequestExport{})
 	s.Nil(err)
+	s.Equal(int32(1), jobID)
+	jobQueue.AssertExpectations(s.T())
 }
 func (s *tixServiceTestSuite) Test_PublishExportEventDataQueue_ShouldError() {
 	s.T().Run("error get", func(t *testing.T) {
@@ -1378,7 +4028,8 @@ func (s *tixServiceTestSuite) Test_PublishExportEventDataQueue_ShouldError() {
 		miniRedis.Close()
 		_ = redisClient.Close()
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id")
+		_, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+			&request.EventRequestExport{})
 		s.NotNil(err)
 	})
 	s.T().Run("error rate limiter", func(t *testing.T) {
@@ -1391,13 +4042,70 @@ func (s *tixServiceTestSuite) Test_PublishExportEventDataQueue_ShouldError() {
 
 		redisClient.Set(context.TODO(), cacheKey, "asd", 1)
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id")
+		_, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+			&request.EventRequestExport{})
 		s.NotNil(err)
 		s.Equal(err, common.ErrRateLimitingPushQueue)
 		redisClient.Del(context.TODO(), cacheKey)
 		miniRedis.Close()
 		_ = redisClient.Close()
 	})
+	s.T().Run("error event", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+			&request.EventRequestExport{})
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error insert job", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(0), errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+			&request.EventRequestExport{})
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error publish", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusFailed)
+		}), mock.Anything).Return(nil).Once()
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient),
+			service.WithJobQueue(jobQueue))
+		_, err := svc.PublishExportEventDataQueue(context.TODO(), "asd", "pdf", "asd@hello.id",
+			&request.EventRequestExport{})
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
 }
 
 func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketQueue_ShouldSuccess() {
@@ -1405,9 +4113,19 @@ func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketQueue_ShouldSuccess
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: miniRedis.Addr(),
 	})
-	svc := service.NewTixService(service.WithRedisCache(redisClient))
-	err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("Publish", mock.Anything, common.ReqGenEventTixQueueKey, mock.Anything).Return("1-0", nil).Once()
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient),
+		service.WithJobQueue(jobQueue))
+	jobID, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
 	s.Nil(err)
+	s.Equal(int32(1), jobID)
+	jobQueue.AssertExpectations(s.T())
 }
 func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketQueue_ShouldError() {
 	s.T().Run("error get", func(t *testing.T) {
@@ -1418,7 +4136,7 @@ func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketQueue_ShouldError()
 		miniRedis.Close()
 		_ = redisClient.Close()
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+		_, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
 		s.NotNil(err)
 	})
 	s.T().Run("error rate limiter", func(t *testing.T) {
@@ -1430,13 +4148,477 @@ func (s *tixServiceTestSuite) Test_PublishGenerateEventTicketQueue_ShouldError()
 			common.ReqGenEventTixQueueKey, "asd", 1)
 		redisClient.Set(context.TODO(), cacheKey, "asd", 1)
 		svc := service.NewTixService(service.WithRedisCache(redisClient))
-		err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+		_, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
 		s.NotNil(err)
 		s.Equal(err, common.ErrRateLimitingPushQueue)
 		redisClient.Del(context.TODO(), cacheKey)
 		miniRedis.Close()
 		_ = redisClient.Close()
 	})
+	s.T().Run("error event", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error insert job", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(0), errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		_, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+	s.T().Run("error publish", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("InsertJob", mock.Anything, mock.Anything).Return(int32(1), nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusFailed)
+		}), mock.Anything).Return(nil).Once()
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient),
+			service.WithJobQueue(jobQueue))
+		_, err := svc.PublishGenerateEventTicketQueue(context.TODO(), "asd", 1)
+		s.NotNil(err)
+		miniRedis.Close()
+		_ = redisClient.Close()
+	})
+}
+
+func (s *tixServiceTestSuite) Test_FetchDeadLetterJobs_ShouldSuccess() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("ListDeadLetters", mock.Anything, "", int64(common.DeadLetterPageSize)).Return([]*entity.DeadLetterJob{{
+		ID:       "1-0",
+		Queue:    common.ReqGenEventTixQueueKey,
+		Payload:  []byte(`{"google_form_id":"asd","participant_id":1}`),
+		Attempts: 5,
+		Error:    "lorem",
+		FailedAt: 1686045600,
+	}}, nil).Once()
+	svc := service.NewTixService(service.WithJobQueue(jobQueue))
+	item, err := svc.FetchDeadLetterJobs(context.TODO(), &request.JobRequestDeadLetters{})
+	s.Nil(err)
+	s.Equal(len(item.Items), 1)
+	s.Equal(item.Items[0].Payload, `{"google_form_id":"asd","participant_id":1}`)
+	s.Equal(item.Items[0].Attempts, 5)
+	// the last page
+	s.Empty(item.NextCursor)
+
+	jobQueue.On("ListDeadLetters", mock.Anything, "3-0", int64(2)).
+		Return([]*entity.DeadLetterJob{{ID: "2-0"}, {ID: "1-0"}}, nil).Once()
+	item, err = svc.FetchDeadLetterJobs(context.TODO(), &request.JobRequestDeadLetters{Cursor: "3-0", Limit: 2})
+	s.Nil(err)
+	s.Equal(len(item.Items), 2)
+	s.Equal("1-0", item.NextCursor)
+	jobQueue.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_FetchDeadLetterJobs_ShouldError() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("ListDeadLetters", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+	svc := service.NewTixService(service.WithJobQueue(jobQueue))
+	item, err := svc.FetchDeadLetterJobs(context.TODO(), &request.JobRequestDeadLetters{})
+	s.NotNil(err)
+	s.Nil(item)
+}
+
+func (s *tixServiceTestSuite) Test_FetchDeadLetterJob_ShouldSuccess() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("GetDeadLetter", mock.Anything, "1-0").Return(&entity.DeadLetterJob{
+		ID:    "1-0",
+		Queue: common.ReqSyncEventQueueKey,
+	}, nil).Once()
+	svc := service.NewTixService(service.WithJobQueue(jobQueue))
+	item, err := svc.FetchDeadLetterJob(context.TODO(), "1-0")
+	s.Nil(err)
+	s.Equal(item.Queue, common.ReqSyncEventQueueKey)
+}
+func (s *tixServiceTestSuite) Test_FetchDeadLetterJob_ShouldError() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("GetDeadLetter", mock.Anything, mock.Anything).Return(nil, common.ErrDeadLetterJobNotFound).Once()
+	svc := service.NewTixService(service.WithJobQueue(jobQueue))
+	item, err := svc.FetchDeadLetterJob(context.TODO(), "1-0")
+	s.Equal(err, common.ErrDeadLetterJobNotFound)
+	s.Nil(item)
+}
+
+func (s *tixServiceTestSuite) Test_RetryDeadLetterJob_ShouldSuccess() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("GetDeadLetter", mock.Anything, "1-0").Return(&entity.DeadLetterJob{
+		ID:      "1-0",
+		Queue:   common.ReqSyncEventQueueKey,
+		Payload: []byte(`{"google_form_id":"asd","job_id":1}`),
+	}, nil).Once()
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{
+		ID:     1,
+		Status: string(common.JobStatusFailed),
+	}, nil).Once()
+	var queued bool
+	pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+		return job.Status == string(common.JobStatusQueued) && !job.FinishedAt.Valid
+	}), mock.Anything).Run(func(mock.Arguments) { queued = true }).Return(nil).Once()
+	// the job is queued before it is published
+	jobQueue.On("RetryDeadLetter", mock.Anything, "1-0").
+		Run(func(mock.Arguments) { s.True(queued) }).Return("2-0", nil).Once()
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithJobQueue(jobQueue))
+	jobID, err := svc.RetryDeadLetterJob(context.TODO(), "1-0")
+	s.Nil(err)
+	s.Equal(jobID, "2-0")
+	pqRepo.AssertExpectations(s.T())
+}
+func (s *tixServiceTestSuite) Test_RetryDeadLetterJob_ShouldError() {
+	s.T().Run("error get", func(t *testing.T) {
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("GetDeadLetter", mock.Anything, mock.Anything).Return(nil, common.ErrDeadLetterJobNotFound).Once()
+		svc := service.NewTixService(service.WithJobQueue(jobQueue))
+		jobID, err := svc.RetryDeadLetterJob(context.TODO(), "1-0")
+		s.Equal(err, common.ErrDeadLetterJobNotFound)
+		s.Empty(jobID)
+	})
+	s.T().Run("error retry", func(t *testing.T) {
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("GetDeadLetter", mock.Anything, mock.Anything).Return(&entity.DeadLetterJob{ID: "1-0"}, nil).Once()
+		jobQueue.On("RetryDeadLetter", mock.Anything, mock.Anything).Return("", errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithJobQueue(jobQueue))
+		jobID, err := svc.RetryDeadLetterJob(context.TODO(), "1-0")
+		s.NotNil(err)
+		s.Empty(jobID)
+	})
+	s.T().Run("error queue job", func(t *testing.T) {
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("GetDeadLetter", mock.Anything, mock.Anything).Return(&entity.DeadLetterJob{
+			ID: "1-0", Payload: []byte(`{"job_id":1}`),
+		}, nil).Once()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithJobQueue(jobQueue))
+		jobID, err := svc.RetryDeadLetterJob(context.TODO(), "1-0")
+		s.NotNil(err)
+		s.Empty(jobID)
+		// nothing is published
+		jobQueue.AssertNotCalled(t, "RetryDeadLetter", mock.Anything, mock.Anything)
+	})
+	s.T().Run("error retry tracked job", func(t *testing.T) {
+		jobQueue := new(mocks.IJobQueue)
+		jobQueue.On("GetDeadLetter", mock.Anything, mock.Anything).Return(&entity.DeadLetterJob{
+			ID: "1-0", Payload: []byte(`{"job_id":1}`),
+		}, nil).Once()
+		jobQueue.On("RetryDeadLetter", mock.Anything, mock.Anything).Return("", errors.New("lorem")).Once()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{ID: 1}, nil).Twice()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusQueued)
+		}), mock.Anything).Return(nil).Once()
+		// the job goes back to failed
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusFailed) && job.Error.String == "lorem"
+		}), mock.Anything).Return(nil).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithJobQueue(jobQueue))
+		jobID, err := svc.RetryDeadLetterJob(context.TODO(), "1-0")
+		s.NotNil(err)
+		s.Empty(jobID)
+		pqRepo.AssertExpectations(t)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_DiscardDeadLetterJob() {
+	jobQueue := new(mocks.IJobQueue)
+	jobQueue.On("DiscardDeadLetter", mock.Anything, "1-0").Return(nil).Once()
+	svc := service.NewTixService(service.WithJobQueue(jobQueue))
+	err := svc.DiscardDeadLetterJob(context.TODO(), "1-0")
+	s.Nil(err)
+}
+
+func (s *tixServiceTestSuite) Test_FetchJob_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{
+		ID:         1,
+		EventID:    1,
+		Queue:      common.ReqExpEventDataQueueKey,
+		Status:     string(common.JobStatusSucceeded),
+		Attempts:   1,
+		Result:     sql.NullString{String: "pdf export sent to asd@hello.id", Valid: true},
+		QueuedAt:   sql.NullInt32{Int32: 1, Valid: true},
+		StartedAt:  sql.NullInt32{Int32: 2, Valid: true},
+		FinishedAt: sql.NullInt32{Int32: 3, Valid: true},
+	}, nil).Once()
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	item, err := svc.FetchJob(context.TODO(), 1)
+	s.Nil(err)
+	s.Equal(string(common.JobStatusSucceeded), item.Status)
+	s.Equal("pdf export sent to asd@hello.id", item.Result)
+	s.Equal(int32(2), *item.StartedAt)
+	s.Equal(int32(3), *item.FinishedAt)
+}
+func (s *tixServiceTestSuite) Test_FetchJob_ShouldError() {
+	s.T().Run("not found", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.FetchJob(context.TODO(), 1)
+		s.Equal(err, common.ErrJobNotFound)
+		s.Nil(item)
+	})
+	s.T().Run("error query", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		item, err := svc.FetchJob(context.TODO(), 1)
+		s.NotNil(err)
+		s.Nil(item)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_FetchEventJobs_ShouldSuccess() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("GetJobsByEventID", mock.Anything, int32(1), int32(common.JobHistoryLimit)).Return([]*entity.Job{
+		{ID: 2, EventID: 1, Queue: common.ReqSyncEventQueueKey, Status: string(common.JobStatusRunning)},
+		{ID: 1, EventID: 1, Queue: common.ReqSyncEventQueueKey, Status: string(common.JobStatusFailed)},
+	}, nil).Once()
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	items, err := svc.FetchEventJobs(context.TODO(), "asd")
+	s.Nil(err)
+	s.Equal(2, len(items))
+	s.Nil(items[0].FinishedAt)
+}
+func (s *tixServiceTestSuite) Test_FetchEventJobs_ShouldError() {
+	s.T().Run("error event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		items, err := svc.FetchEventJobs(context.TODO(), "asd")
+		s.NotNil(err)
+		s.Nil(items)
+	})
+	s.T().Run("error jobs", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		pqRepo.On("GetJobsByEventID", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		items, err := svc.FetchEventJobs(context.TODO(), "asd")
+		s.NotNil(err)
+		s.Nil(items)
+	})
+}
+
+func (s *tixServiceTestSuite) Test_UpdateJobStatus_ShouldSuccess() {
+	s.T().Run("running", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{ID: 1}, nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusRunning) &&
+				job.Attempts == 1 && job.StartedAt.Valid && !job.FinishedAt.Valid
+		}), mock.Anything).Return(nil).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		err := svc.UpdateJobStatus(context.TODO(), 1, common.JobStatusRunning, 1, "", "")
+		s.Nil(err)
+		pqRepo.AssertExpectations(s.T())
+	})
+	s.T().Run("succeeded", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{ID: 1}, nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusSucceeded) &&
+				job.Result.String == "lorem" && job.FinishedAt.Valid
+		}), mock.Anything).Return(nil).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		err := svc.UpdateJobStatus(context.TODO(), 1, common.JobStatusSucceeded, 1, "lorem", "")
+		s.Nil(err)
+		pqRepo.AssertExpectations(s.T())
+	})
+	s.T().Run("queued for the next attempt", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetJobByID", mock.Anything, int32(1)).Return(&entity.Job{ID: 1}, nil).Once()
+		pqRepo.On("UpdateJob", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+			return job.Status == string(common.JobStatusQueued) &&
+				job.Error.String == "lorem" && !job.FinishedAt.Valid
+		}), mock.Anything).Return(nil).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		err := svc.UpdateJobStatus(context.TODO(), 1, common.JobStatusQueued, 1, "", "lorem")
+		s.Nil(err)
+		pqRepo.AssertExpectations(s.T())
+	})
+}
+func (s *tixServiceTestSuite) Test_UpdateJobStatus_ShouldError() {
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetJobByID", mock.Anything, mock.Anything).Return(nil, errors.New("lorem")).Once()
+	svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+	err := svc.UpdateJobStatus(context.TODO(), 1, common.JobStatusRunning, 1, "", "")
+	s.NotNil(err)
+}
+
+func (s *tixServiceTestSuite) Test_SubscribeEventNotifications_ShouldSuccess() {
+	miniRedis := miniredis.RunT(s.T())
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: miniRedis.Addr(),
+	})
+	defer func() { _ = redisClient.Close() }()
+	pqRepo := new(mocks.IPostgreSQLRepository)
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").Return(&entity.Event{ID: 1}, nil).Once()
+	pqRepo.On("UpdateParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	svc := service.NewTixService(
+		service.WithPostgreSQLRepository(pqRepo),
+		service.WithRedisCache(redisClient))
+	ctx, cancel := context.WithCancel(context.Background())
+	notifications, err := svc.SubscribeEventNotifications(ctx, "asd")
+	s.Nil(err)
+	err = svc.UpdateParticipantStatus(context.TODO(), "asd", 1, &request.EventRequestUpdateParticipant{
+		Status:         string(common.ParticipantRequestDeclined),
+		DeclinedReason: "lorem",
+	})
+	s.Nil(err)
+	select {
+	case notification := <-notifications:
+		s.Equal(string(common.NotificationParticipantStatusUpdated), notification.Type)
+		s.Equal("asd", notification.GoogleFormID)
+		s.Equal(map[string]any{
+			"participant_id": float64(1),
+			"status":         string(common.ParticipantRequestDeclined),
+		}, notification.Data)
+	case <-time.After(time.Second):
+		s.Fail("notification not received")
+	}
+	cancel()
+	select {
+	case _, ok := <-notifications:
+		s.False(ok)
+	case <-time.After(time.Second):
+		s.Fail("notification channel not closed")
+	}
+}
+func (s *tixServiceTestSuite) Test_SubscribeEventNotifications_ShouldError() {
+	s.T().Run("error event", func(t *testing.T) {
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Once()
+		svc := service.NewTixService(service.WithPostgreSQLRepository(pqRepo))
+		notifications, err := svc.SubscribeEventNotifications(context.TODO(), "asd")
+		s.NotNil(err)
+		s.Nil(notifications)
+	})
+	s.T().Run("error subscribe", func(t *testing.T) {
+		miniRedis := miniredis.RunT(s.T())
+		redisClient := redis.NewClient(&redis.Options{
+			Addr: miniRedis.Addr(),
+		})
+		miniRedis.Close()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		pqRepo.On("GetEventByGoogleFormID", mock.Anything, mock.Anything).Return(&entity.Event{ID: 1}, nil).Once()
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithRedisCache(redisClient))
+		notifications, err := svc.SubscribeEventNotifications(context.TODO(), "asd")
+		s.NotNil(err)
+		s.Nil(notifications)
+		_ = redisClient.Close()
+	})
+}
+
+// exportUnsent run the export whose email can not be sent, so the export
+// file is left in the export directory, the repository call can be held.
+func exportUnsent(t *testing.T, svc domain.ITixService, pqRepo *mocks.IPostgreSQLRepository, hold <-chan struct{}) error {
+	pqRepo.On("GetEventByGoogleFormID", mock.Anything, "asd").
+		Return(&entity.Event{ID: 1, GoogleFormID: "asd", Name: "asd"}, nil).
+		Run(func(mock.Arguments) { <-hold }).Once()
+	pqRepo.On("GetAllParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*entity.Participant{{ID: 1, EventID: 1, Name: "asd", Email: "asd@asd.id"}}, nil).Once()
+	pqRepo.On("CountParticipants", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(1).Times(4)
+	defer pqRepo.AssertExpectations(t)
+	return svc.ExportEvent(context.TODO(), "asd", string(common.ExportTypeCSV), "lorem@lorem.id",
+		&request.EventRequestExport{})
+}
+
+func (s *tixServiceTestSuite) Test_Shutdown_ShouldSuccess() {
+	s.T().Run("without export directory", func(t *testing.T) {
+		svc := service.NewTixService()
+		s.Nil(svc.Shutdown(context.TODO()))
+	})
+	s.T().Run("remove the export files of this process", func(t *testing.T) {
+		s.Nil(os.MkdirAll(common.ExportTempDir, os.ModePerm))
+		defer func() { _ = os.RemoveAll("./temps") }()
+		// saved by the other instance sharing the directory
+		other := filepath.Join(common.ExportTempDir, "lorem.pdf")
+		s.Nil(os.WriteFile(other, []byte("lorem"), 0600))
+		keep := filepath.Join(common.ExportTempDir, ".gitkeep")
+		s.Nil(os.WriteFile(keep, nil, 0600))
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(&gomail.Dialer{}))
+		hold := make(chan struct{})
+		close(hold)
+		s.Error(exportUnsent(t, svc, pqRepo, hold))
+		export := filepath.Join(common.ExportTempDir, "asd.csv")
+		s.FileExists(export)
+		s.Nil(svc.Shutdown(context.TODO()))
+		s.NoFileExists(export)
+		s.FileExists(other)
+		s.FileExists(keep)
+	})
+}
+func (s *tixServiceTestSuite) Test_Shutdown_ShouldError() {
+	s.T().Run("context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		svc := service.NewTixService()
+		s.ErrorIs(svc.Shutdown(ctx), context.Canceled)
+	})
+	s.T().Run("keep the files while the export is running", func(t *testing.T) {
+		s.Nil(os.MkdirAll(common.ExportTempDir, os.ModePerm))
+		defer func() { _ = os.RemoveAll("./temps") }()
+		pqRepo := new(mocks.IPostgreSQLRepository)
+		svc := service.NewTixService(
+			service.WithPostgreSQLRepository(pqRepo),
+			service.WithMailer(&gomail.Dialer{}))
+		hold := make(chan struct{})
+		close(hold)
+		s.Error(exportUnsent(t, svc, pqRepo, hold))
+		export := filepath.Join(common.ExportTempDir, "asd.csv")
+
+		// the next export hold the service lock past the deadline
+		hold = make(chan struct{})
+		done := make(chan error)
+		go func() { done <- exportUnsent(t, svc, pqRepo, hold) }()
+		time.Sleep(50 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		s.ErrorIs(svc.Shutdown(ctx), context.DeadlineExceeded)
+		s.FileExists(export)
+		close(hold)
+		s.Error(<-done)
+	})
 }
 
 func TestTixService(t *testing.T) {
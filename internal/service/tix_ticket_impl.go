@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"github.com/aasumitro/tix/pkg/token"
+	"strings"
+	"time"
+)
+
+// FetchTicketKeyBundle return the event public key, an offline gate
+// use it to verify the ticket tokens without calling the api.
+func (service *tixService) FetchTicketKeyBundle(
+	ctx context.Context,
+	googleFormID string,
+) (item *response.TicketKeyBundleResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := service.ticketKey(event.GoogleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.TicketKeyBundleResponse{
+		EventID:      event.ID,
+		GoogleFormID: event.GoogleFormID,
+		Algorithm:    token.TicketAlgorithm,
+		PublicKey:    base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		ExpiresAt:    ticketTokenExpiry(event).Unix(),
+	}, nil
+}
+
+// VerifyTicket verify the ticket token and return the current state
+// of the ticket holder, the ticket is not checked in.
+func (service *tixService) VerifyTicket(
+	ctx context.Context,
+	googleFormID, ticketToken string,
+) (item *response.TicketVerificationResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := service.verifyTicketToken(event, ticketToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := service.ticketHolder(ctx, event, claim.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.TicketVerificationResponse{
+		ParticipantID: participant.ID,
+		Name:          participant.Name,
+		Email:         participant.Email,
+		TicketCode:    participant.TicketCode.String,
+		Status: func() string {
+			if participant.ApprovedAt.Valid {
+				return "approved"
+			}
+			if participant.DeclinedAt.Valid {
+				return "declined"
+			}
+			return "waiting approval"
+		}(),
+		CheckedInAt: func() *int32 {
+			if participant.CheckedInAt.Valid {
+				return &participant.CheckedInAt.Int32
+			}
+			return nil
+		}(),
+		ExpiresAt: claim.ExpiresAt.Unix(),
+	}, nil
+}
+
+// ticketKey derive the signing key of the event.
+func (service *tixService) ticketKey(googleFormID string) (ed25519.PrivateKey, error) {
+	if service.ticketSigningSecret == "" {
+		return nil, common.ErrTicketSigningDisabled
+	}
+	return token.NewTicketKey(service.ticketSigningSecret, googleFormID), nil
+}
+
+// ticketQRCode return the content of the ticket QR code, the signed ticket
+// token or the ticket code when the ticket signing is not configured.
+func (service *tixService) ticketQRCode(
+	event *entity.Event,
+	participantID int32,
+	ticketCode string,
+) (string, error) {
+	key, err := service.ticketKey(event.GoogleFormID)
+	if err != nil {
+		return ticketCode, nil
+	}
+	return token.SignTicket(key, event.ID, participantID, ticketTokenExpiry(event))
+}
+
+// verifyTicketToken verify the ticket token scanned at the given time,
+// the failure is returned as a check-in error.
+func (service *tixService) verifyTicketToken(
+	event *entity.Event,
+	ticketToken string,
+	scannedAt time.Time,
+) (*token.TicketClaim, error) {
+	key, err := service.ticketKey(event.GoogleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := token.ParseTicket(ticketToken)
+	if err != nil {
+		return nil, common.ErrTicketInvalid
+	}
+	if claim.EventID != event.ID {
+		return nil, common.ErrTicketWrongEvent
+	}
+
+	claim, err = token.VerifyTicket(key.Public().(ed25519.PublicKey), ticketToken, scannedAt)
+	switch {
+	case errors.Is(err, token.ErrExpiredTicket):
+		return nil, common.ErrTicketExpired
+	case err != nil:
+		return nil, common.ErrTicketInvalid
+	}
+
+	return claim, nil
+}
+
+func (service *tixService) ticketHolder(
+	ctx context.Context,
+	event *entity.Event,
+	participantID int32,
+) (*entity.Participant, error) {
+	participant, err := service.postgreSQLRepository.GetParticipantByIDAndEventID(
+		ctx, participantID, event.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketNotFound
+		}
+		return nil, err
+	}
+	return participant, nil
+}
+
+func ticketTokenExpiry(event *entity.Event) time.Time {
+	return time.Unix(int64(event.EventDate), 0).Add(common.TicketTokenGracePeriod)
+}
+
+// isTicketToken tell the scanned ticket token (a jwt) apart from the ticket code.
+func isTicketToken(scanned string) bool {
+	return strings.Count(scanned, ".") == 2
+}
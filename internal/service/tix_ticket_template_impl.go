@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/internal/domain/request"
+	"github.com/aasumitro/tix/internal/domain/response"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"strings"
+	"text/template"
+)
+
+// sampleTicketParticipant is drawn on the ticket template preview.
+var sampleTicketParticipant = &entity.Participant{
+	Name:  "Jane Doe",
+	Email: "jane.doe@example.com",
+	Phone: "081234567890",
+	Job:   "Software Engineer",
+	CustomAnswers: entity.CustomAnswers{
+		"sample": {Question: "Company", Answer: "BAKODE"},
+	},
+	TicketCode: sql.NullString{String: "7K2M-QX4D-9PLA-ZR3T", Valid: true},
+}
+
+type (
+	ticketTemplateData struct {
+		Event       ticketTemplateEvent
+		Participant ticketTemplateParticipant
+		TicketCode  string
+		QRCode      string
+		Barcode     string
+		Logo        string
+		Branding    entity.Branding
+	}
+
+	ticketTemplateEvent struct {
+		Name     string
+		Location string
+		Date     string
+	}
+
+	ticketTemplateParticipant struct {
+		Name  string
+		Email string
+		Phone string
+		Job   string
+		// Answers keep the custom answers keyed by the google form question
+		Answers map[string]string
+	}
+)
+
+func (service *tixService) FetchTicketTemplate(
+	ctx context.Context,
+	googleFormID string,
+) (item *response.EventTicketTemplateResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTicketTemplateResponse(googleFormID, event.TicketTemplate.String), nil
+}
+
+func (service *tixService) UpdateTicketTemplate(
+	ctx context.Context,
+	googleFormID string,
+	form *request.EventRequestTicketTemplate,
+) (item *response.EventTicketTemplateResponse, err error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketTemplate := strings.TrimSpace(form.Template)
+	if ticketTemplate != "" {
+		// draw the template once, so a broken one is never stored
+		if _, err := service.previewTicket(event, ticketTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := service.postgreSQLRepository.UpdateEventTicketTemplate(
+		ctx, googleFormID, sql.NullString{
+			String: ticketTemplate,
+			Valid:  ticketTemplate != "",
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return newTicketTemplateResponse(googleFormID, ticketTemplate), nil
+}
+
+// PreviewTicketTemplate draw the ticket of a sample participant, the stored
+// ticket template is drawn when the form does not provide any.
+func (service *tixService) PreviewTicketTemplate(
+	ctx context.Context,
+	googleFormID string,
+	form *request.EventRequestTicketTemplate,
+) ([]byte, error) {
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketTemplate := strings.TrimSpace(form.Template)
+	if ticketTemplate == "" {
+		ticketTemplate = event.TicketTemplate.String
+	}
+
+	return service.previewTicket(event, ticketTemplate)
+}
+
+func (service *tixService) previewTicket(
+	event *entity.Event,
+	ticketTemplate string,
+) ([]byte, error) {
+	ticketCode := sampleTicketParticipant.TicketCode.String
+	qrCode, err := service.ticketQRCode(event, sampleTicketParticipant.ID, ticketCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return drawPDFTicket(event, sampleTicketParticipant, ticketCode, qrCode, ticketTemplate)
+}
+
+// drawPDFTicket draw the pdf ticket in memory.
+func drawPDFTicket(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode, qrCode, ticketTemplate string,
+) ([]byte, error) {
+	m, err := renderPDFTicket(event, participant, ticketCode, qrCode, ticketTemplate)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := m.Output()
+	if err != nil {
+		return nil, fmt.Errorf("⚠️ could not draw pdf: %s", err.Error())
+	}
+
+	return ticket.Bytes(), nil
+}
+
+func newTicketTemplateResponse(
+	googleFormID, ticketTemplate string,
+) *response.EventTicketTemplateResponse {
+	item := &response.EventTicketTemplateResponse{
+		GoogleFormID: googleFormID,
+		Template:     ticketTemplate,
+		Placeholders: common.TicketTemplatePlaceholders,
+	}
+	if ticketTemplate == "" {
+		item.IsDefault = true
+		item.Template = common.DefaultTicketTemplate
+	}
+	return item
+}
+
+func newTicketTemplateData(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode string,
+	branding entity.Branding,
+) *ticketTemplateData {
+	answers := make(map[string]string, len(participant.CustomAnswers))
+	for _, answer := range participant.CustomAnswers {
+		answers[answer.Question] = answer.Answer
+	}
+	return &ticketTemplateData{
+		Event: ticketTemplateEvent{
+			Name:     event.Name,
+			Location: event.Location,
+			Date:     ticketEventDate(event),
+		},
+		Participant: ticketTemplateParticipant{
+			Name:    participant.Name,
+			Email:   participant.Email,
+			Phone:   participant.Phone,
+			Job:     participant.Job,
+			Answers: answers,
+		},
+		TicketCode: ticketCode,
+		QRCode:     common.TicketTemplateQRCode,
+		Barcode:    common.TicketTemplateBarcode,
+		Logo:       common.TicketTemplateLogo,
+		Branding:   branding,
+	}
+}
+
+// executeTicketTemplate fill the placeholders of the ticket template,
+// it returns the ticket layout to draw line by line.
+func executeTicketTemplate(ticketTemplate string, data *ticketTemplateData) ([]string, error) {
+	tmpl, err := template.New("ticket").Option("missingkey=zero").Parse(ticketTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrInvalidTicketTemplate, err.Error())
+	}
+	var layout bytes.Buffer
+	if err := tmpl.Execute(&layout, data); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrInvalidTicketTemplate, err.Error())
+	}
+
+	lines := strings.Split(strings.ReplaceAll(layout.String(), "\r\n", "\n"), "\n")
+	if len(lines) > common.TicketTemplateMaxLines {
+		return nil, fmt.Errorf("%w: it has more than %d lines",
+			common.ErrInvalidTicketTemplate, common.TicketTemplateMaxLines)
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") && !isTicketTemplateDirective(line) {
+			return nil, fmt.Errorf("%w: unknown directive %s",
+				common.ErrInvalidTicketTemplate, line)
+		}
+	}
+
+	return lines, nil
+}
+
+func isTicketTemplateDirective(line string) bool {
+	switch line {
+	case common.TicketTemplateQRCode, common.TicketTemplateBarcode, common.TicketTemplateLogo:
+		return true
+	default:
+		return false
+	}
+}
+
+// drawTicketLayout draw the ticket layout line by line, see
+// common.DefaultTicketTemplate for the syntax of each line.
+func drawTicketLayout(
+	m pdf.Maroto,
+	layout []string,
+	ticketCode, qrCode string,
+	branding entity.Branding,
+	logo *brandingLogo,
+) error {
+	for _, line := range layout {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			m.Row(common.PdfTicketTemplateSpaceHeight, func() {})
+		case line == "---":
+			m.Line(common.PdfLineSpaceHeight, props.Line{Width: common.PdfLineWidth})
+		case line == common.TicketTemplateQRCode:
+			m.Row(common.PdfTicketTemplateQrCodeHeight, func() {
+				m.Col(common.PdfHeaderColWidth, func() {
+					m.QrCode(qrCode, props.Rect{
+						Percent: common.PdfTicketQrCodePercent,
+						Center:  true,
+					})
+				})
+			})
+		case line == common.TicketTemplateBarcode:
+			if err := drawTicketBarcode(m, ticketCode); err != nil {
+				return err
+			}
+		case line == common.TicketTemplateLogo:
+			drawTicketLogo(m, logo)
+		case strings.HasPrefix(line, "### "):
+			drawTicketText(m, common.PdfTicketTemplateHeadingHeight, line[len("### "):], props.Text{
+				Size:  common.PdfTicketEventNameSize,
+				Style: consts.Bold,
+				Align: consts.Left,
+				Color: brandingColor(branding.SecondaryColor),
+			})
+		case strings.HasPrefix(line, "## "):
+			drawTicketText(m, common.PdfTicketTemplateSubtitleHeight, line[len("## "):], props.Text{
+				Size:  common.PdfSubtitleSize,
+				Style: consts.Italic,
+				Align: consts.Center,
+				Color: brandingColor(branding.PrimaryColor),
+			})
+		case strings.HasPrefix(line, "# "):
+			drawTicketText(m, common.PdfTicketTemplateTitleHeight, line[len("# "):], props.Text{
+				Size:  common.PdfTitleSize,
+				Style: consts.Bold,
+				Align: consts.Center,
+				Color: brandingColor(branding.PrimaryColor),
+			})
+		case strings.HasPrefix(line, "> "):
+			drawTicketText(m, common.PdfTicketDetailLineHeight, line[len("> "):], props.Text{
+				Size:  common.PdfFooterTitleSize,
+				Style: consts.Italic,
+				Align: consts.Center,
+			})
+		case len(line) > len("****") && strings.HasPrefix(line, "**") && strings.HasSuffix(line, "**"):
+			drawTicketText(m, common.PdfTicketDetailLineHeight, strings.Trim(line, "*"), props.Text{
+				Size:  common.PdfTicketDetailSize,
+				Style: consts.Bold,
+				Align: consts.Left,
+			})
+		default:
+			drawTicketText(m, common.PdfTicketDetailLineHeight, line, props.Text{
+				Size:  common.PdfTicketDetailSize,
+				Style: consts.Normal,
+				Align: consts.Left,
+			})
+		}
+	}
+
+	return nil
+}
+
+func drawTicketText(m pdf.Maroto, height float64, text string, prop props.Text) {
+	m.Row(height, func() {
+		m.Col(common.PdfHeaderColWidth, func() {
+			m.Text(text, prop)
+		})
+	})
+}
+
+// drawTicketLogo draw the logo customised by the event, nothing is drawn
+// when the event does not customise it.
+func drawTicketLogo(m pdf.Maroto, logo *brandingLogo) {
+	if logo == nil {
+		return
+	}
+	m.Row(common.PdfTicketTemplateLogoHeight, func() {
+		m.Col(common.PdfHeaderColWidth, func() {
+			if err := m.Base64Image(
+				base64.StdEncoding.EncodeToString(logo.data), logo.extension,
+				props.Rect{Percent: common.PdfHeaderLogoPercent, Center: true},
+			); err != nil {
+				fmt.Println("⚠️ could not draw branding logo:", err)
+			}
+		})
+	})
+}
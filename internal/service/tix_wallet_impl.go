@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/aasumitro/tix/common"
+	"github.com/aasumitro/tix/internal/domain/entity"
+	"github.com/aasumitro/tix/pkg/wallet"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// GenerateWalletPass build the wallet pass of the ticket, it is requested
+// by the participant through the link sent with the ticket email.
+func (service *tixService) GenerateWalletPass(
+	ctx context.Context,
+	googleFormID, ticketCode string,
+) ([]byte, error) {
+	if service.walletPass == nil {
+		return nil, common.ErrWalletPassDisabled
+	}
+
+	event, err := service.postgreSQLRepository.GetEventByGoogleFormID(ctx, googleFormID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketNotFound
+		}
+		return nil, err
+	}
+
+	participant, err := service.postgreSQLRepository.GetParticipantByTicketCode(
+		ctx, strings.ToUpper(strings.TrimSpace(ticketCode)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, common.ErrTicketNotFound
+		}
+		return nil, err
+	}
+	// do not tell the ticket exists for another event
+	if participant.EventID != event.ID {
+		return nil, common.ErrTicketNotFound
+	}
+	if !participant.ApprovedAt.Valid || participant.DeclinedAt.Valid {
+		return nil, common.ErrParticipantNotApproved
+	}
+
+	qrCode, err := service.ticketQRCode(event, participant.ID, participant.TicketCode.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.walletPassTicket(event, participant, participant.TicketCode.String, qrCode)
+}
+
+func (service *tixService) walletPassTicket(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode, qrCode string,
+) ([]byte, error) {
+	return service.walletPass.Generate(&wallet.EventTicket{
+		SerialNumber:   ticketCode,
+		EventName:      event.Name,
+		EventDate:      time.Unix(int64(event.EventDate), 0),
+		Location:       event.Location,
+		AttendeeName:   participant.Name,
+		TicketCode:     ticketCode,
+		BarcodeMessage: qrCode,
+	})
+}
+
+// generateWalletPassTicket save the wallet pass next to the pdf ticket
+// so both are attached to the ticket email.
+func (service *tixService) generateWalletPassTicket(
+	event *entity.Event,
+	participant *entity.Participant,
+	ticketCode, qrCode string,
+) error {
+	pass, err := service.walletPassTicket(event, participant, ticketCode, qrCode)
+	if err != nil {
+		return fmt.Errorf("⚠️ could not generate wallet pass: %s", err.Error())
+	}
+
+	attachment := fmt.Sprintf("./%s/%s", common.ExportTempDir,
+		walletPassAttachmentName(event.ID, participant.ID))
+	service.saveTempFile(attachment)
+	if err := os.WriteFile(attachment, pass, common.ExportFileMode); err != nil {
+		return fmt.Errorf("⚠️ could not save wallet pass: %s", err.Error())
+	}
+
+	return nil
+}
+
+// walletPassLink return the participant facing link to download the
+// wallet pass, empty when the wallet pass or the public url is not set.
+func (service *tixService) walletPassLink(googleFormID, ticketCode string) string {
+	if service.walletPass == nil || service.publicURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/api/v1/tickets/%s/%s/pass", service.publicURL,
+		url.PathEscape(googleFormID), url.PathEscape(ticketCode))
+}
+
+func walletPassAttachmentName(eventID, participantID int32) string {
+	return fmt.Sprintf("gen%d%dtix.pkpass", eventID, participantID)
+}
@@ -41,25 +41,25 @@ func (_m *IGoogleServiceRepository) GetEvent(ctx context.Context, formID string)
 	return r0, r1
 }
 
-// GetResponses provides a mock function with given fields: ctx, formID
-func (_m *IGoogleServiceRepository) GetResponses(ctx context.Context, formID string) (*forms.ListFormResponsesResponse, error) {
-	ret := _m.Called(ctx, formID)
+// GetResponses provides a mock function with given fields: ctx, formID, submittedAfter
+func (_m *IGoogleServiceRepository) GetResponses(ctx context.Context, formID string, submittedAfter string) (*forms.ListFormResponsesResponse, error) {
+	ret := _m.Called(ctx, formID, submittedAfter)
 
 	var r0 *forms.ListFormResponsesResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*forms.ListFormResponsesResponse, error)); ok {
-		return rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*forms.ListFormResponsesResponse, error)); ok {
+		return rf(ctx, formID, submittedAfter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *forms.ListFormResponsesResponse); ok {
-		r0 = rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *forms.ListFormResponsesResponse); ok {
+		r0 = rf(ctx, formID, submittedAfter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*forms.ListFormResponsesResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, formID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, formID, submittedAfter)
 	} else {
 		r1 = ret.Error(1)
 	}
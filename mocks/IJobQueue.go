@@ -0,0 +1,160 @@
+// Code generated by mockery v2.22.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/aasumitro/tix/internal/domain"
+	entity "github.com/aasumitro/tix/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IJobQueue is an autogenerated mock type for the IJobQueue type
+type IJobQueue struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, queue, handler
+func (_m *IJobQueue) Consume(ctx context.Context, queue string, handler domain.JobHandler) error {
+	ret := _m.Called(ctx, queue, handler)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.JobHandler) error); ok {
+		r0 = rf(ctx, queue, handler)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DiscardDeadLetter provides a mock function with given fields: ctx, id
+func (_m *IJobQueue) DiscardDeadLetter(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeadLetter provides a mock function with given fields: ctx, id
+func (_m *IJobQueue) GetDeadLetter(ctx context.Context, id string) (*entity.DeadLetterJob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.DeadLetterJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.DeadLetterJob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.DeadLetterJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DeadLetterJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeadLetters provides a mock function with given fields: ctx, cursor, count
+func (_m *IJobQueue) ListDeadLetters(ctx context.Context, cursor string, count int64) ([]*entity.DeadLetterJob, error) {
+	ret := _m.Called(ctx, cursor, count)
+
+	var r0 []*entity.DeadLetterJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]*entity.DeadLetterJob, error)); ok {
+		return rf(ctx, cursor, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*entity.DeadLetterJob); ok {
+		r0 = rf(ctx, cursor, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.DeadLetterJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, cursor, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, queue, payload
+func (_m *IJobQueue) Publish(ctx context.Context, queue string, payload []byte) (string, error) {
+	ret := _m.Called(ctx, queue, payload)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) (string, error)); ok {
+		return rf(ctx, queue, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) string); ok {
+		r0 = rf(ctx, queue, payload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte) error); ok {
+		r1 = rf(ctx, queue, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetryDeadLetter provides a mock function with given fields: ctx, id
+func (_m *IJobQueue) RetryDeadLetter(ctx context.Context, id string) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIJobQueue interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIJobQueue creates a new instance of IJobQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIJobQueue(t mockConstructorTestingTNewIJobQueue) *IJobQueue {
+	mock := &IJobQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
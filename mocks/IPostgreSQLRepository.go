@@ -12,6 +12,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	request "github.com/aasumitro/tix/internal/domain/request"
+
+	sql "database/sql"
 )
 
 // IPostgreSQLRepository is an autogenerated mock type for the IPostgreSQLRepository type
@@ -19,6 +21,34 @@ type IPostgreSQLRepository struct {
 	mock.Mock
 }
 
+// BackfillParticipantResponse provides a mock function with given fields: ctx, participant
+func (_m *IPostgreSQLRepository) BackfillParticipantResponse(ctx context.Context, participant *entity.Participant) error {
+	ret := _m.Called(ctx, participant)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Participant) error); ok {
+		r0 = rf(ctx, participant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckInParticipant provides a mock function with given fields: ctx, id, checkedInAt, checkedInBy
+func (_m *IPostgreSQLRepository) CheckInParticipant(ctx context.Context, id int32, checkedInAt int64, checkedInBy string) error {
+	ret := _m.Called(ctx, id, checkedInAt, checkedInBy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int64, string) error); ok {
+		r0 = rf(ctx, id, checkedInAt, checkedInBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CountParticipants provides a mock function with given fields: ctx, eventID, participantStatus, startBetween, endBetween
 func (_m *IPostgreSQLRepository) CountParticipants(ctx context.Context, eventID int32, participantStatus common.EventParticipantStatus, startBetween int64, endBetween int64) int {
 	ret := _m.Called(ctx, eventID, participantStatus, startBetween, endBetween)
@@ -165,6 +195,58 @@ func (_m *IPostgreSQLRepository) GetEventByGoogleFormID(ctx context.Context, goo
 	return r0, r1
 }
 
+// GetJobByID provides a mock function with given fields: ctx, id
+func (_m *IPostgreSQLRepository) GetJobByID(ctx context.Context, id int32) (*entity.Job, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32) (*entity.Job, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *entity.Job); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJobsByEventID provides a mock function with given fields: ctx, eventID, limit
+func (_m *IPostgreSQLRepository) GetJobsByEventID(ctx context.Context, eventID int32, limit int32) ([]*entity.Job, error) {
+	ret := _m.Called(ctx, eventID, limit)
+
+	var r0 []*entity.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) ([]*entity.Job, error)); ok {
+		return rf(ctx, eventID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) []*entity.Job); ok {
+		r0 = rf(ctx, eventID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32, int32) error); ok {
+		r1 = rf(ctx, eventID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetParticipantByEmailAndEventID provides a mock function with given fields: ctx, email, eventID
 func (_m *IPostgreSQLRepository) GetParticipantByEmailAndEventID(ctx context.Context, email string, eventID int32) (*entity.Participant, error) {
 	ret := _m.Called(ctx, email, eventID)
@@ -217,6 +299,136 @@ func (_m *IPostgreSQLRepository) GetParticipantByIDAndEventID(ctx context.Contex
 	return r0, r1
 }
 
+// GetParticipantByResponseIDAndEventID provides a mock function with given fields: ctx, responseID, eventID
+func (_m *IPostgreSQLRepository) GetParticipantByResponseIDAndEventID(ctx context.Context, responseID string, eventID int32) (*entity.Participant, error) {
+	ret := _m.Called(ctx, responseID, eventID)
+
+	var r0 *entity.Participant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) (*entity.Participant, error)); ok {
+		return rf(ctx, responseID, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) *entity.Participant); ok {
+		r0 = rf(ctx, responseID, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Participant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, responseID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetParticipantByTicketCode provides a mock function with given fields: ctx, ticketCode
+func (_m *IPostgreSQLRepository) GetParticipantByTicketCode(ctx context.Context, ticketCode string) (*entity.Participant, error) {
+	ret := _m.Called(ctx, ticketCode)
+
+	var r0 *entity.Participant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Participant, error)); ok {
+		return rf(ctx, ticketCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Participant); ok {
+		r0 = rf(ctx, ticketCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Participant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ticketCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetParticipantChanges provides a mock function with given fields: ctx, eventID, participantID
+func (_m *IPostgreSQLRepository) GetParticipantChanges(ctx context.Context, eventID int32, participantID int32) ([]*entity.ParticipantChange, error) {
+	ret := _m.Called(ctx, eventID, participantID)
+
+	var r0 []*entity.ParticipantChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) ([]*entity.ParticipantChange, error)); ok {
+		return rf(ctx, eventID, participantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) []*entity.ParticipantChange); ok {
+		r0 = rf(ctx, eventID, participantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ParticipantChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32, int32) error); ok {
+		r1 = rf(ctx, eventID, participantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTicketDeliveriesByJobID provides a mock function with given fields: ctx, jobID
+func (_m *IPostgreSQLRepository) GetTicketDeliveriesByJobID(ctx context.Context, jobID int32) ([]*entity.TicketDelivery, error) {
+	ret := _m.Called(ctx, jobID)
+
+	var r0 []*entity.TicketDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32) ([]*entity.TicketDelivery, error)); ok {
+		return rf(ctx, jobID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32) []*entity.TicketDelivery); ok {
+		r0 = rf(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.TicketDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTicketRecipientIDs provides a mock function with given fields: ctx, eventID, unsentOnly
+func (_m *IPostgreSQLRepository) GetTicketRecipientIDs(ctx context.Context, eventID int32, unsentOnly bool) ([]int32, error) {
+	ret := _m.Called(ctx, eventID, unsentOnly)
+
+	var r0 []int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, bool) ([]int32, error)); ok {
+		return rf(ctx, eventID, unsentOnly)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32, bool) []int32); ok {
+		r0 = rf(ctx, eventID, unsentOnly)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int32)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32, bool) error); ok {
+		r1 = rf(ctx, eventID, unsentOnly)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByEmail provides a mock function with given fields: ctx, email
 func (_m *IPostgreSQLRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	ret := _m.Called(ctx, email)
@@ -243,6 +455,30 @@ func (_m *IPostgreSQLRepository) GetUserByEmail(ctx context.Context, email strin
 	return r0, r1
 }
 
+// InsertJob provides a mock function with given fields: ctx, job
+func (_m *IPostgreSQLRepository) InsertJob(ctx context.Context, job *entity.Job) (int32, error) {
+	ret := _m.Called(ctx, job)
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job) (int32, error)); ok {
+		return rf(ctx, job)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job) int32); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Job) error); ok {
+		r1 = rf(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InsertManyParticipants provides a mock function with given fields: ctx, participants, createdAt
 func (_m *IPostgreSQLRepository) InsertManyParticipants(ctx context.Context, participants []*entity.Participant, createdAt int64) error {
 	ret := _m.Called(ctx, participants, createdAt)
@@ -283,6 +519,156 @@ func (_m *IPostgreSQLRepository) InsertNewEvent(ctx context.Context, param *requ
 	return r0, r1
 }
 
+// InsertTicketDeliveries provides a mock function with given fields: ctx, jobID, participantIDs, createdAt
+func (_m *IPostgreSQLRepository) InsertTicketDeliveries(ctx context.Context, jobID int32, participantIDs []int32, createdAt int64) error {
+	ret := _m.Called(ctx, jobID, participantIDs, createdAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, []int32, int64) error); ok {
+		r0 = rf(ctx, jobID, participantIDs, createdAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTicketDelivery provides a mock function with given fields: ctx, delivery, updatedAt
+func (_m *IPostgreSQLRepository) SaveTicketDelivery(ctx context.Context, delivery *entity.TicketDelivery, updatedAt int64) error {
+	ret := _m.Called(ctx, delivery, updatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.TicketDelivery, int64) error); ok {
+		r0 = rf(ctx, delivery, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEventBranding provides a mock function with given fields: ctx, googleFormID, branding
+func (_m *IPostgreSQLRepository) UpdateEventBranding(ctx context.Context, googleFormID string, branding entity.Branding) error {
+	ret := _m.Called(ctx, googleFormID, branding)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.Branding) error); ok {
+		r0 = rf(ctx, googleFormID, branding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEventFieldMapping provides a mock function with given fields: ctx, googleFormID, mapping
+func (_m *IPostgreSQLRepository) UpdateEventFieldMapping(ctx context.Context, googleFormID string, mapping entity.FieldMapping) error {
+	ret := _m.Called(ctx, googleFormID, mapping)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.FieldMapping) error); ok {
+		r0 = rf(ctx, googleFormID, mapping)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEventLastRespondSyncedAt provides a mock function with given fields: ctx, eventID, lastSubmittedTime
+func (_m *IPostgreSQLRepository) UpdateEventLastRespondSyncedAt(ctx context.Context, eventID int32, lastSubmittedTime string) error {
+	ret := _m.Called(ctx, eventID, lastSubmittedTime)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, string) error); ok {
+		r0 = rf(ctx, eventID, lastSubmittedTime)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEventTicketTemplate provides a mock function with given fields: ctx, googleFormID, template
+func (_m *IPostgreSQLRepository) UpdateEventTicketTemplate(ctx context.Context, googleFormID string, template sql.NullString) error {
+	ret := _m.Called(ctx, googleFormID, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, sql.NullString) error); ok {
+		r0 = rf(ctx, googleFormID, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateJob provides a mock function with given fields: ctx, job, updatedAt
+func (_m *IPostgreSQLRepository) UpdateJob(ctx context.Context, job *entity.Job, updatedAt int64) error {
+	ret := _m.Called(ctx, job, updatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job, int64) error); ok {
+		r0 = rf(ctx, job, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateParticipantResponse provides a mock function with given fields: ctx, participant, changes, resetStatus, updatedAt
+func (_m *IPostgreSQLRepository) UpdateParticipantResponse(ctx context.Context, participant *entity.Participant, changes []*entity.ParticipantChange, resetStatus bool, updatedAt int64) error {
+	ret := _m.Called(ctx, participant, changes, resetStatus, updatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Participant, []*entity.ParticipantChange, bool, int64) error); ok {
+		r0 = rf(ctx, participant, changes, resetStatus, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateParticipantTicketCode provides a mock function with given fields: ctx, id, ticketCode
+func (_m *IPostgreSQLRepository) UpdateParticipantTicketCode(ctx context.Context, id int32, ticketCode string) (string, error) {
+	ret := _m.Called(ctx, id, ticketCode)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, string) (string, error)); ok {
+		return rf(ctx, id, ticketCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32, string) string); ok {
+		r0 = rf(ctx, id, ticketCode)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32, string) error); ok {
+		r1 = rf(ctx, id, ticketCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateParticipantTicketSentAt provides a mock function with given fields: ctx, id, sentAt
+func (_m *IPostgreSQLRepository) UpdateParticipantTicketSentAt(ctx context.Context, id int32, sentAt int64) error {
+	ret := _m.Called(ctx, id, sentAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int64) error); ok {
+		r0 = rf(ctx, id, sentAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateParticipants provides a mock function with given fields: ctx, approvedAt, declinedAt, declinedReason, id
 func (_m *IPostgreSQLRepository) UpdateParticipants(ctx context.Context, approvedAt *int64, declinedAt *int64, declinedReason *string, id int32) error {
 	ret := _m.Called(ctx, approvedAt, declinedAt, declinedReason, id)
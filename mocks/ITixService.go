@@ -5,65 +5,741 @@ package mocks
 import (
 	context "context"
 
+	common "github.com/aasumitro/tix/common"
+
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	request "github.com/aasumitro/tix/internal/domain/request"
 
-	response "github.com/aasumitro/tix/internal/domain/response"
-)
+	response "github.com/aasumitro/tix/internal/domain/response"
+)
+
+// ITixService is an autogenerated mock type for the ITixService type
+type ITixService struct {
+	mock.Mock
+}
+
+// CheckInTicket provides a mock function with given fields: ctx, googleFormID, ticketCode, checkedInBy
+func (_m *ITixService) CheckInTicket(ctx context.Context, googleFormID string, ticketCode string, checkedInBy string) (*response.CheckInResponse, error) {
+	ret := _m.Called(ctx, googleFormID, ticketCode, checkedInBy)
+
+	var r0 *response.CheckInResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*response.CheckInResponse, error)); ok {
+		return rf(ctx, googleFormID, ticketCode, checkedInBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *response.CheckInResponse); ok {
+		r0 = rf(ctx, googleFormID, ticketCode, checkedInBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.CheckInResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, googleFormID, ticketCode, checkedInBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, uuid
+func (_m *ITixService) DeleteUser(ctx context.Context, uuid string) error {
+	ret := _m.Called(ctx, uuid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uuid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DiscardDeadLetterJob provides a mock function with given fields: ctx, id
+func (_m *ITixService) DiscardDeadLetterJob(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DownloadTicket provides a mock function with given fields: ctx, googleFormID, participantID
+func (_m *ITixService) DownloadTicket(ctx context.Context, googleFormID string, participantID int32) ([]byte, error) {
+	ret := _m.Called(ctx, googleFormID, participantID)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) ([]byte, error)); ok {
+		return rf(ctx, googleFormID, participantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) []byte); ok {
+		r0 = rf(ctx, googleFormID, participantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, googleFormID, participantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DownloadTicketByToken provides a mock function with given fields: ctx, googleFormID, downloadToken
+func (_m *ITixService) DownloadTicketByToken(ctx context.Context, googleFormID string, downloadToken string) ([]byte, error) {
+	ret := _m.Called(ctx, googleFormID, downloadToken)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]byte, error)); ok {
+		return rf(ctx, googleFormID, downloadToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []byte); ok {
+		r0 = rf(ctx, googleFormID, downloadToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, googleFormID, downloadToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportEvent provides a mock function with given fields: ctx, googleFormID, exportFileType, targetEmail, form
+func (_m *ITixService) ExportEvent(ctx context.Context, googleFormID string, exportFileType string, targetEmail string, form *request.EventRequestExport) error {
+	ret := _m.Called(ctx, googleFormID, exportFileType, targetEmail, form)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *request.EventRequestExport) error); ok {
+		r0 = rf(ctx, googleFormID, exportFileType, targetEmail, form)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchDeadLetterJob provides a mock function with given fields: ctx, id
+func (_m *ITixService) FetchDeadLetterJob(ctx context.Context, id string) (*response.DeadLetterJobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *response.DeadLetterJobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.DeadLetterJobResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.DeadLetterJobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DeadLetterJobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchDeadLetterJobs provides a mock function with given fields: ctx, form
+func (_m *ITixService) FetchDeadLetterJobs(ctx context.Context, form *request.JobRequestDeadLetters) (*response.DeadLetterJobPageResponse, error) {
+	ret := _m.Called(ctx, form)
+
+	var r0 *response.DeadLetterJobPageResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *request.JobRequestDeadLetters) (*response.DeadLetterJobPageResponse, error)); ok {
+		return rf(ctx, form)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *request.JobRequestDeadLetters) *response.DeadLetterJobPageResponse); ok {
+		r0 = rf(ctx, form)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.DeadLetterJobPageResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *request.JobRequestDeadLetters) error); ok {
+		r1 = rf(ctx, form)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEventBranding provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchEventBranding(ctx context.Context, googleFormID string) (*response.EventBrandingResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 *response.EventBrandingResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.EventBrandingResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.EventBrandingResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventBrandingResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEventCalendar provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchEventCalendar(ctx context.Context, googleFormID string) ([]byte, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEventJobs provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchEventJobs(ctx context.Context, googleFormID string) ([]*response.JobResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 []*response.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.JobResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.JobResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEvents provides a mock function with given fields: ctx
+func (_m *ITixService) FetchEvents(ctx context.Context) ([]*response.EventResponse, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*response.EventResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*response.EventResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*response.EventResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.EventResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchFieldMapping provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchFieldMapping(ctx context.Context, googleFormID string) (*response.EventFieldMappingResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 *response.EventFieldMappingResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.EventFieldMappingResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.EventFieldMappingResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventFieldMappingResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchForms provides a mock function with given fields: ctx, formID
+func (_m *ITixService) FetchForms(ctx context.Context, formID string) ([]*response.GoogleFormQuestion, error) {
+	ret := _m.Called(ctx, formID)
+
+	var r0 []*response.GoogleFormQuestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.GoogleFormQuestion, error)); ok {
+		return rf(ctx, formID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.GoogleFormQuestion); ok {
+		r0 = rf(ctx, formID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.GoogleFormQuestion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, formID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchJob provides a mock function with given fields: ctx, id
+func (_m *ITixService) FetchJob(ctx context.Context, id int32) (*response.JobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *response.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32) (*response.JobResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *response.JobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchOverview provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchOverview(ctx context.Context, googleFormID string) (*response.EventOverviewResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 *response.EventOverviewResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.EventOverviewResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.EventOverviewResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventOverviewResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchParticipantChanges provides a mock function with given fields: ctx, googleFormID, participantID
+func (_m *ITixService) FetchParticipantChanges(ctx context.Context, googleFormID string, participantID int32) ([]*response.ParticipantChangeResponse, error) {
+	ret := _m.Called(ctx, googleFormID, participantID)
+
+	var r0 []*response.ParticipantChangeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) ([]*response.ParticipantChangeResponse, error)); ok {
+		return rf(ctx, googleFormID, participantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) []*response.ParticipantChangeResponse); ok {
+		r0 = rf(ctx, googleFormID, participantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.ParticipantChangeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, googleFormID, participantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchParticipants provides a mock function with given fields: ctx, googleFormID, filter
+func (_m *ITixService) FetchParticipants(ctx context.Context, googleFormID string, filter *request.EventRequestParticipantFilter) ([]*response.ParticipantResponse, error) {
+	ret := _m.Called(ctx, googleFormID, filter)
+
+	var r0 []*response.ParticipantResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestParticipantFilter) ([]*response.ParticipantResponse, error)); ok {
+		return rf(ctx, googleFormID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestParticipantFilter) []*response.ParticipantResponse); ok {
+		r0 = rf(ctx, googleFormID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.ParticipantResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.EventRequestParticipantFilter) error); ok {
+		r1 = rf(ctx, googleFormID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchResponds provides a mock function with given fields: ctx, formID, submittedAfter
+func (_m *ITixService) FetchResponds(ctx context.Context, formID string, submittedAfter string) ([]*response.GoogleFormRespond, error) {
+	ret := _m.Called(ctx, formID, submittedAfter)
+
+	var r0 []*response.GoogleFormRespond
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*response.GoogleFormRespond, error)); ok {
+		return rf(ctx, formID, submittedAfter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*response.GoogleFormRespond); ok {
+		r0 = rf(ctx, formID, submittedAfter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.GoogleFormRespond)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, formID, submittedAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTicketDeliveries provides a mock function with given fields: ctx, googleFormID, jobID
+func (_m *ITixService) FetchTicketDeliveries(ctx context.Context, googleFormID string, jobID int32) (*response.TicketDeliveryReportResponse, error) {
+	ret := _m.Called(ctx, googleFormID, jobID)
+
+	var r0 *response.TicketDeliveryReportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) (*response.TicketDeliveryReportResponse, error)); ok {
+		return rf(ctx, googleFormID, jobID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) *response.TicketDeliveryReportResponse); ok {
+		r0 = rf(ctx, googleFormID, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.TicketDeliveryReportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, googleFormID, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTicketKeyBundle provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchTicketKeyBundle(ctx context.Context, googleFormID string) (*response.TicketKeyBundleResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 *response.TicketKeyBundleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.TicketKeyBundleResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.TicketKeyBundleResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.TicketKeyBundleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTicketTemplate provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) FetchTicketTemplate(ctx context.Context, googleFormID string) (*response.EventTicketTemplateResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
+
+	var r0 *response.EventTicketTemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.EventTicketTemplateResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.EventTicketTemplateResponse); ok {
+		r0 = rf(ctx, googleFormID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventTicketTemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchUsers provides a mock function with given fields: ctx, email
+func (_m *ITixService) FetchUsers(ctx context.Context, email string) ([]*response.UserResponse, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 []*response.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.UserResponse, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.UserResponse); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*response.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateEventTickets provides a mock function with given fields: ctx, googleFormID, jobID, filter
+func (_m *ITixService) GenerateEventTickets(ctx context.Context, googleFormID string, jobID int32, filter string) (*response.TicketDeliveryReportResponse, error) {
+	ret := _m.Called(ctx, googleFormID, jobID, filter)
+
+	var r0 *response.TicketDeliveryReportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32, string) (*response.TicketDeliveryReportResponse, error)); ok {
+		return rf(ctx, googleFormID, jobID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32, string) *response.TicketDeliveryReportResponse); ok {
+		r0 = rf(ctx, googleFormID, jobID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.TicketDeliveryReportResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32, string) error); ok {
+		r1 = rf(ctx, googleFormID, jobID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateMagicLink provides a mock function with given fields: ctx, email
+func (_m *ITixService) GenerateMagicLink(ctx context.Context, email string) *response.ServiceSingleRespond {
+	ret := _m.Called(ctx, email)
+
+	var r0 *response.ServiceSingleRespond
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.ServiceSingleRespond); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.ServiceSingleRespond)
+		}
+	}
+
+	return r0
+}
+
+// GenerateTicket provides a mock function with given fields: ctx, googleFormID, participantID
+func (_m *ITixService) GenerateTicket(ctx context.Context, googleFormID string, participantID int32) error {
+	ret := _m.Called(ctx, googleFormID, participantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) error); ok {
+		r0 = rf(ctx, googleFormID, participantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateWalletPass provides a mock function with given fields: ctx, googleFormID, ticketCode
+func (_m *ITixService) GenerateWalletPass(ctx context.Context, googleFormID string, ticketCode string) ([]byte, error) {
+	ret := _m.Called(ctx, googleFormID, ticketCode)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]byte, error)); ok {
+		return rf(ctx, googleFormID, ticketCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []byte); ok {
+		r0 = rf(ctx, googleFormID, ticketCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, googleFormID, ticketCode)
+	} else {
+		r1 = ret.Error(1)
+	}
 
-// ITixService is an autogenerated mock type for the ITixService type
-type ITixService struct {
-	mock.Mock
+	return r0, r1
 }
 
-// DeleteUser provides a mock function with given fields: ctx, uuid
-func (_m *ITixService) DeleteUser(ctx context.Context, uuid string) error {
-	ret := _m.Called(ctx, uuid)
+// InviteUserByEmail provides a mock function with given fields: ctx, email
+func (_m *ITixService) InviteUserByEmail(ctx context.Context, email string) *response.ServiceSingleRespond {
+	ret := _m.Called(ctx, email)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, uuid)
+	var r0 *response.ServiceSingleRespond
+	if rf, ok := ret.Get(0).(func(context.Context, string) *response.ServiceSingleRespond); ok {
+		r0 = rf(ctx, email)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.ServiceSingleRespond)
+		}
 	}
 
 	return r0
 }
 
-// ExportEvent provides a mock function with given fields: ctx, googleFormID, exportFileType, targetEmail
-func (_m *ITixService) ExportEvent(ctx context.Context, googleFormID string, exportFileType string, targetEmail string) error {
-	ret := _m.Called(ctx, googleFormID, exportFileType, targetEmail)
+// OpenEventExport provides a mock function with given fields: ctx, downloadToken
+func (_m *ITixService) OpenEventExport(ctx context.Context, downloadToken string) (io.ReadCloser, string, string, error) {
+	ret := _m.Called(ctx, downloadToken)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
-		r0 = rf(ctx, googleFormID, exportFileType, targetEmail)
+	var r0 io.ReadCloser
+	var r1 string
+	var r2 string
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, string, string, error)); ok {
+		return rf(ctx, downloadToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, downloadToken)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, downloadToken)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) string); ok {
+		r2 = rf(ctx, downloadToken)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string) error); ok {
+		r3 = rf(ctx, downloadToken)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
 }
 
-// FetchEvents provides a mock function with given fields: ctx
-func (_m *ITixService) FetchEvents(ctx context.Context) ([]*response.EventResponse, error) {
-	ret := _m.Called(ctx)
+// PreviewTicketTemplate provides a mock function with given fields: ctx, googleFormID, form
+func (_m *ITixService) PreviewTicketTemplate(ctx context.Context, googleFormID string, form *request.EventRequestTicketTemplate) ([]byte, error) {
+	ret := _m.Called(ctx, googleFormID, form)
 
-	var r0 []*response.EventResponse
+	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*response.EventResponse, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestTicketTemplate) ([]byte, error)); ok {
+		return rf(ctx, googleFormID, form)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*response.EventResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestTicketTemplate) []byte); ok {
+		r0 = rf(ctx, googleFormID, form)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*response.EventResponse)
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.EventRequestTicketTemplate) error); ok {
+		r1 = rf(ctx, googleFormID, form)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,25 +747,23 @@ func (_m *ITixService) FetchEvents(ctx context.Context) ([]*response.EventRespon
 	return r0, r1
 }
 
-// FetchForms provides a mock function with given fields: ctx, formID
-func (_m *ITixService) FetchForms(ctx context.Context, formID string) ([]*response.GoogleFormQuestion, error) {
-	ret := _m.Called(ctx, formID)
+// PublishExportEventDataQueue provides a mock function with given fields: ctx, googleFormID, exportType, email, form
+func (_m *ITixService) PublishExportEventDataQueue(ctx context.Context, googleFormID string, exportType string, email string, form *request.EventRequestExport) (int32, error) {
+	ret := _m.Called(ctx, googleFormID, exportType, email, form)
 
-	var r0 []*response.GoogleFormQuestion
+	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.GoogleFormQuestion, error)); ok {
-		return rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *request.EventRequestExport) (int32, error)); ok {
+		return rf(ctx, googleFormID, exportType, email, form)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.GoogleFormQuestion); ok {
-		r0 = rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *request.EventRequestExport) int32); ok {
+		r0 = rf(ctx, googleFormID, exportType, email, form)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*response.GoogleFormQuestion)
-		}
+		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, formID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *request.EventRequestExport) error); ok {
+		r1 = rf(ctx, googleFormID, exportType, email, form)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,25 +771,47 @@ func (_m *ITixService) FetchForms(ctx context.Context, formID string) ([]*respon
 	return r0, r1
 }
 
-// FetchOverview provides a mock function with given fields: ctx, googleFormID
-func (_m *ITixService) FetchOverview(ctx context.Context, googleFormID string) (*response.EventOverviewResponse, error) {
-	ret := _m.Called(ctx, googleFormID)
+// PublishGenerateEventTicketQueue provides a mock function with given fields: ctx, googleFormID, participantID
+func (_m *ITixService) PublishGenerateEventTicketQueue(ctx context.Context, googleFormID string, participantID int32) (int32, error) {
+	ret := _m.Called(ctx, googleFormID, participantID)
 
-	var r0 *response.EventOverviewResponse
+	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*response.EventOverviewResponse, error)); ok {
-		return rf(ctx, googleFormID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) (int32, error)); ok {
+		return rf(ctx, googleFormID, participantID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *response.EventOverviewResponse); ok {
-		r0 = rf(ctx, googleFormID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) int32); ok {
+		r0 = rf(ctx, googleFormID, participantID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*response.EventOverviewResponse)
-		}
+		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, googleFormID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, googleFormID, participantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishGenerateEventTicketsQueue provides a mock function with given fields: ctx, googleFormID, filter
+func (_m *ITixService) PublishGenerateEventTicketsQueue(ctx context.Context, googleFormID string, filter string) (int32, error) {
+	ret := _m.Called(ctx, googleFormID, filter)
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int32, error)); ok {
+		return rf(ctx, googleFormID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int32); ok {
+		r0 = rf(ctx, googleFormID, filter)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, googleFormID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -123,21 +819,19 @@ func (_m *ITixService) FetchOverview(ctx context.Context, googleFormID string) (
 	return r0, r1
 }
 
-// FetchParticipants provides a mock function with given fields: ctx, googleFormID
-func (_m *ITixService) FetchParticipants(ctx context.Context, googleFormID string) ([]*response.ParticipantResponse, error) {
+// PublishSyncEventDataQueue provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) PublishSyncEventDataQueue(ctx context.Context, googleFormID string) (int32, error) {
 	ret := _m.Called(ctx, googleFormID)
 
-	var r0 []*response.ParticipantResponse
+	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.ParticipantResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int32, error)); ok {
 		return rf(ctx, googleFormID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.ParticipantResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) int32); ok {
 		r0 = rf(ctx, googleFormID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*response.ParticipantResponse)
-		}
+		r0 = ret.Get(0).(int32)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -149,25 +843,25 @@ func (_m *ITixService) FetchParticipants(ctx context.Context, googleFormID strin
 	return r0, r1
 }
 
-// FetchResponds provides a mock function with given fields: ctx, formID
-func (_m *ITixService) FetchResponds(ctx context.Context, formID string) ([]*response.GoogleFormRespond, error) {
-	ret := _m.Called(ctx, formID)
+// ReconcileCheckIns provides a mock function with given fields: ctx, googleFormID, checkIns, checkedInBy
+func (_m *ITixService) ReconcileCheckIns(ctx context.Context, googleFormID string, checkIns []*request.EventRequestOfflineCheckIn, checkedInBy string) (*response.CheckInBatchResponse, error) {
+	ret := _m.Called(ctx, googleFormID, checkIns, checkedInBy)
 
-	var r0 []*response.GoogleFormRespond
+	var r0 *response.CheckInBatchResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.GoogleFormRespond, error)); ok {
-		return rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*request.EventRequestOfflineCheckIn, string) (*response.CheckInBatchResponse, error)); ok {
+		return rf(ctx, googleFormID, checkIns, checkedInBy)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.GoogleFormRespond); ok {
-		r0 = rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*request.EventRequestOfflineCheckIn, string) *response.CheckInBatchResponse); ok {
+		r0 = rf(ctx, googleFormID, checkIns, checkedInBy)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*response.GoogleFormRespond)
+			r0 = ret.Get(0).(*response.CheckInBatchResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, formID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []*request.EventRequestOfflineCheckIn, string) error); ok {
+		r1 = rf(ctx, googleFormID, checkIns, checkedInBy)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -175,25 +869,23 @@ func (_m *ITixService) FetchResponds(ctx context.Context, formID string) ([]*res
 	return r0, r1
 }
 
-// FetchUsers provides a mock function with given fields: ctx, email
-func (_m *ITixService) FetchUsers(ctx context.Context, email string) ([]*response.UserResponse, error) {
-	ret := _m.Called(ctx, email)
+// RetryDeadLetterJob provides a mock function with given fields: ctx, id
+func (_m *ITixService) RetryDeadLetterJob(ctx context.Context, id string) (string, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 []*response.UserResponse
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*response.UserResponse, error)); ok {
-		return rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*response.UserResponse); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*response.UserResponse)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, email)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -201,29 +893,27 @@ func (_m *ITixService) FetchUsers(ctx context.Context, email string) ([]*respons
 	return r0, r1
 }
 
-// GenerateMagicLink provides a mock function with given fields: ctx, email
-func (_m *ITixService) GenerateMagicLink(ctx context.Context, email string) *response.ServiceSingleRespond {
+// SetUserAsVerified provides a mock function with given fields: ctx, email
+func (_m *ITixService) SetUserAsVerified(ctx context.Context, email string) error {
 	ret := _m.Called(ctx, email)
 
-	var r0 *response.ServiceSingleRespond
-	if rf, ok := ret.Get(0).(func(context.Context, string) *response.ServiceSingleRespond); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
 		r0 = rf(ctx, email)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*response.ServiceSingleRespond)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// GenerateTicket provides a mock function with given fields: ctx, googleFormID, participantID
-func (_m *ITixService) GenerateTicket(ctx context.Context, googleFormID string, participantID int32) error {
-	ret := _m.Called(ctx, googleFormID, participantID)
+// Shutdown provides a mock function with given fields: ctx
+func (_m *ITixService) Shutdown(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int32) error); ok {
-		r0 = rf(ctx, googleFormID, participantID)
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -231,29 +921,39 @@ func (_m *ITixService) GenerateTicket(ctx context.Context, googleFormID string,
 	return r0
 }
 
-// InviteUserByEmail provides a mock function with given fields: ctx, email
-func (_m *ITixService) InviteUserByEmail(ctx context.Context, email string) *response.ServiceSingleRespond {
-	ret := _m.Called(ctx, email)
+// StoreEvent provides a mock function with given fields: ctx, form
+func (_m *ITixService) StoreEvent(ctx context.Context, form *request.EventRequestMakeNew) (*response.EventResponse, error) {
+	ret := _m.Called(ctx, form)
 
-	var r0 *response.ServiceSingleRespond
-	if rf, ok := ret.Get(0).(func(context.Context, string) *response.ServiceSingleRespond); ok {
-		r0 = rf(ctx, email)
+	var r0 *response.EventResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *request.EventRequestMakeNew) (*response.EventResponse, error)); ok {
+		return rf(ctx, form)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *request.EventRequestMakeNew) *response.EventResponse); ok {
+		r0 = rf(ctx, form)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*response.ServiceSingleRespond)
+			r0 = ret.Get(0).(*response.EventResponse)
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *request.EventRequestMakeNew) error); ok {
+		r1 = rf(ctx, form)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// PublishExportEventDataQueue provides a mock function with given fields: ctx, googleFormID, exportType, email
-func (_m *ITixService) PublishExportEventDataQueue(ctx context.Context, googleFormID string, exportType string, email string) error {
-	ret := _m.Called(ctx, googleFormID, exportType, email)
+// StreamEventExport provides a mock function with given fields: ctx, googleFormID, exportFileType, form, w
+func (_m *ITixService) StreamEventExport(ctx context.Context, googleFormID string, exportFileType string, form *request.EventRequestExport, w io.Writer) error {
+	ret := _m.Called(ctx, googleFormID, exportFileType, form, w)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
-		r0 = rf(ctx, googleFormID, exportType, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *request.EventRequestExport, io.Writer) error); ok {
+		r0 = rf(ctx, googleFormID, exportFileType, form, w)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -261,27 +961,39 @@ func (_m *ITixService) PublishExportEventDataQueue(ctx context.Context, googleFo
 	return r0
 }
 
-// PublishGenerateEventTicketQueue provides a mock function with given fields: ctx, googleFormID, participantID
-func (_m *ITixService) PublishGenerateEventTicketQueue(ctx context.Context, googleFormID string, participantID int32) error {
-	ret := _m.Called(ctx, googleFormID, participantID)
+// SubscribeEventNotifications provides a mock function with given fields: ctx, googleFormID
+func (_m *ITixService) SubscribeEventNotifications(ctx context.Context, googleFormID string) (<-chan *response.EventNotificationResponse, error) {
+	ret := _m.Called(ctx, googleFormID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int32) error); ok {
-		r0 = rf(ctx, googleFormID, participantID)
+	var r0 <-chan *response.EventNotificationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan *response.EventNotificationResponse, error)); ok {
+		return rf(ctx, googleFormID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan *response.EventNotificationResponse); ok {
+		r0 = rf(ctx, googleFormID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *response.EventNotificationResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, googleFormID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// PublishSyncEventDataQueue provides a mock function with given fields: ctx, googleFormID
-func (_m *ITixService) PublishSyncEventDataQueue(ctx context.Context, googleFormID string) error {
-	ret := _m.Called(ctx, googleFormID)
+// SyncRespondData provides a mock function with given fields: ctx, formID
+func (_m *ITixService) SyncRespondData(ctx context.Context, formID string) error {
+	ret := _m.Called(ctx, formID)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, googleFormID)
+		r0 = rf(ctx, formID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -289,39 +1001,51 @@ func (_m *ITixService) PublishSyncEventDataQueue(ctx context.Context, googleForm
 	return r0
 }
 
-// SetUserAsVerified provides a mock function with given fields: ctx, email
-func (_m *ITixService) SetUserAsVerified(ctx context.Context, email string) error {
-	ret := _m.Called(ctx, email)
+// UpdateEventBranding provides a mock function with given fields: ctx, googleFormID, form
+func (_m *ITixService) UpdateEventBranding(ctx context.Context, googleFormID string, form *request.EventRequestBranding) (*response.EventBrandingResponse, error) {
+	ret := _m.Called(ctx, googleFormID, form)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, email)
+	var r0 *response.EventBrandingResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestBranding) (*response.EventBrandingResponse, error)); ok {
+		return rf(ctx, googleFormID, form)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestBranding) *response.EventBrandingResponse); ok {
+		r0 = rf(ctx, googleFormID, form)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventBrandingResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.EventRequestBranding) error); ok {
+		r1 = rf(ctx, googleFormID, form)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// StoreEvent provides a mock function with given fields: ctx, form
-func (_m *ITixService) StoreEvent(ctx context.Context, form *request.EventRequestMakeNew) (*response.EventResponse, error) {
-	ret := _m.Called(ctx, form)
+// UpdateFieldMapping provides a mock function with given fields: ctx, googleFormID, form
+func (_m *ITixService) UpdateFieldMapping(ctx context.Context, googleFormID string, form *request.EventRequestFieldMapping) (*response.EventFieldMappingResponse, error) {
+	ret := _m.Called(ctx, googleFormID, form)
 
-	var r0 *response.EventResponse
+	var r0 *response.EventFieldMappingResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *request.EventRequestMakeNew) (*response.EventResponse, error)); ok {
-		return rf(ctx, form)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestFieldMapping) (*response.EventFieldMappingResponse, error)); ok {
+		return rf(ctx, googleFormID, form)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *request.EventRequestMakeNew) *response.EventResponse); ok {
-		r0 = rf(ctx, form)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestFieldMapping) *response.EventFieldMappingResponse); ok {
+		r0 = rf(ctx, googleFormID, form)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*response.EventResponse)
+			r0 = ret.Get(0).(*response.EventFieldMappingResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *request.EventRequestMakeNew) error); ok {
-		r1 = rf(ctx, form)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.EventRequestFieldMapping) error); ok {
+		r1 = rf(ctx, googleFormID, form)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -329,13 +1053,13 @@ func (_m *ITixService) StoreEvent(ctx context.Context, form *request.EventReques
 	return r0, r1
 }
 
-// SyncRespondData provides a mock function with given fields: ctx, formID
-func (_m *ITixService) SyncRespondData(ctx context.Context, formID string) error {
-	ret := _m.Called(ctx, formID)
+// UpdateJobStatus provides a mock function with given fields: ctx, id, status, attempt, result, errorText
+func (_m *ITixService) UpdateJobStatus(ctx context.Context, id int32, status common.JobStatus, attempt int, result string, errorText string) error {
+	ret := _m.Called(ctx, id, status, attempt, result, errorText)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, formID)
+	if rf, ok := ret.Get(0).(func(context.Context, int32, common.JobStatus, int, string, string) error); ok {
+		r0 = rf(ctx, id, status, attempt, result, errorText)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -357,6 +1081,58 @@ func (_m *ITixService) UpdateParticipantStatus(ctx context.Context, googleFormID
 	return r0
 }
 
+// UpdateTicketTemplate provides a mock function with given fields: ctx, googleFormID, form
+func (_m *ITixService) UpdateTicketTemplate(ctx context.Context, googleFormID string, form *request.EventRequestTicketTemplate) (*response.EventTicketTemplateResponse, error) {
+	ret := _m.Called(ctx, googleFormID, form)
+
+	var r0 *response.EventTicketTemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestTicketTemplate) (*response.EventTicketTemplateResponse, error)); ok {
+		return rf(ctx, googleFormID, form)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *request.EventRequestTicketTemplate) *response.EventTicketTemplateResponse); ok {
+		r0 = rf(ctx, googleFormID, form)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.EventTicketTemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *request.EventRequestTicketTemplate) error); ok {
+		r1 = rf(ctx, googleFormID, form)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTicket provides a mock function with given fields: ctx, googleFormID, ticketToken
+func (_m *ITixService) VerifyTicket(ctx context.Context, googleFormID string, ticketToken string) (*response.TicketVerificationResponse, error) {
+	ret := _m.Called(ctx, googleFormID, ticketToken)
+
+	var r0 *response.TicketVerificationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*response.TicketVerificationResponse, error)); ok {
+		return rf(ctx, googleFormID, ticketToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *response.TicketVerificationResponse); ok {
+		r0 = rf(ctx, googleFormID, ticketToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.TicketVerificationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, googleFormID, ticketToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewITixService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,45 @@
+// Code generated by mockery v2.22.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/aasumitro/tix/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobHandler is an autogenerated mock type for the JobHandler type
+type JobHandler struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx, message
+func (_m *JobHandler) Execute(ctx context.Context, message *entity.QueueMessage) error {
+	ret := _m.Called(ctx, message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.QueueMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewJobHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewJobHandler creates a new instance of JobHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewJobHandler(t mockConstructorTestingTNewJobHandler) *JobHandler {
+	mock := &JobHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
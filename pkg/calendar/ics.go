@@ -0,0 +1,109 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of the RFC 5545 calendar file.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	productID = "-//TIX//Event Ticketing//EN"
+	dateTime  = "20060102T150405Z"
+	date      = "20060102"
+	// lineLength is the max octets of a content line, the longer one is folded.
+	lineLength = 75
+)
+
+// Event is an all-day calendar event, the tix event only know its date.
+type Event struct {
+	// UID stay the same for the event, so importing it again update
+	// the calendar entry instead of adding another one.
+	UID            string
+	Summary        string
+	Description    string
+	Location       string
+	Date           time.Time
+	OrganizerName  string
+	OrganizerEmail string
+}
+
+// ICS encode the event as an RFC 5545 calendar (a single VEVENT).
+func (event *Event) ICS() []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + productID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:" + escape(event.UID),
+		"DTSTAMP:" + time.Now().UTC().Format(dateTime),
+		"DTSTART;VALUE=DATE:" + event.Date.Format(date),
+		"DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format(date),
+		"SUMMARY:" + escape(event.Summary),
+	}
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escape(event.Description))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+escape(event.Location))
+	}
+	if event.OrganizerEmail != "" {
+		lines = append(lines, fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s",
+			quote(event.OrganizerName), event.OrganizerEmail))
+	}
+	lines = append(lines,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR")
+
+	var ics strings.Builder
+	for _, line := range lines {
+		ics.WriteString(fold(line))
+		ics.WriteString("\r\n")
+	}
+	return []byte(ics.String())
+}
+
+// escape the TEXT value (RFC 5545 3.3.11).
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// quote the parameter value, it can not contain a double quote.
+func quote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// fold split the line longer than 75 octets, the continuation line start
+// with a space, a multi-byte character is never split.
+func fold(line string) string {
+	if len(line) <= lineLength {
+		return line
+	}
+	var folded strings.Builder
+	limit := lineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space count to the line length
+		limit = lineLength - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
@@ -0,0 +1,62 @@
+package calendar_test
+
+import (
+	"github.com/aasumitro/tix/pkg/calendar"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEvent_ICS(t *testing.T) {
+	event := &calendar.Event{
+		UID:            "event-1@tix.bakode.xyz",
+		Summary:        "Go Meetup; Gorontalo, 2023",
+		Description:    "Ticket code: 7K2M-QX4D-9PLA-ZR3T\nShow it at the gate",
+		Location:       "Jalan Tix",
+		Date:           time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+		OrganizerName:  "BAKODE SUPPORT",
+		OrganizerEmail: "support@bakode.xyz",
+	}
+	ics := string(event.ICS())
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "UID:event-1@tix.bakode.xyz\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20230630\r\n")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20230701\r\n")
+	assert.Contains(t, ics, `SUMMARY:Go Meetup\; Gorontalo\, 2023`+"\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Ticket code: 7K2M-QX4D-9PLA-ZR3T\nShow it at the gate`+"\r\n")
+	assert.Contains(t, ics, "LOCATION:Jalan Tix\r\n")
+	assert.Contains(t, ics, `ORGANIZER;CN="BAKODE SUPPORT":mailto:support@bakode.xyz`+"\r\n")
+}
+
+func TestEvent_ICS_FoldLongLine(t *testing.T) {
+	event := &calendar.Event{
+		UID:      "event-1@tix.bakode.xyz",
+		Summary:  strings.Repeat("Pertemuan Komunitas Gopher — ", 6),
+		Location: "",
+		Date:     time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	ics := string(event.ICS())
+	assert.NotContains(t, ics, "LOCATION")
+	assert.NotContains(t, ics, "ORGANIZER")
+
+	lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+	var summary strings.Builder
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, utf8.ValidString(line))
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary.WriteString(line)
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, " ") {
+					break
+				}
+				summary.WriteString(next[1:])
+			}
+		}
+	}
+	assert.Equal(t, "SUMMARY:"+event.Summary, summary.String())
+}
@@ -0,0 +1,75 @@
+// Package i18n translate the text of the exports, the tickets and the emails.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Locale is the language of the message catalogue, e.g. id or en.
+type Locale string
+
+const (
+	ID Locale = "id"
+	EN Locale = "en"
+)
+
+// Default is the locale of the event that does not choose one.
+const Default = ID
+
+// Locales list every locale that has a message catalogue.
+var Locales = []Locale{ID, EN}
+
+// Parse return the locale of the language tag, e.g. en-US is en,
+// ok is false when the language has no message catalogue.
+func Parse(tag string) (locale Locale, ok bool) {
+	language := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(language, "-_"); i != -1 {
+		language = language[:i]
+	}
+	_, ok = catalogues[Locale(language)]
+	return Locale(language), ok
+}
+
+// Of return the locale of the language tag, or the default locale.
+func Of(tag string) Locale {
+	if locale, ok := Parse(tag); ok {
+		return locale
+	}
+	return Default
+}
+
+// T translate the message and format it with the args like fmt.Sprintf.
+// The message missing from the catalogue fallback to the default locale,
+// the key is returned when no catalogue has it.
+func (locale Locale) T(key string, args ...interface{}) string {
+	message, ok := catalogues[locale][key]
+	if !ok {
+		if message, ok = catalogues[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Date format the date with the month name of the locale, e.g. 30 Juni 2023.
+func (locale Locale) Date(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), locale.month(t.Month()), t.Year())
+}
+
+// DateTime format the date and the time of the day, e.g. 30 Juni 2023 14:05 WITA.
+func (locale Locale) DateTime(t time.Time) string {
+	return fmt.Sprintf("%s %s", locale.Date(t), t.Format("15:04 MST"))
+}
+
+func (locale Locale) month(month time.Month) string {
+	names, ok := months[locale]
+	if !ok {
+		names = months[Default]
+	}
+	return names[month-1]
+}
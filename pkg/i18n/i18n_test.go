@@ -0,0 +1,43 @@
+package i18n_test
+
+import (
+	"github.com/aasumitro/tix/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for tag, want := range map[string]i18n.Locale{
+		"id":     i18n.ID,
+		"EN":     i18n.EN,
+		"en-US":  i18n.EN,
+		" id_ID": i18n.ID,
+	} {
+		locale, ok := i18n.Parse(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, want, locale, tag)
+	}
+	for _, tag := range []string{"", "fr", "english"} {
+		_, ok := i18n.Parse(tag)
+		assert.False(t, ok, tag)
+		assert.Equal(t, i18n.Default, i18n.Of(tag), tag)
+	}
+}
+
+func TestLocale_T(t *testing.T) {
+	assert.Equal(t, "Daftar Peserta", i18n.ID.T("export.participants"))
+	assert.Equal(t, "Participants", i18n.EN.T("export.participants"))
+	assert.Equal(t, "Ticket for Go Meetup", i18n.EN.T("email.ticket.subject", "Go Meetup"))
+	// the unknown locale fallback to the default one
+	assert.Equal(t, "Daftar Peserta", i18n.Locale("fr").T("export.participants"))
+	assert.Equal(t, "lorem.ipsum", i18n.EN.T("lorem.ipsum"))
+}
+
+func TestLocale_Date(t *testing.T) {
+	date := time.Date(2023, 6, 30, 14, 5, 0, 0, time.FixedZone("WITA", 8*60*60))
+	assert.Equal(t, "30 Juni 2023", i18n.ID.Date(date))
+	assert.Equal(t, "30 June 2023", i18n.EN.Date(date))
+	assert.Equal(t, "30 Juni 2023", i18n.Locale("fr").Date(date))
+	assert.Equal(t, "30 Juni 2023 14:05 WITA", i18n.ID.DateTime(date))
+}
@@ -0,0 +1,110 @@
+package i18n
+
+// months is the month names of each locale, January first.
+var months = map[Locale][12]string{
+	ID: {"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+		"Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	EN: {"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+}
+
+// catalogues is the message catalogue of each locale, every locale
+// must translate the same keys.
+var catalogues = map[Locale]map[string]string{
+	ID: {
+		// exports
+		"export.name":               "Nama",
+		"export.location":           "Lokasi",
+		"export.date":               "Tanggal",
+		"export.total_participants": "Total Peserta",
+		"export.totals":             "%d –– %d diterima | %d menunggu | %d ditolak | %d hadir ––",
+		"export.participants":       "Daftar Peserta",
+		"export.others":             "Lainnya",
+		"export.generated":          "Dibuat %s melalui %s",
+		"column.no":                 "No",
+		"column.name":               "Nama",
+		"column.email":              "Email",
+		"column.phone":              "No Telp.",
+		"column.job":                "Pekerjaan",
+		"column.dob":                "Tanggal Lahir",
+		"column.approved":           "Diterima",
+		"column.declined":           "Ditolak",
+		"column.declined_reason":    "Alasan Ditolak",
+		"column.checked_in":         "Hadir",
+		"column.status":             "Status",
+		"status.checked_in":         "hadir",
+		"status.approved":           "diterima",
+		"status.declined":           "ditolak",
+		"status.waiting":            "menunggu",
+		// tickets
+		"ticket.attendee":      "Peserta",
+		"ticket.email":         "Email",
+		"ticket.date":          "Tanggal",
+		"ticket.location":      "Lokasi",
+		"calendar.description": "Peserta: %s\nKode tiket: %s",
+		// emails
+		"email.greeting":               "Halo",
+		"email.signature":              "Salam hangat",
+		"email.trouble":                "Jika tombol '{ACTION}' tidak berfungsi, salin dan tempel URL di bawah ini ke peramban Anda.",
+		"email.ticket.subject":         "Tiket %s",
+		"email.ticket.intro":           "Terlampir tiket acara yang Anda minta!",
+		"email.ticket.wallet":          "Simpan tiket di dompet ponsel Anda:",
+		"email.ticket.wallet_button":   "Tambah ke Wallet",
+		"email.ticket.download":        "Tiket hilang? Unduh kembali sampai acara selesai:",
+		"email.ticket.download_button": "Unduh Tiket",
+		"email.export.subject":         "Ekspor Data %s dengan tipe %s",
+		"email.export.name":            "Pengguna Tix",
+		"email.export.intro":           "Terlampir ekspor data acara yang Anda minta. Terima kasih telah menggunakan aplikasi tix!",
+		"email.export.link_intro":      "Ekspor data acara yang Anda minta terlalu besar untuk dilampirkan. Terima kasih telah menggunakan aplikasi tix!",
+		"email.export.link":            "Unduh ekspor sebelum %s:",
+		"email.export.download_button": "Unduh Ekspor",
+	},
+	EN: {
+		// exports
+		"export.name":               "Name",
+		"export.location":           "Location",
+		"export.date":               "Date",
+		"export.total_participants": "Total Participants",
+		"export.totals":             "%d –– %d approved | %d waiting | %d declined | %d checked in ––",
+		"export.participants":       "Participants",
+		"export.others":             "Others",
+		"export.generated":          "Generated %s via %s",
+		"column.no":                 "No",
+		"column.name":               "Name",
+		"column.email":              "Email",
+		"column.phone":              "Phone",
+		"column.job":                "Job",
+		"column.dob":                "Date of Birth",
+		"column.approved":           "Approved",
+		"column.declined":           "Declined",
+		"column.declined_reason":    "Declined Reason",
+		"column.checked_in":         "Checked In",
+		"column.status":             "Status",
+		"status.checked_in":         "checked in",
+		"status.approved":           "approved",
+		"status.declined":           "declined",
+		"status.waiting":            "waiting",
+		// tickets
+		"ticket.attendee":      "Attendee",
+		"ticket.email":         "Email",
+		"ticket.date":          "Date",
+		"ticket.location":      "Location",
+		"calendar.description": "Attendee: %s\nTicket code: %s",
+		// emails
+		"email.greeting":               "Hi",
+		"email.signature":              "Yours truly",
+		"email.trouble":                "If you’re having trouble with the button '{ACTION}', copy and paste the URL below into your web browser.",
+		"email.ticket.subject":         "Ticket for %s",
+		"email.ticket.intro":           "Please find attached the requested ticket of event!",
+		"email.ticket.wallet":          "Keep the ticket in your phone wallet:",
+		"email.ticket.wallet_button":   "Add to Wallet",
+		"email.ticket.download":        "Lost the ticket? Download it again until the event is over:",
+		"email.ticket.download_button": "Download Ticket",
+		"email.export.subject":         "Export Data for %s with type %s",
+		"email.export.name":            "Tix User",
+		"email.export.intro":           "Please find attached the requested export of event data. Thank you for using tix app.!",
+		"email.export.link_intro":      "The requested export of event data is too large to be attached. Thank you for using tix app.!",
+		"email.export.link":            "Download the export before %s:",
+		"email.export.download_button": "Download Export",
+	},
+}
@@ -0,0 +1,16 @@
+package i18n
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCatalogues_TranslateSameKeys(t *testing.T) {
+	for _, locale := range Locales {
+		assert.Len(t, catalogues[locale], len(catalogues[Default]), locale)
+		for key := range catalogues[Default] {
+			assert.Contains(t, catalogues[locale], key, locale)
+		}
+		assert.Contains(t, months, locale)
+	}
+}
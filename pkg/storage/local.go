@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	localDirMode  = 0o700
+	localFileMode = 0o600
+)
+
+// LocalFileStore keep the files in a local directory, it is only shared
+// by the app instances mounting the same directory.
+type LocalFileStore struct {
+	dir string
+}
+
+func NewLocalFileStore(dir string) *LocalFileStore {
+	return &LocalFileStore{dir: dir}
+}
+
+func (store *LocalFileStore) Put(
+	_ context.Context,
+	key string,
+	r io.Reader,
+	_ int64,
+	_ string,
+) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), localDirMode); err != nil {
+		return err
+	}
+	// write aside then rename, so the file is never read half written
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Chmod(localFileMode); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (store *LocalFileStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (store *LocalFileStore) Delete(_ context.Context, key string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL is not supported, the local files are served by the app.
+func (store *LocalFileStore) SignedURL(context.Context, string, time.Duration) (string, error) {
+	return "", ErrSignedURLNotSupported
+}
+
+func (store *LocalFileStore) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(store.dir, filepath.FromSlash(key)), nil
+}
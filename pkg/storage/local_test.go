@@ -0,0 +1,54 @@
+package storage_test
+
+import (
+	"context"
+	"github.com/aasumitro/tix/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalFileStore(dir)
+	ctx := context.TODO()
+
+	assert.NoError(t, store.Put(ctx, "exports/asd/1.csv", strings.NewReader("asd"), 3, "text/csv"))
+	info, err := os.Stat(filepath.Join(dir, "exports", "asd", "1.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	// the file of the same key is replaced
+	assert.NoError(t, store.Put(ctx, "exports/asd/1.csv", strings.NewReader("lorem"), 5, "text/csv"))
+
+	file, err := store.Open(ctx, "exports/asd/1.csv")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(file)
+	_ = file.Close()
+	assert.Equal(t, "lorem", string(data))
+	// the upload is not left behind
+	entries, _ := os.ReadDir(filepath.Join(dir, "exports", "asd"))
+	assert.Len(t, entries, 1)
+
+	_, err = store.SignedURL(ctx, "exports/asd/1.csv", time.Hour)
+	assert.ErrorIs(t, err, storage.ErrSignedURLNotSupported)
+
+	assert.NoError(t, store.Delete(ctx, "exports/asd/1.csv"))
+	assert.NoError(t, store.Delete(ctx, "exports/asd/1.csv"))
+	_, err = store.Open(ctx, "exports/asd/1.csv")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestLocalFileStore_InvalidKey(t *testing.T) {
+	store := storage.NewLocalFileStore(t.TempDir())
+	ctx := context.TODO()
+	for _, key := range []string{"", "/etc/passwd", "../secret", "exports/../../secret", "exports//1.csv", `exports\1.csv`} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("asd"), 3, ""), storage.ErrInvalidKey, key)
+		_, err := store.Open(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+		assert.ErrorIs(t, store.Delete(ctx, key), storage.ErrInvalidKey, key)
+	}
+}
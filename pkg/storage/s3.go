@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3DateFormat     = "20060102T150405Z"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3EmptyBodyHash  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3MaxErrorLength = 1 << 10
+	// S3MaxSignedURLExpiry is the longest expiry of the signed url (sigv4 limit)
+	S3MaxSignedURLExpiry = 7 * 24 * time.Hour
+	// S3DefaultTimeout bound the request to the store when no timeout is configured
+	S3DefaultTimeout = time.Minute
+)
+
+var ErrInvalidExpiry = errors.New("signed url expiry must be between 1 second and 7 days")
+
+// S3Config is the bucket of the S3 compatible store, e.g. AWS S3 or MinIO.
+type S3Config struct {
+	// Endpoint is the base url of the store, e.g. https://s3.ap-southeast-1.amazonaws.com
+	// or http://127.0.0.1:9000 for MinIO.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle put the bucket in the path (endpoint/bucket/key) instead of the
+	// host (bucket.endpoint/key), MinIO and most self-hosted stores need it.
+	PathStyle bool
+	// Timeout bound the upload and delete requests, the download only wait
+	// the response header for it, so the streamed body is not cut off.
+	// S3DefaultTimeout is used when it is not set.
+	Timeout time.Duration
+}
+
+// S3FileStore keep the files in a bucket of the S3 compatible store,
+// the requests are signed with the AWS signature version 4.
+type S3FileStore struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	// streamClient open the file streamed to the caller, it has no overall timeout
+	streamClient *http.Client
+	now          func() time.Time
+}
+
+func NewS3FileStore(cfg S3Config) (*S3FileStore, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q must be an absolute url", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("s3 bucket and region are required")
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = S3DefaultTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	return &S3FileStore{
+		endpoint:     endpoint,
+		region:       cfg.Region,
+		bucket:       cfg.Bucket,
+		accessKey:    cfg.AccessKey,
+		secretKey:    cfg.SecretKey,
+		pathStyle:    cfg.PathStyle,
+		client:       &http.Client{Transport: transport, Timeout: timeout},
+		streamClient: &http.Client{Transport: transport},
+		now:          time.Now,
+	}, nil
+}
+
+// Put upload the file without hashing it first, so it is streamed once.
+func (store *S3FileStore) Put(
+	ctx context.Context,
+	key string,
+	r io.Reader,
+	size int64,
+	contentType string,
+) error {
+	if size < 0 {
+		return errors.New("s3 upload need the file size")
+	}
+	req, err := store.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	store.signRequest(req, s3UnsignedBody)
+	resp, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return newS3Error(req, resp)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (store *S3FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := store.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	store.signRequest(req, s3EmptyBodyHash)
+	resp, err := store.streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, newS3Error(req, resp)
+	}
+	return resp.Body, nil
+}
+
+func (store *S3FileStore) Delete(ctx context.Context, key string) error {
+	req, err := store.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	store.signRequest(req, s3EmptyBodyHash)
+	resp, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return newS3Error(req, resp)
+}
+
+// SignedURL presign the download url, it is valid until the expiry
+// even when the credential is not given to the downloader.
+func (store *S3FileStore) SignedURL(_ context.Context, key string, expiry time.Duration) (string, error) {
+	if expiry < time.Second || expiry > S3MaxSignedURLExpiry {
+		return "", ErrInvalidExpiry
+	}
+	objectURL, err := store.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	amzDate := store.now().UTC().Format(s3DateFormat)
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", store.accessKey+"/"+store.scope(amzDate))
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiry/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonical, _ := canonicalS3Request(http.MethodGet, objectURL.EscapedPath(), query,
+		map[string]string{"host": objectURL.Host}, s3UnsignedBody)
+	query.Set("X-Amz-Signature", store.signature(amzDate, canonical))
+	objectURL.RawQuery = canonicalS3Query(query)
+	return objectURL.String(), nil
+}
+
+func (store *S3FileStore) newRequest(
+	ctx context.Context,
+	method, key string,
+	body io.Reader,
+) (*http.Request, error) {
+	objectURL, err := store.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+}
+
+// objectURL is the url of the file, the path is escaped the way it is signed.
+func (store *S3FileStore) objectURL(key string) (*url.URL, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	objectURL := *store.endpoint
+	path := "/" + key
+	if store.pathStyle {
+		path = "/" + store.bucket + path
+	} else {
+		objectURL.Host = store.bucket + "." + objectURL.Host
+	}
+	objectURL.Path = strings.TrimSuffix(store.endpoint.Path, "/") + path
+	objectURL.RawPath = s3Escape(objectURL.Path, false)
+	return &objectURL, nil
+}
+
+// signRequest add the authorization header signing the host and the x-amz headers.
+func (store *S3FileStore) signRequest(req *http.Request, payloadHash string) {
+	amzDate := store.now().UTC().Format(s3DateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(req.Header.Values(name), ","))
+	}
+	canonical, signedHeaders := canonicalS3Request(req.Method, req.URL.EscapedPath(),
+		req.URL.Query(), headers, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, store.accessKey, store.scope(amzDate), signedHeaders, store.signature(amzDate, canonical)))
+}
+
+func (store *S3FileStore) scope(amzDate string) string {
+	return strings.Join([]string{amzDate[:8], store.region, s3Service, "aws4_request"}, "/")
+}
+
+// signature sign the canonical request with the key derived from the secret.
+func (store *S3FileStore) signature(amzDate, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm, amzDate, store.scope(amzDate), hex.EncodeToString(hash[:]),
+	}, "\n")
+	key := []byte("AWS4" + store.secretKey)
+	for _, part := range []string{amzDate[:8], store.region, s3Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalS3Request return the canonical request and the signed header
+// names, the headers are keyed by their lower case name.
+func canonicalS3Request(
+	method, escapedPath string,
+	query url.Values,
+	headers map[string]string,
+	payloadHash string,
+) (canonical, signedHeaders string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	return strings.Join([]string{
+		method, escapedPath, canonicalS3Query(query),
+		canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n"), signedHeaders
+}
+
+func canonicalS3Query(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(name, true)+"="+s3Escape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent encode every byte but the unreserved characters (and
+// the slash of the path), as the signature version 4 require.
+func s3Escape(value string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !escapeSlash:
+			escaped.WriteByte(c)
+		default:
+			escaped.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	return escaped.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// S3Error is the error response of the store, e.g. AccessDenied.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (err *S3Error) Error() string {
+	if err.Code == "" {
+		return fmt.Sprintf("s3 request failed with status %d", err.StatusCode)
+	}
+	return fmt.Sprintf("s3 request failed with status %d: %s %s", err.StatusCode, err.Code, err.Message)
+}
+
+func newS3Error(req *http.Request, resp *http.Response) error {
+	s3Err := &S3Error{StatusCode: resp.StatusCode}
+	if req.Method != http.MethodHead {
+		_ = xml.NewDecoder(io.LimitReader(resp.Body, s3MaxErrorLength)).Decode(s3Err)
+	}
+	return s3Err
+}
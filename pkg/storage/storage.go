@@ -0,0 +1,44 @@
+// Package storage keep the generated files (e.g. the event exports) in a
+// file store shared by every app instance, the local directory or an S3
+// compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound              = errors.New("file is not found")
+	ErrInvalidKey            = errors.New("file key is not valid")
+	ErrSignedURLNotSupported = errors.New("file store can not sign the download url")
+)
+
+// FileStore keep the files by their slash separated key, e.g. exports/form/1.pdf.
+type FileStore interface {
+	// Put write the file of the given size, it replaces the file of the same key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open read the file, it returns ErrNotFound when there is no file of the key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL return the link to download the file without credential until it
+	// expires, it returns ErrSignedURLNotSupported when the store can not sign it,
+	// so the app serve the file itself.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// validKey reject the empty key and the key escaping its directory, e.g. ../secret.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
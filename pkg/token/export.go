@@ -0,0 +1,57 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+var (
+	ErrInvalidExportDownload = errors.New("invalid export download token")
+	ErrExpiredExportDownload = errors.New("export download token is expired")
+)
+
+// exportDownloadAudience tell the token of the export download link
+// apart from the ticket tokens signed with the same secret.
+const exportDownloadAudience = "export-download"
+
+// SignExportDownload sign the token of the link to download the archived
+// export, the subject is the key of the export in the file store.
+func SignExportDownload(
+	key ed25519.PrivateKey,
+	fileKey string,
+	expiredAt time.Time,
+) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{
+		Subject:   fileKey,
+		Audience:  jwt.ClaimStrings{exportDownloadAudience},
+		ExpiresAt: jwt.NewNumericDate(expiredAt),
+	}).SignedString(key)
+}
+
+// VerifyExportDownload verify the token of the export download link
+// and return the key of the export in the file store.
+func VerifyExportDownload(
+	publicKey ed25519.PublicKey,
+	downloadToken string,
+	at time.Time,
+) (fileKey string, err error) {
+	claim := &jwt.RegisteredClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(downloadToken, claim, func(*jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidExportDownload, err.Error())
+	}
+	if !claim.VerifyAudience(exportDownloadAudience, true) || claim.Subject == "" {
+		return "", fmt.Errorf("%w: token has wrong audience", ErrInvalidExportDownload)
+	}
+	if !claim.VerifyExpiresAt(at, true) {
+		return "", ErrExpiredExportDownload
+	}
+	return claim.Subject, nil
+}
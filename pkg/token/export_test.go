@@ -0,0 +1,34 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"github.com/aasumitro/tix/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExport_SignAndVerifyDownload(t *testing.T) {
+	key := token.NewTicketKey("secret", "export-download")
+	publicKey := key.Public().(ed25519.PublicKey)
+	downloadToken, err := token.SignExportDownload(key, "exports/form-1/1.pdf", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	fileKey, err := token.VerifyExportDownload(publicKey, downloadToken, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "exports/form-1/1.pdf", fileKey)
+
+	_, err = token.VerifyExportDownload(publicKey, downloadToken, time.Now().Add(2*time.Hour))
+	assert.ErrorIs(t, err, token.ErrExpiredExportDownload)
+
+	_, err = token.VerifyExportDownload(
+		token.NewTicketKey("secret", "form-1").Public().(ed25519.PublicKey), downloadToken, time.Now())
+	assert.ErrorIs(t, err, token.ErrInvalidExportDownload)
+
+	// the ticket download token can not download the export
+	ticketToken, _ := token.SignTicketDownload(key, 1, 2, time.Now().Add(time.Hour))
+	_, err = token.VerifyExportDownload(publicKey, ticketToken, time.Now())
+	assert.ErrorIs(t, err, token.ErrInvalidExportDownload)
+	_, err = token.VerifyExportDownload(publicKey, "lorem", time.Now())
+	assert.ErrorIs(t, err, token.ErrInvalidExportDownload)
+}
@@ -0,0 +1,124 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"time"
+)
+
+var (
+	ErrInvalidTicket = errors.New("invalid ticket token")
+	ErrExpiredTicket = errors.New("ticket token is expired")
+)
+
+// TicketAlgorithm is the signing algorithm of the ticket token,
+// the scanner need it to verify the token offline.
+var TicketAlgorithm = jwt.SigningMethodEdDSA.Alg()
+
+// ticketDownloadAudience tell the token of the ticket download link
+// apart from the ticket token encoded in the ticket QR code.
+const ticketDownloadAudience = "ticket-download"
+
+// TicketClaim is the payload of the ticket token encoded in the ticket QR code.
+type TicketClaim struct {
+	EventID       int32 `json:"eid"`
+	ParticipantID int32 `json:"pid"`
+	jwt.RegisteredClaims
+}
+
+// NewTicketKey derive the ed25519 key of the given key id from the secret,
+// so every app instance sign the tickets with the same key without storing it.
+func NewTicketKey(secret, keyID string) ed25519.PrivateKey {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(keyID))
+	return ed25519.NewKeyFromSeed(mac.Sum(nil))
+}
+
+func SignTicket(
+	key ed25519.PrivateKey,
+	eventID, participantID int32,
+	expiredAt time.Time,
+) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, TicketClaim{
+		EventID:       eventID,
+		ParticipantID: participantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiredAt),
+		},
+	}).SignedString(key)
+}
+
+// ParseTicket read the ticket claim without verifying it, it is only
+// used to tell which event the ticket belongs to.
+func ParseTicket(ticket string) (claim *TicketClaim, err error) {
+	claim = &TicketClaim{}
+	if _, _, err := jwt.NewParser().ParseUnverified(ticket, claim); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTicket, err.Error())
+	}
+	return claim, nil
+}
+
+// SignTicketDownload sign the token of the link to download the ticket,
+// it can not be used as the ticket token and the other way around.
+func SignTicketDownload(
+	key ed25519.PrivateKey,
+	eventID, participantID int32,
+	expiredAt time.Time,
+) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, TicketClaim{
+		EventID:       eventID,
+		ParticipantID: participantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{ticketDownloadAudience},
+			ExpiresAt: jwt.NewNumericDate(expiredAt),
+		},
+	}).SignedString(key)
+}
+
+// VerifyTicket verify the ticket signature and expiry at the given time,
+// e.g. when the ticket was scanned by an offline gate.
+func VerifyTicket(
+	publicKey ed25519.PublicKey,
+	ticket string,
+	at time.Time,
+) (claim *TicketClaim, err error) {
+	return verifyTicket(publicKey, ticket, at, "")
+}
+
+// VerifyTicketDownload verify the token of the link to download the ticket.
+func VerifyTicketDownload(
+	publicKey ed25519.PublicKey,
+	downloadToken string,
+	at time.Time,
+) (claim *TicketClaim, err error) {
+	return verifyTicket(publicKey, downloadToken, at, ticketDownloadAudience)
+}
+
+func verifyTicket(
+	publicKey ed25519.PublicKey,
+	ticket string,
+	at time.Time,
+	audience string,
+) (claim *TicketClaim, err error) {
+	claim = &TicketClaim{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{TicketAlgorithm}),
+		jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(ticket, claim, func(*jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTicket, err.Error())
+	}
+	if (audience == "" && len(claim.Audience) > 0) ||
+		(audience != "" && !claim.VerifyAudience(audience, true)) {
+		return nil, fmt.Errorf("%w: token has wrong audience", ErrInvalidTicket)
+	}
+	if !claim.VerifyExpiresAt(at, true) {
+		return nil, ErrExpiredTicket
+	}
+	return claim, nil
+}
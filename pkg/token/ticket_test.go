@@ -0,0 +1,103 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"github.com/aasumitro/tix/pkg/token"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTicket_SignAndVerify(t *testing.T) {
+	key := token.NewTicketKey("secret", "form-1")
+	// the key is derived, not random
+	assert.Equal(t, key, token.NewTicketKey("secret", "form-1"))
+	assert.NotEqual(t, key, token.NewTicketKey("secret", "form-2"))
+
+	expiredAt := time.Now().Add(time.Hour)
+	ticket, err := token.SignTicket(key, 1, 2, expiredAt)
+	assert.NoError(t, err)
+
+	claim, err := token.ParseTicket(ticket)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), claim.EventID)
+	assert.Equal(t, int32(2), claim.ParticipantID)
+
+	claim, err = token.VerifyTicket(key.Public().(ed25519.PublicKey), ticket, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), claim.ParticipantID)
+	assert.Equal(t, expiredAt.Unix(), claim.ExpiresAt.Unix())
+}
+
+func TestTicket_Verify_ShouldError(t *testing.T) {
+	key := token.NewTicketKey("secret", "form-1")
+	ticket, _ := token.SignTicket(key, 1, 2, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		ticket    string
+		at        time.Time
+		wantErr   error
+	}{
+		{
+			name:      "expired",
+			publicKey: key.Public().(ed25519.PublicKey),
+			ticket:    ticket,
+			at:        time.Now().Add(2 * time.Hour),
+			wantErr:   token.ErrExpiredTicket,
+		},
+		{
+			name:      "signed by another key",
+			publicKey: token.NewTicketKey("secret", "form-2").Public().(ed25519.PublicKey),
+			ticket:    ticket,
+			at:        time.Now(),
+			wantErr:   token.ErrInvalidTicket,
+		},
+		{
+			name:      "malformed",
+			publicKey: key.Public().(ed25519.PublicKey),
+			ticket:    "7K2M-QX4D-9PLA-ZR3T",
+			at:        time.Now(),
+			wantErr:   token.ErrInvalidTicket,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim, err := token.VerifyTicket(tt.publicKey, tt.ticket, tt.at)
+			assert.Nil(t, claim)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	claim, err := token.ParseTicket("lorem")
+	assert.Nil(t, claim)
+	assert.ErrorIs(t, err, token.ErrInvalidTicket)
+}
+
+func TestTicket_SignAndVerifyDownload(t *testing.T) {
+	key := token.NewTicketKey("secret", "form-1")
+	publicKey := key.Public().(ed25519.PublicKey)
+	expiredAt := time.Now().Add(time.Hour)
+	downloadToken, err := token.SignTicketDownload(key, 1, 2, expiredAt)
+	assert.NoError(t, err)
+
+	claim, err := token.VerifyTicketDownload(publicKey, downloadToken, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), claim.EventID)
+	assert.Equal(t, int32(2), claim.ParticipantID)
+	assert.Equal(t, expiredAt.Unix(), claim.ExpiresAt.Unix())
+
+	claim, err = token.VerifyTicketDownload(publicKey, downloadToken, time.Now().Add(2*time.Hour))
+	assert.Nil(t, claim)
+	assert.ErrorIs(t, err, token.ErrExpiredTicket)
+
+	// the download token is not a ticket and the other way around
+	claim, err = token.VerifyTicket(publicKey, downloadToken, time.Now())
+	assert.Nil(t, claim)
+	assert.ErrorIs(t, err, token.ErrInvalidTicket)
+	ticket, _ := token.SignTicket(key, 1, 2, expiredAt)
+	claim, err = token.VerifyTicketDownload(publicKey, ticket, time.Now())
+	assert.Nil(t, claim)
+	assert.ErrorIs(t, err, token.ErrInvalidTicket)
+}
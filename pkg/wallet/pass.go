@@ -0,0 +1,294 @@
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/sha1" //nolint:gosec // the pass manifest is a sha1 digest by spec
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go.mozilla.org/pkcs7"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"time"
+)
+
+// ContentType is the media type of the pass bundle, the phone open
+// the wallet app when the file is downloaded with it.
+const ContentType = "application/vnd.apple.pkpass"
+
+const (
+	passFormatVersion = 1
+	iconSize          = 29
+	iconShade         = 34
+)
+
+var ErrInvalidPEM = errors.New("no pem block found")
+
+// Signer sign the pass bundle with the pass type certificate,
+// the intermediate is the apple wwdr certificate that issued it.
+type Signer struct {
+	PassTypeID   string
+	TeamID       string
+	Organization string
+
+	certificate  *x509.Certificate
+	privateKey   crypto.PrivateKey
+	intermediate *x509.Certificate
+}
+
+// EventTicket is the content of the event ticket pass.
+type EventTicket struct {
+	SerialNumber string
+	EventName    string
+	EventDate    time.Time
+	Location     string
+	AttendeeName string
+	TicketCode   string
+	// BarcodeMessage is the content of the QR code, the ticket code is
+	// shown under it so the door staff can type it when the scan fail.
+	BarcodeMessage string
+}
+
+type passFile struct {
+	name    string
+	content []byte
+}
+
+type passField struct {
+	Key       string `json:"key"`
+	Label     string `json:"label"`
+	Value     string `json:"value"`
+	DateStyle string `json:"dateStyle,omitempty"`
+	TimeStyle string `json:"timeStyle,omitempty"`
+}
+
+type passBarcode struct {
+	Format          string `json:"format"`
+	Message         string `json:"message"`
+	MessageEncoding string `json:"messageEncoding"`
+	AltText         string `json:"altText"`
+}
+
+type passStructure struct {
+	PrimaryFields   []passField `json:"primaryFields"`
+	SecondaryFields []passField `json:"secondaryFields"`
+	AuxiliaryFields []passField `json:"auxiliaryFields"`
+	BackFields      []passField `json:"backFields"`
+}
+
+type pass struct {
+	FormatVersion      int           `json:"formatVersion"`
+	PassTypeIdentifier string        `json:"passTypeIdentifier"`
+	SerialNumber       string        `json:"serialNumber"`
+	TeamIdentifier     string        `json:"teamIdentifier"`
+	OrganizationName   string        `json:"organizationName"`
+	Description        string        `json:"description"`
+	RelevantDate       string        `json:"relevantDate"`
+	Barcodes           []passBarcode `json:"barcodes"`
+	EventTicket        passStructure `json:"eventTicket"`
+}
+
+// Generate build the signed .pkpass bundle (a zip archive) of the ticket.
+func (signer *Signer) Generate(ticket *EventTicket) ([]byte, error) {
+	passJSON, err := json.Marshal(signer.pass(ticket))
+	if err != nil {
+		return nil, err
+	}
+
+	icon1x, err := icon(iconSize)
+	if err != nil {
+		return nil, err
+	}
+	icon2x, err := icon(2 * iconSize)
+	if err != nil {
+		return nil, err
+	}
+	files := []*passFile{
+		{name: "pass.json", content: passJSON},
+		{name: "icon.png", content: icon1x},
+		{name: "icon@2x.png", content: icon2x},
+	}
+
+	manifest := make(map[string]string, len(files))
+	for _, file := range files {
+		digest := sha1.Sum(file.content) //nolint:gosec // required by the pass format
+		manifest[file.name] = hex.EncodeToString(digest[:])
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.sign(manifestJSON)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files,
+		&passFile{name: "manifest.json", content: manifestJSON},
+		&passFile{name: "signature", content: signature})
+
+	buf := new(bytes.Buffer)
+	archive := zip.NewWriter(buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (signer *Signer) pass(ticket *EventTicket) *pass {
+	return &pass{
+		FormatVersion:      passFormatVersion,
+		PassTypeIdentifier: signer.PassTypeID,
+		SerialNumber:       ticket.SerialNumber,
+		TeamIdentifier:     signer.TeamID,
+		OrganizationName:   signer.Organization,
+		Description:        fmt.Sprintf("Ticket for %s", ticket.EventName),
+		RelevantDate:       ticket.EventDate.Format(time.RFC3339),
+		Barcodes: []passBarcode{{
+			Format:          "PKBarcodeFormatQR",
+			Message:         ticket.BarcodeMessage,
+			MessageEncoding: "iso-8859-1",
+			AltText:         ticket.TicketCode,
+		}},
+		EventTicket: passStructure{
+			PrimaryFields: []passField{
+				{Key: "event", Label: "EVENT", Value: ticket.EventName},
+			},
+			SecondaryFields: []passField{
+				{Key: "date", Label: "DATE", Value: ticket.EventDate.Format(time.RFC3339),
+					DateStyle: "PKDateStyleMedium", TimeStyle: "PKDateStyleNone"},
+				{Key: "location", Label: "LOCATION", Value: ticket.Location},
+			},
+			AuxiliaryFields: []passField{
+				{Key: "attendee", Label: "ATTENDEE", Value: ticket.AttendeeName},
+			},
+			BackFields: []passField{
+				{Key: "ticket_code", Label: "TICKET CODE", Value: ticket.TicketCode},
+			},
+		},
+	}
+}
+
+// sign return the detached pkcs7 signature of the manifest.
+func (signer *Signer) sign(manifest []byte) ([]byte, error) {
+	signedData, err := pkcs7.NewSignedData(manifest)
+	if err != nil {
+		return nil, err
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	var parents []*x509.Certificate
+	if signer.intermediate != nil {
+		parents = append(parents, signer.intermediate)
+	}
+	if err := signedData.AddSignerChain(
+		signer.certificate, signer.privateKey, parents, pkcs7.SignerInfoConfig{},
+	); err != nil {
+		return nil, err
+	}
+	signedData.Detach()
+	return signedData.Finish()
+}
+
+// icon draw the plain icon required by the wallet app.
+func icon(size int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: iconShade, G: iconShade, B: iconShade, A: math.MaxUint8}},
+		image.Point{}, draw.Src)
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func NewSigner(
+	passTypeID, teamID, organization string,
+	certificate *x509.Certificate,
+	privateKey crypto.PrivateKey,
+	intermediate *x509.Certificate,
+) *Signer {
+	return &Signer{
+		PassTypeID:   passTypeID,
+		TeamID:       teamID,
+		Organization: organization,
+		certificate:  certificate,
+		privateKey:   privateKey,
+		intermediate: intermediate,
+	}
+}
+
+// LoadSigner read the pem encoded pass type certificate, its private key
+// and the wwdr intermediate certificate (optional) from the disk.
+func LoadSigner(
+	passTypeID, teamID, organization string,
+	certificatePath, privateKeyPath, intermediatePath string,
+) (*Signer, error) {
+	certificate, err := loadCertificate(certificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("pass certificate: %w", err)
+	}
+	privateKey, err := loadPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("pass private key: %w", err)
+	}
+	var intermediate *x509.Certificate
+	if intermediatePath != "" {
+		if intermediate, err = loadCertificate(intermediatePath); err != nil {
+			return nil, fmt.Errorf("pass intermediate certificate: %w", err)
+		}
+	}
+	return NewSigner(passTypeID, teamID, organization,
+		certificate, privateKey, intermediate), nil
+}
+
+func loadPEM(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	return block, nil
+}
+
+func loadCertificate(path string) (*x509.Certificate, error) {
+	block, err := loadPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func loadPrivateKey(path string) (crypto.PrivateKey, error) {
+	block, err := loadPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
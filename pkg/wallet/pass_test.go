@@ -0,0 +1,148 @@
+package wallet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // the pass manifest is a sha1 digest by spec
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/aasumitro/tix/pkg/wallet"
+	"github.com/stretchr/testify/assert"
+	"go.mozilla.org/pkcs7"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	privateKey  *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate) *testCertificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	issuer, issuerKey := template, privateKey
+	if parent != nil {
+		issuer, issuerKey = parent.certificate, parent.privateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &privateKey.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCertificate{certificate: certificate, privateKey: privateKey}
+}
+
+func readPass(t *testing.T, pass []byte) map[string][]byte {
+	archive, err := zip.NewReader(bytes.NewReader(pass), int64(len(pass)))
+	assert.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		_ = r.Close()
+		files[file.Name] = content
+	}
+	return files
+}
+
+func TestSigner_Generate(t *testing.T) {
+	wwdr := newTestCertificate(t, "WWDR", nil)
+	passType := newTestCertificate(t, "Pass Type ID: pass.id.tix", wwdr)
+	signer := wallet.NewSigner("pass.id.tix", "TEAM123", "TIX",
+		passType.certificate, passType.privateKey, wwdr.certificate)
+
+	pass, err := signer.Generate(&wallet.EventTicket{
+		SerialNumber:   "7K2M-QX4D-9PLA-ZR3T",
+		EventName:      "lorem",
+		EventDate:      time.Date(2023, 6, 10, 9, 0, 0, 0, time.UTC),
+		Location:       "ipsum",
+		AttendeeName:   "dolor",
+		TicketCode:     "7K2M-QX4D-9PLA-ZR3T",
+		BarcodeMessage: "a.b.c",
+	})
+	assert.NoError(t, err)
+
+	files := readPass(t, pass)
+	for _, name := range []string{"pass.json", "icon.png", "icon@2x.png", "manifest.json", "signature"} {
+		assert.Contains(t, files, name)
+	}
+
+	var content map[string]any
+	assert.NoError(t, json.Unmarshal(files["pass.json"], &content))
+	assert.Equal(t, "pass.id.tix", content["passTypeIdentifier"])
+	assert.Equal(t, "TEAM123", content["teamIdentifier"])
+	assert.Equal(t, "2023-06-10T09:00:00Z", content["relevantDate"])
+	barcode := content["barcodes"].([]any)[0].(map[string]any)
+	assert.Equal(t, "a.b.c", barcode["message"])
+	assert.Equal(t, "7K2M-QX4D-9PLA-ZR3T", barcode["altText"])
+
+	var manifest map[string]string
+	assert.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Len(t, manifest, 3)
+	for name, digest := range manifest {
+		sum := sha1.Sum(files[name]) //nolint:gosec // required by the pass format
+		assert.Equal(t, hex.EncodeToString(sum[:]), digest)
+	}
+
+	signature, err := pkcs7.Parse(files["signature"])
+	assert.NoError(t, err)
+	signature.Content = files["manifest.json"]
+	assert.NoError(t, signature.Verify())
+	assert.Len(t, signature.Certificates, 2)
+}
+
+func TestLoadSigner(t *testing.T) {
+	dir := t.TempDir()
+	passType := newTestCertificate(t, "Pass Type ID: pass.id.tix", nil)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(passType.privateKey)
+	assert.NoError(t, err)
+	certificatePath := filepath.Join(dir, "pass.pem")
+	privateKeyPath := filepath.Join(dir, "pass.key")
+	assert.NoError(t, os.WriteFile(certificatePath, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: passType.certificate.Raw,
+	}), 0o600))
+	assert.NoError(t, os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{
+		Type: "PRIVATE KEY", Bytes: keyDER,
+	}), 0o600))
+
+	signer, err := wallet.LoadSigner("pass.id.tix", "TEAM123", "TIX",
+		certificatePath, privateKeyPath, "")
+	assert.NoError(t, err)
+	_, err = signer.Generate(&wallet.EventTicket{SerialNumber: "lorem"})
+	assert.NoError(t, err)
+
+	_, err = wallet.LoadSigner("pass.id.tix", "TEAM123", "TIX",
+		filepath.Join(dir, "missing.pem"), privateKeyPath, "")
+	assert.Error(t, err)
+
+	_, err = wallet.LoadSigner("pass.id.tix", "TEAM123", "TIX",
+		certificatePath, privateKeyPath, privateKeyPath)
+	assert.Error(t, err)
+
+	notPEM := filepath.Join(dir, "lorem.txt")
+	assert.NoError(t, os.WriteFile(notPEM, []byte("lorem"), 0o600))
+	_, err = wallet.LoadSigner("pass.id.tix", "TEAM123", "TIX",
+		certificatePath, notPEM, "")
+	assert.ErrorIs(t, err, wallet.ErrInvalidPEM)
+}